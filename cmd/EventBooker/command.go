@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/service"
+)
+
+// runCommand runs a maintenance subcommand instead of the server:
+//
+//	EventBooker admin -email admin@example.com [-password secret]
+func runCommand(services *service.Services, args []string) error {
+	switch args[0] {
+	case "admin":
+		return runAdmin(services.User, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, available: admin", args[0])
+	}
+}
+
+// runAdmin promotes an existing account to admin or creates a new admin
+// account. The password is only used for a new account and may be passed in
+// ADMIN_PASSWORD to keep it out of the shell history.
+func runAdmin(users service.UserService, args []string) error {
+	fs := flag.NewFlagSet("admin", flag.ContinueOnError)
+	email := fs.String("email", "", "email of the account to create or promote")
+	password := fs.String("password", os.Getenv("ADMIN_PASSWORD"), "password of a new account")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("admin: -email is required")
+	}
+
+	created, err := users.EnsureAdmin(context.Background(), *email, *password)
+	if err != nil {
+		return fmt.Errorf("admin: %w", err)
+	}
+
+	if created {
+		zlog.Logger.Info().Msgf("admin account %s created", *email)
+	} else {
+		zlog.Logger.Info().Msgf("account %s is an admin", *email)
+	}
+	return nil
+}
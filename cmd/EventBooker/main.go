@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -36,23 +37,52 @@ func main() {
 		ConnMaxLifetime: 10 * time.Minute,
 	})
 
-	tgbot := service.NewTelegramBot(c.TgBot.Token)
+	storage := repository.NewStorage(pg)
+	services := service.NewServices(storage)
+
+	if len(os.Args) > 1 {
+		if err := runCommand(services, os.Args[1:]); err != nil {
+			zlog.Logger.Fatal().Msg(err.Error())
+		}
+		return
+	}
+
+	var notifiers []service.Notifier
+	tgbot := service.NewTelegramBot(c.TgBot.Token, services)
 	if tgbot == nil {
 		zlog.Logger.Warn().Msg("App starting without telegram bot")
+	} else {
+		notifiers = append(notifiers, tgbot)
+	}
+
+	if c.SMTP.Host != "" {
+		notifiers = append(notifiers, service.NewEmailNotifier(c.SMTP.Host, c.SMTP.Port,
+			c.SMTP.Username, c.SMTP.Password, c.SMTP.From))
+	} else {
+		// Verification codes go out by email only, so without SMTP no new
+		// account could ever be verified.
+		if !c.Auth.UnverifiedLogin || !c.Auth.UnverifiedBooking {
+			zlog.Logger.Fatal().Msg("SMTP_HOST is required when UNVERIFIED_LOGIN or UNVERIFIED_BOOKING is false")
+		}
+		zlog.Logger.Warn().Msg("App starting without smtp, email notifications stay pending")
 	}
 
-	storage := repository.NewStorage(pg)
-	services := service.NewServices(storage)
 	handlers := handlers.NewHandlers(services)
 	engine := ginext.New("debug")
+	// Gin trusts every proxy by default and would take the client address
+	// from the leftmost X-Forwarded-For entry, which the client controls.
+	if err := engine.SetTrustedProxies(c.Server.TrustedProxies); err != nil {
+		zlog.Logger.Fatal().Msg(err.Error())
+	}
 	api.SetupRoutes(handlers, engine)
 
 	app := app.App{
-		Handler:  engine,
-		Port:     ":" + c.Server.Port,
-		Services: services,
-		Storage:  storage,
-		TgBot:    tgbot,
+		Handler:   engine,
+		Port:      ":" + c.Server.Port,
+		Services:  services,
+		Storage:   storage,
+		TgBot:     tgbot,
+		Notifiers: notifiers,
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
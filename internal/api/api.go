@@ -4,6 +4,7 @@ import (
 	"github.com/wb-go/wbf/ginext"
 
 	"EventBooker/internal/api/handlers"
+	"EventBooker/internal/model"
 )
 
 func SetupRoutes(h *handlers.Handlers, g *ginext.Engine) {
@@ -19,25 +20,78 @@ func SetupRoutes(h *handlers.Handlers, g *ginext.Engine) {
 	{
 		auth.POST("/register", h.User.Register)
 		auth.POST("/login", h.User.Login)
+		auth.POST("/refresh", h.User.Refresh)
+		auth.POST("/logout", h.User.Logout)
+		auth.POST("/password/forgot", h.User.ForgotPassword)
+		auth.POST("/password/reset", h.User.ResetPassword)
+		auth.POST("/verify", h.User.VerifyEmail)
 	}
 
 	g.GET("/events", h.Event.GetListEvents)
 	g.GET("/events/:id", h.Event.GetEvent)
+	g.GET("/events/:id/seats", h.Event.GetSeatMap)
+	g.POST("/webhooks/payments/:provider", h.Payment.Webhook)
 
 	api := g.Group("/api")
-	api.Use(handlers.AuthMiddleware())
+	api.Use(h.Auth)
 	{
 		api.POST("/events/:event_id/book", h.Booking.Book)
 		api.POST("/events/:event_id/confirm/:book_id", h.Booking.Confirm)
+		api.POST("/events/:event_id/pay/:book_id", h.Payment.Pay)
+		api.GET("/events/:event_id/cancel/:book_id", h.Booking.CancellationQuote)
 		api.POST("/events/:event_id/cancel/:book_id", h.Booking.Cancel)
 		api.GET("/books", h.Booking.GetListBooking)
+		api.POST("/events/:event_id/waitlist", h.Waitlist.Join)
+		api.DELETE("/events/:event_id/waitlist", h.Waitlist.Leave)
+		api.GET("/waitlist", h.Waitlist.GetList)
+		api.GET("/me", h.User.GetProfile)
+		api.PATCH("/me", h.User.UpdateProfile)
+		api.DELETE("/me", h.User.DeleteAccount)
+		api.GET("/me/export", h.Export.ExportMe)
+		api.GET("/me/notifications", h.Notification.GetPreferences)
+		api.PUT("/me/notifications", h.Notification.UpdatePreferences)
+		api.POST("/me/telegram/link", h.User.CreateTgLink)
+		api.DELETE("/me/telegram", h.User.UnlinkTelegram)
+		api.PUT("/me/password", h.User.ChangePassword)
+		api.POST("/me/email/verify", h.User.ResendVerification)
+
+		eventsWrite := handlers.RequirePermission(model.PermissionEventsWrite)
+		usersRead := handlers.RequirePermission(model.PermissionUsersRead)
+		usersManage := handlers.RequirePermission(model.PermissionUsersManage)
+		notificationsManage := handlers.RequirePermission(model.PermissionNotificationsManage)
+		promoCodesManage := handlers.RequirePermission(model.PermissionPromoCodesManage)
+		venuesManage := handlers.RequirePermission(model.PermissionVenuesManage)
 
 		admin := api.Group("/admin")
-		admin.Use(handlers.AdminMiddleware())
 		{
-			admin.GET("/check")
-			admin.POST("/events", h.Event.CreateEvent)
-			admin.GET("/users", h.User.GetList)
+			admin.GET("/check", handlers.RequireAnyPermission(model.Permissions...))
+			admin.POST("/events", eventsWrite, h.Event.CreateEvent)
+			admin.GET("/events", eventsWrite, h.Event.GetListEventsAdmin)
+			admin.POST("/events/:id/status", eventsWrite, h.Event.ChangeStatus)
+			admin.PATCH("/events/:id", eventsWrite, h.Event.UpdateEvent)
+			admin.DELETE("/events/:id", eventsWrite, h.Event.DeleteEvent)
+			admin.POST("/events/:id/cancel", eventsWrite, h.Event.CancelEvent)
+			admin.GET("/users", usersRead, h.User.GetList)
+			admin.PUT("/users/:id/role", usersManage, h.User.ChangeRole)
+			admin.DELETE("/users/:id/sessions", usersManage, h.User.RevokeSessions)
+			admin.DELETE("/users/:id/lockout", usersManage, h.User.Unlock)
+			admin.POST("/users/:id/export", usersManage, h.Export.RequestExport)
+			admin.GET("/exports/:id", usersManage, h.Export.GetExport)
+			admin.GET("/exports/:id/download", usersManage, h.Export.Download)
+			admin.GET("/roles", usersRead, h.User.GetRoles)
+			admin.PUT("/roles/:role/permissions", usersManage, h.User.SetRolePermissions)
+			admin.GET("/notifications", notificationsManage, h.Notification.GetList)
+			admin.POST("/notifications/:id/resend", notificationsManage, h.Notification.Resend)
+			admin.POST("/promo-codes", promoCodesManage, h.PromoCode.Create)
+			admin.GET("/promo-codes", promoCodesManage, h.PromoCode.GetList)
+			admin.GET("/promo-codes/:id", promoCodesManage, h.PromoCode.Get)
+			admin.PUT("/promo-codes/:id", promoCodesManage, h.PromoCode.Update)
+			admin.DELETE("/promo-codes/:id", promoCodesManage, h.PromoCode.Delete)
+			admin.GET("/venues", eventsWrite, h.Venue.GetList)
+			admin.GET("/venues/:id", eventsWrite, h.Venue.Get)
+			admin.POST("/venues", venuesManage, h.Venue.Create)
+			admin.PUT("/venues/:id", venuesManage, h.Venue.Update)
+			admin.DELETE("/venues/:id", venuesManage, h.Venue.Delete)
 		}
 	}
 
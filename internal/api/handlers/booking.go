@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -31,13 +33,50 @@ func (h *BookingHandler) Book(c *ginext.Context) {
 		return
 	}
 
-	b := model.BookingInCreate{
-		UserID:  userID,
-		EventID: eventID,
+	var b model.BookingInCreate
+	err = c.ShouldBindJSON(&b)
+	if err != nil && !errors.Is(err, io.EOF) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
+	b.UserID = userID
+	b.EventID = eventID
 
 	err = h.bookingService.Book(context.Background(), b)
 	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeRequired) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPromoCodeNotFound) || errors.Is(err, service.ErrSeatNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSeatsRequired) || errors.Is(err, service.ErrSeatsNotAssigned) ||
+			errors.Is(err, service.ErrSeatsMixedTiers) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrSeatUnavailable) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPromoCodeNotApplicable) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPromoCodeInactive) || errors.Is(err, service.ErrPromoCodeExhausted) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -64,6 +103,10 @@ func (h *BookingHandler) Confirm(c *ginext.Context) {
 
 	err = h.bookingService.Confirm(context.Background(), bookID, eventID, userID)
 	if err != nil {
+		if errors.Is(err, service.ErrPaymentRequired) {
+			NewErrorResponse(c, http.StatusPaymentRequired, err.Error())
+			return
+		}
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -142,9 +185,49 @@ func (h *BookingHandler) Cancel(c *ginext.Context) {
 
 	err = h.bookingService.CancelBook(context.Background(), bookID, eventID, userID)
 	if err != nil {
+		if errors.Is(err, service.ErrBookingNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrBookingNotActive) || errors.Is(err, service.ErrCancellationClosed) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	NewSuccessResponse(c, http.StatusOK, "booking canceled")
 }
+
+// CancellationQuote shows the refund the user would get by cancelling now.
+func (h *BookingHandler) CancellationQuote(c *ginext.Context) {
+	userID := c.GetInt("userID")
+	eventID, err := strconv.Atoi(c.Param("event_id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := h.bookingService.GetCancellationQuote(context.Background(), bookID, eventID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrBookingNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrBookingNotActive) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
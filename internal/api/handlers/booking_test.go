@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/wb-go/wbf/ginext"
 
 	"EventBooker/internal/model"
+	"EventBooker/internal/service"
 	"EventBooker/internal/service/mocks"
 )
 
@@ -40,6 +42,7 @@ func TestBookHandler_Book(t *testing.T) {
 	tests := []struct {
 		name           string
 		eventIDStr     string
+		body           string
 		userID         int
 		setupMocks     func(ms *mocks.MockBookingService)
 		expectedStatus int
@@ -75,6 +78,166 @@ func TestBookHandler_Book(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "database connection failed",
 		},
+		{
+			name:       "email not verified",
+			eventIDStr: "10",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrEmailNotVerified)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   service.ErrEmailNotVerified.Error(),
+		},
+		{
+			name:       "success booking with quantity",
+			eventIDStr: "15",
+			body:       `{"quantity": 4}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.MatchedBy(func(b model.BookingInCreate) bool {
+					return b.UserID == 42 && b.EventID == 15 && b.Quantity == 4
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"book"`,
+		},
+		{
+			name:       "body cannot override user and event",
+			eventIDStr: "15",
+			body:       `{"user_id": 1, "event_id": 2, "quantity": 2}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.MatchedBy(func(b model.BookingInCreate) bool {
+					return b.UserID == 42 && b.EventID == 15 && b.Quantity == 2
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"book"`,
+		},
+		{
+			name:           "invalid body",
+			eventIDStr:     "15",
+			body:           `{"quantity": "four"}`,
+			userID:         42,
+			setupMocks:     func(ms *mocks.MockBookingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "cannot unmarshal",
+		},
+		{
+			name:       "no seats for requested quantity",
+			eventIDStr: "15",
+			body:       `{"quantity": 10}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrNoSeatsAvailable)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "no seats available",
+		},
+		{
+			name:       "ticket type passed to service",
+			eventIDStr: "15",
+			body:       `{"quantity": 2, "ticket_type_id": 7}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.MatchedBy(func(b model.BookingInCreate) bool {
+					return b.TicketTypeID == 7 && b.Quantity == 2
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"book"`,
+		},
+		{
+			name:       "ticket type of another event",
+			eventIDStr: "15",
+			body:       `{"ticket_type_id": 99}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrTicketTypeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "ticket type not found",
+		},
+		{
+			name:       "ticket type required",
+			eventIDStr: "15",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrTicketTypeRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "ticket_type_id is required",
+		},
+		{
+			name:       "promo code passed to service",
+			eventIDStr: "15",
+			body:       `{"promo_code": "summer"}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.MatchedBy(func(b model.BookingInCreate) bool {
+					return b.PromoCode == "summer"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"book"`,
+		},
+		{
+			name:       "promo code used up",
+			eventIDStr: "15",
+			body:       `{"promo_code": "summer"}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrPromoCodeExhausted)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "usage limit reached",
+		},
+		{
+			name:       "unknown promo code",
+			eventIDStr: "15",
+			body:       `{"promo_code": "nope"}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrPromoCodeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "promo code not found",
+		},
+		{
+			name:       "seats",
+			eventIDStr: "15",
+			body:       `{"seat_ids": [7, 8]}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.MatchedBy(func(b model.BookingInCreate) bool {
+					return len(b.SeatIDs) == 2 && b.SeatIDs[0] == 7 && b.SeatIDs[1] == 8
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"book"`,
+		},
+		{
+			name:       "seat taken",
+			eventIDStr: "15",
+			body:       `{"seat_ids": [7]}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrSeatUnavailable)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "already held or sold",
+		},
+		{
+			name:       "seats required",
+			eventIDStr: "15",
+			body:       `{"quantity": 2}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Book", mock.Anything, mock.Anything).Return(service.ErrSeatsRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "seat_ids are required",
+		},
 		{
 			name:       "zero user id",
 			eventIDStr: "5",
@@ -99,7 +262,7 @@ func TestBookHandler_Book(t *testing.T) {
 			tt.setupMocks(mockService)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("POST", "/book/"+tt.eventIDStr, nil)
+			req, _ := http.NewRequest("POST", "/book/"+tt.eventIDStr, strings.NewReader(tt.body))
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
@@ -114,6 +277,7 @@ func TestBookHandler_Confirm(t *testing.T) {
 	tests := []struct {
 		name           string
 		eventIDStr     string
+		bookIDStr      string
 		userID         int
 		setupMocks     func(ms *mocks.MockBookingService)
 		expectedStatus int
@@ -122,9 +286,10 @@ func TestBookHandler_Confirm(t *testing.T) {
 		{
 			name:       "success confirm",
 			eventIDStr: "25",
+			bookIDStr:  "3",
 			userID:     42,
 			setupMocks: func(ms *mocks.MockBookingService) {
-				ms.On("Confirm", mock.Anything, 25, 42).Return(nil)
+				ms.On("Confirm", mock.Anything, 3, 25, 42).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
 			expectedBody:   `"book confirmed"`,
@@ -132,6 +297,7 @@ func TestBookHandler_Confirm(t *testing.T) {
 		{
 			name:           "invalid event id",
 			eventIDStr:     "abc",
+			bookIDStr:      "3",
 			userID:         42,
 			setupMocks:     func(ms *mocks.MockBookingService) {},
 			expectedStatus: http.StatusBadRequest,
@@ -141,13 +307,25 @@ func TestBookHandler_Confirm(t *testing.T) {
 		{
 			name:       "service error",
 			eventIDStr: "7",
+			bookIDStr:  "3",
 			userID:     42,
 			setupMocks: func(ms *mocks.MockBookingService) {
-				ms.On("Confirm", mock.Anything, 7, 42).Return(errors.New("event not found"))
+				ms.On("Confirm", mock.Anything, 3, 7, 42).Return(errors.New("event not found"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "event not found",
 		},
+		{
+			name:       "paid booking",
+			eventIDStr: "7",
+			bookIDStr:  "3",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("Confirm", mock.Anything, 3, 7, 42).Return(service.ErrPaymentRequired)
+			},
+			expectedStatus: http.StatusPaymentRequired,
+			expectedBody:   "has to be paid",
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,12 +333,12 @@ func TestBookHandler_Confirm(t *testing.T) {
 			mockService := mocks.NewMockBookingService(t)
 			handler := NewBookingService(mockService)
 			router := setupTestRouter(tt.userID)
-			router.POST("/confirm/:event_id", handler.Confirm)
+			router.POST("/confirm/:event_id/:book_id", handler.Confirm)
 
 			tt.setupMocks(mockService)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("POST", "/confirm/"+tt.eventIDStr, nil)
+			req, _ := http.NewRequest("POST", "/confirm/"+tt.eventIDStr+"/"+tt.bookIDStr, nil)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
@@ -393,6 +571,7 @@ func TestCancelHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		eventIDStr     string
+		bookIDStr      string
 		userID         int
 		setupMocks     func(ms *mocks.MockBookingService)
 		expectedStatus int
@@ -402,10 +581,11 @@ func TestCancelHandler(t *testing.T) {
 		{
 			name:       "success cancel",
 			eventIDStr: "30",
+			bookIDStr:  "5",
 			userID:     42,
 			httpMethod: "POST",
 			setupMocks: func(ms *mocks.MockBookingService) {
-				ms.On("CancelBook", mock.Anything, 30, 42).Return(nil)
+				ms.On("CancelBook", mock.Anything, 5, 30, 42).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `"booking canceled"`,
@@ -413,6 +593,7 @@ func TestCancelHandler(t *testing.T) {
 		{
 			name:           "invalid event id",
 			eventIDStr:     "notanumber",
+			bookIDStr:      "5",
 			userID:         42,
 			httpMethod:     "POST",
 			setupMocks:     func(ms *mocks.MockBookingService) {},
@@ -423,21 +604,35 @@ func TestCancelHandler(t *testing.T) {
 		{
 			name:       "service error",
 			eventIDStr: "12",
+			bookIDStr:  "5",
 			userID:     42,
 			httpMethod: "POST",
 			setupMocks: func(ms *mocks.MockBookingService) {
-				ms.On("CancelBook", mock.Anything, 12, 42).Return(errors.New("booking not found"))
+				ms.On("CancelBook", mock.Anything, 5, 12, 42).Return(errors.New("booking not found"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "booking not found",
 		},
+		{
+			name:       "cancellation closed",
+			eventIDStr: "12",
+			bookIDStr:  "5",
+			userID:     42,
+			httpMethod: "POST",
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("CancelBook", mock.Anything, 5, 12, 42).Return(service.ErrCancellationClosed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "can no longer be cancelled",
+		},
 		{
 			name:       "zero user id cancel",
 			eventIDStr: "8",
+			bookIDStr:  "5",
 			userID:     0,
 			httpMethod: "POST",
 			setupMocks: func(ms *mocks.MockBookingService) {
-				ms.On("CancelBook", mock.Anything, 8, 0).Return(nil)
+				ms.On("CancelBook", mock.Anything, 5, 8, 0).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `"booking canceled"`,
@@ -450,12 +645,12 @@ func TestCancelHandler(t *testing.T) {
 			handler := NewBookingService(mockService)
 			router := setupTestRouter(tt.userID)
 
-			router.POST("/cancel/:event_id", handler.Cancel)
+			router.POST("/cancel/:event_id/:book_id", handler.Cancel)
 
 			tt.setupMocks(mockService)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest(tt.httpMethod, "/cancel/"+tt.eventIDStr, nil)
+			req, _ := http.NewRequest(tt.httpMethod, "/cancel/"+tt.eventIDStr+"/"+tt.bookIDStr, nil)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
@@ -465,3 +660,71 @@ func TestCancelHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestCancellationQuoteHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		bookIDStr      string
+		setupMocks     func(ms *mocks.MockBookingService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:      "success",
+			bookIDStr: "5",
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("GetCancellationQuote", mock.Anything, 5, 30, 42).Return(model.CancellationQuote{
+					BookingID: 5, Allowed: true, RefundPercent: 50, RefundAmount: 75000, Currency: "RUB",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"refund_amount":75000`,
+		},
+		{
+			name:           "invalid book id",
+			bookIDStr:      "abc",
+			setupMocks:     func(ms *mocks.MockBookingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:      "not found",
+			bookIDStr: "5",
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("GetCancellationQuote", mock.Anything, 5, 30, 42).Return(model.CancellationQuote{}, service.ErrBookingNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "booking not found",
+		},
+		{
+			name:      "already cancelled",
+			bookIDStr: "5",
+			setupMocks: func(ms *mocks.MockBookingService) {
+				ms.On("GetCancellationQuote", mock.Anything, 5, 30, 42).Return(model.CancellationQuote{}, service.ErrBookingNotActive)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "already cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockBookingService(t)
+			handler := NewBookingService(mockService)
+			router := setupTestRouter(42)
+
+			router.GET("/cancel/:event_id/:book_id", handler.CancellationQuote)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/cancel/30/"+tt.bookIDStr, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
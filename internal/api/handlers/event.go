@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -29,7 +30,7 @@ func (h *EventHandler) CreateEvent(c *ginext.Context) {
 		return
 	}
 
-	err = h.eventService.CreateEvent(context.Background(), e)
+	err = h.eventService.CreateEvent(context.Background(), actorFromContext(c), e)
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -55,7 +56,38 @@ func (h *EventHandler) GetEvent(c *ginext.Context) {
 	c.JSON(http.StatusOK, e)
 }
 
+// GetSeatMap shows the seats of an event with assigned seating and whether
+// they are free, held or sold.
+func (h *EventHandler) GetSeatMap(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	seats, err := h.eventService.GetSeatMap(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) || errors.Is(err, service.ErrSeatsNotAssigned) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, seats)
+}
+
 func (h *EventHandler) GetListEvents(c *ginext.Context) {
+	h.listEvents(c, false)
+}
+
+// GetListEventsAdmin lists events including drafts.
+func (h *EventHandler) GetListEventsAdmin(c *ginext.Context) {
+	h.listEvents(c, true)
+}
+
+func (h *EventHandler) listEvents(c *ginext.Context, includeDrafts bool) {
 	lastCreatedAtStr := c.Query("last_created_at")
 	lastCreatedAt, err := time.Parse(time.RFC3339, lastCreatedAtStr)
 	if err != nil {
@@ -84,6 +116,12 @@ func (h *EventHandler) GetListEvents(c *ginext.Context) {
 		LastID:        lastID,
 		Mode:          mode,
 		PageSize:      pageSize,
+		IncludeDrafts: includeDrafts,
+	}
+
+	// Without events:manage_all the management list shows only own events.
+	if includeDrafts && !actorFromContext(c).Can(model.PermissionEventsManageAll) {
+		req.OrganizerID = c.GetInt("userID")
 	}
 
 	e, err := h.eventService.GetListEvents(context.Background(), req)
@@ -92,7 +130,7 @@ func (h *EventHandler) GetListEvents(c *ginext.Context) {
 		return
 	}
 
-	countEvent, err := h.eventService.GetCountEvent(context.Background())
+	countEvent, err := h.eventService.GetCountEvent(context.Background(), includeDrafts, req.OrganizerID)
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -103,3 +141,101 @@ func (h *EventHandler) GetListEvents(c *ginext.Context) {
 		"events": e,
 	})
 }
+
+func (h *EventHandler) UpdateEvent(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var e model.EventInUpdate
+	err = c.ShouldBindJSON(&e)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.eventService.UpdateEvent(context.Background(), actorFromContext(c), id, e)
+	if err != nil {
+		if errors.Is(err, service.ErrEventForbidden) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "event updated")
+}
+
+func (h *EventHandler) CancelEvent(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.eventService.CancelEvent(context.Background(), actorFromContext(c), id)
+	if err != nil {
+		if errors.Is(err, service.ErrEventForbidden) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "event canceled")
+}
+
+func (h *EventHandler) DeleteEvent(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.eventService.DeleteEvent(context.Background(), actorFromContext(c), id)
+	if err != nil {
+		if errors.Is(err, service.ErrEventForbidden) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "event deleted")
+}
+
+func (h *EventHandler) ChangeStatus(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req model.EventStatusUpdate
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.eventService.ChangeStatus(context.Background(), actorFromContext(c), id, req.Status)
+	if err != nil {
+		if errors.Is(err, service.ErrEventForbidden) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "event status changed")
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/wb-go/wbf/ginext"
 
 	"EventBooker/internal/model"
+	"EventBooker/internal/service"
 	"EventBooker/internal/service/mocks"
 )
 
@@ -41,7 +43,7 @@ func TestCreateEvent(t *testing.T) {
 				BookingConfimation: true,
 			},
 			setupMocks: func(ms *mocks.MockEventService) {
-				ms.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e model.EventInCreate) bool {
+				ms.On("CreateEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e model.EventInCreate) bool {
 					return e.Title == "Test Conference" &&
 						e.TotalPlace == 100 &&
 						e.BookingConfimation
@@ -50,6 +52,38 @@ func TestCreateEvent(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedBody:   `"event created"`,
 		},
+		{
+			name: "custom reminders",
+			requestBody: model.EventInCreate{
+				Title:             "Reminded Event",
+				EventDate:         time.Now().Add(72 * time.Hour),
+				TotalPlace:        10,
+				ReservationPeriod: "1h",
+				Reminders:         []string{"48h", "30m"},
+			},
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("CreateEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(e model.EventInCreate) bool {
+					return len(e.Reminders) == 2 && e.Reminders[0] == "48h" && e.Reminders[1] == "30m"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"event created"`,
+		},
+		{
+			name: "invalid reminder",
+			requestBody: model.EventInCreate{
+				Title:             "Bad Reminder",
+				EventDate:         time.Now().Add(72 * time.Hour),
+				TotalPlace:        10,
+				ReservationPeriod: "1h",
+				Reminders:         []string{"soon"},
+			},
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(service.ErrInvalidReminder)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "reminders must be positive durations",
+		},
 
 		{
 			name: "service error",
@@ -62,7 +96,7 @@ func TestCreateEvent(t *testing.T) {
 				BookingConfimation: false,
 			},
 			setupMocks: func(ms *mocks.MockEventService) {
-				ms.On("CreateEvent", mock.Anything, mock.Anything).Return(errors.New("database connection failed"))
+				ms.On("CreateEvent", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database connection failed"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "database connection failed",
@@ -114,7 +148,7 @@ func TestGetEvent(t *testing.T) {
 					EventDate:          time.Now().Add(24 * time.Hour),
 					TotalPlace:         200,
 					OccupiedPlace:      50,
-					EventStatus:        model.EventStatusPending,
+					EventStatus:        model.EventStatusPublished,
 					ReservationPeriod:  "24h",
 					BookingConfimation: true,
 					CreatedAt:          time.Now(),
@@ -164,6 +198,79 @@ func TestGetEvent(t *testing.T) {
 	}
 }
 
+func TestGetSeatMap(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventIDStr     string
+		setupMocks     func(ms *mocks.MockEventService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "success",
+			eventIDStr: "15",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("GetSeatMap", mock.Anything, 15).Return(model.SeatMapInResponse{
+					EventID: 15,
+					Held:    1,
+					Seats: []model.EventSeatInResponse{{
+						SeatInResponse: model.SeatInResponse{ID: 3, Section: "Партер", Row: "1", Number: 2},
+						State:          model.SeatStateHeld,
+					}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"state":"held"`,
+		},
+		{
+			name:       "general admission",
+			eventIDStr: "16",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("GetSeatMap", mock.Anything, 16).Return(model.SeatMapInResponse{}, service.ErrSeatsNotAssigned)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no assigned seating",
+		},
+		{
+			name:       "draft",
+			eventIDStr: "17",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("GetSeatMap", mock.Anything, 17).Return(model.SeatMapInResponse{}, service.ErrEventNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "event not found",
+		},
+		{
+			name:           "invalid event id",
+			eventIDStr:     "abc",
+			setupMocks:     func(ms *mocks.MockEventService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEventService(t)
+			handler := NewEventHandler(mockService)
+			router := setupEventTestRouter()
+
+			router.GET("/events/:id/seats", handler.GetSeatMap)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/events/"+tt.eventIDStr+"/seats", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetListEvents_QueryParams(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -189,7 +296,7 @@ func TestGetListEvents_QueryParams(t *testing.T) {
 						EventDate:          time.Now().Add(48 * time.Hour),
 						TotalPlace:         150,
 						OccupiedPlace:      30,
-						EventStatus:        model.EventStatusPending,
+						EventStatus:        model.EventStatusPublished,
 						ReservationPeriod:  "24h",
 						BookingConfimation: true,
 						CreatedAt:          time.Now(),
@@ -202,7 +309,7 @@ func TestGetListEvents_QueryParams(t *testing.T) {
 						!req.LastCreatedAt.IsZero()
 				})).Return(events, nil)
 
-				ms.On("GetCountEvent", mock.Anything).Return(25, nil)
+				ms.On("GetCountEvent", mock.Anything, false, 0).Return(25, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `"count":25`,
@@ -268,7 +375,7 @@ func TestGetListEvents_QueryParams(t *testing.T) {
 			setupMocks: func(ms *mocks.MockEventService) {
 				events := []model.EventInResponse{{ID: 1, Title: "Test"}}
 				ms.On("GetListEvents", mock.Anything, mock.Anything).Return(events, nil)
-				ms.On("GetCountEvent", mock.Anything).Return(0, errors.New("count query failed"))
+				ms.On("GetCountEvent", mock.Anything, false, 0).Return(0, errors.New("count query failed"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "count query failed",
@@ -300,3 +407,306 @@ func TestGetListEvents_QueryParams(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventIDStr     string
+		body           string
+		setupMocks     func(ms *mocks.MockEventService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "success partial update",
+			eventIDStr: "15",
+			body:       `{"title": "Fixed Title", "total_place": 120}`,
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("UpdateEvent", mock.Anything, mock.Anything, 15, mock.MatchedBy(func(e model.EventInUpdate) bool {
+					return e.Title != nil && *e.Title == "Fixed Title" &&
+						e.TotalPlace != nil && *e.TotalPlace == 120 &&
+						e.EventDate == nil && e.Description == nil
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"event updated"`,
+		},
+		{
+			name:           "invalid event id",
+			eventIDStr:     "abc",
+			body:           `{"title": "Fixed Title"}`,
+			setupMocks:     func(ms *mocks.MockEventService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:           "invalid body",
+			eventIDStr:     "15",
+			body:           `{"total_place": "many"}`,
+			setupMocks:     func(ms *mocks.MockEventService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "cannot unmarshal",
+		},
+		{
+			name:       "total place below occupancy",
+			eventIDStr: "15",
+			body:       `{"total_place": 1}`,
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("UpdateEvent", mock.Anything, mock.Anything, 15, mock.Anything).Return(service.ErrTotalPlaceBelowOccupied)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "less than occupied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEventService(t)
+			handler := NewEventHandler(mockService)
+			router := setupEventTestRouter()
+
+			router.PATCH("/events/:id", handler.UpdateEvent)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/events/"+tt.eventIDStr, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelAndDeleteEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		setupMocks     func(ms *mocks.MockEventService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "success cancel",
+			method: "POST",
+			path:   "/events/15/cancel",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("CancelEvent", mock.Anything, mock.Anything, 15).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"event canceled"`,
+		},
+		{
+			name:   "cancel already canceled",
+			method: "POST",
+			path:   "/events/15/cancel",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("CancelEvent", mock.Anything, mock.Anything, 15).Return(service.ErrEventCanceled)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "event is canceled",
+		},
+		{
+			name:           "cancel invalid id",
+			method:         "POST",
+			path:           "/events/abc/cancel",
+			setupMocks:     func(ms *mocks.MockEventService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:   "success delete",
+			method: "DELETE",
+			path:   "/events/15",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("DeleteEvent", mock.Anything, mock.Anything, 15).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"event deleted"`,
+		},
+		{
+			name:   "delete event of another organizer",
+			method: "DELETE",
+			path:   "/events/15",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("DeleteEvent", mock.Anything, mock.Anything, 15).Return(service.ErrEventForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   service.ErrEventForbidden.Error(),
+		},
+		{
+			name:   "delete event with bookings",
+			method: "DELETE",
+			path:   "/events/15",
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("DeleteEvent", mock.Anything, mock.Anything, 15).Return(service.ErrEventHasBookings)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "active bookings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEventService(t)
+			handler := NewEventHandler(mockService)
+			router := setupEventTestRouter()
+
+			router.POST("/events/:id/cancel", handler.CancelEvent)
+			router.DELETE("/events/:id", handler.DeleteEvent)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetListEventsAdmin_IncludesDrafts(t *testing.T) {
+	mockService := mocks.NewMockEventService(t)
+	handler := NewEventHandler(mockService)
+	router := setupEventTestRouter()
+
+	router.GET("/admin/events", handler.GetListEventsAdmin)
+
+	events := []model.EventInResponse{{ID: 1, Title: "Draft Event", EventStatus: model.EventStatusDraft}}
+	mockService.On("GetListEvents", mock.Anything, mock.MatchedBy(func(req model.EventGetRequest) bool {
+		return req.IncludeDrafts && req.PageSize == 10
+	})).Return(events, nil)
+	mockService.On("GetCountEvent", mock.Anything, true, 0).Return(1, nil)
+
+	testURL := buildURL("/admin/events", map[string]string{
+		"last_created_at": time.Now().Format(time.RFC3339),
+		"last_id":         "0",
+		"page_size":       "10",
+		"mode":            "next",
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", testURL, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"event_status":"draft"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetListEventsAdmin_OrganizerSeesOwnEvents(t *testing.T) {
+	mockService := mocks.NewMockEventService(t)
+	handler := NewEventHandler(mockService)
+	router := setupEventTestRouter()
+
+	router.Use(func(c *ginext.Context) {
+		c.Set("userID", 7)
+		c.Set("role", model.RoleOrganizer)
+		c.Set("permissions", []string{model.PermissionEventsWrite})
+		c.Next()
+	})
+	router.GET("/admin/events", handler.GetListEventsAdmin)
+	router.POST("/admin/events", handler.CreateEvent)
+
+	mockService.On("GetListEvents", mock.Anything, mock.MatchedBy(func(req model.EventGetRequest) bool {
+		return req.IncludeDrafts && req.OrganizerID == 7
+	})).Return([]model.EventInResponse{}, nil)
+	mockService.On("GetCountEvent", mock.Anything, true, 7).Return(0, nil)
+	mockService.On("CreateEvent", mock.Anything, model.Actor{
+		UserID:      7,
+		Role:        model.RoleOrganizer,
+		Permissions: []string{model.PermissionEventsWrite},
+	}, mock.Anything).Return(nil)
+
+	testURL := buildURL("/admin/events", map[string]string{
+		"last_created_at": time.Now().Format(time.RFC3339),
+		"last_id":         "0",
+		"page_size":       "10",
+		"mode":            "next",
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", testURL, nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/admin/events", strings.NewReader(`{"title":"Own"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestChangeEventStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventIDStr     string
+		body           string
+		setupMocks     func(ms *mocks.MockEventService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "publish draft",
+			eventIDStr: "15",
+			body:       `{"status": "published"}`,
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("ChangeStatus", mock.Anything, mock.Anything, 15, model.EventStatusPublished).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"event status changed"`,
+		},
+		{
+			name:       "transition not allowed",
+			eventIDStr: "15",
+			body:       `{"status": "draft"}`,
+			setupMocks: func(ms *mocks.MockEventService) {
+				ms.On("ChangeStatus", mock.Anything, mock.Anything, 15, model.EventStatusDraft).Return(service.ErrInvalidStatusTransition)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "transition not allowed",
+		},
+		{
+			name:           "invalid event id",
+			eventIDStr:     "abc",
+			body:           `{"status": "published"}`,
+			setupMocks:     func(ms *mocks.MockEventService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockEventService(t)
+			handler := NewEventHandler(mockService)
+			router := setupEventTestRouter()
+
+			router.POST("/events/:id/status", handler.ChangeStatus)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/events/"+tt.eventIDStr+"/status", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(s service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: s}
+}
+
+func (h *ExportHandler) ExportMe(c *ginext.Context) {
+	userID := c.GetInt("userID")
+	format := c.DefaultQuery("format", model.ExportFormatJSON)
+
+	payload, err := h.exportService.ExportUser(context.Background(), userID, format)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidExportFormat) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendExport(c, fmt.Sprintf("eventbooker-user-%d", userID), format, payload)
+}
+
+func (h *ExportHandler) RequestExport(c *ginext.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req model.DataExportRequest
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export, err := h.exportService.RequestExport(context.Background(), c.GetInt("userID"), userID, req.Format)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidExportFormat) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+func (h *ExportHandler) GetExport(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export, err := h.exportService.GetExport(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrExportNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+func (h *ExportHandler) Download(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export, err := h.exportService.DownloadExport(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrExportNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrExportNotReady) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sendExport(c, fmt.Sprintf("eventbooker-user-%d", export.UserID), export.Format, export.Payload)
+}
+
+func sendExport(c *ginext.Context, name, format string, payload []byte) {
+	contentType := "application/json"
+	if format == model.ExportFormatZIP {
+		contentType = "application/zip"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Data(http.StatusOK, contentType, payload)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestExportHandler(t *testing.T) {
+	requestedBy := 1
+
+	tests := []struct {
+		name                string
+		method              string
+		path                string
+		requestBody         string
+		setupMocks          func(ms *mocks.MockExportService)
+		expectedStatus      int
+		expectedBody        string
+		expectedContentType string
+		expectedDisposition string
+	}{
+		{
+			name:   "export me as json",
+			method: "GET",
+			path:   "/me/export",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("ExportUser", mock.Anything, 1, "json").Return([]byte(`{"user":{"id":1}}`), nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"user":{"id":1}}`,
+			expectedContentType: "application/json",
+			expectedDisposition: `attachment; filename="eventbooker-user-1.json"`,
+		},
+		{
+			name:   "export me as zip",
+			method: "GET",
+			path:   "/me/export?format=zip",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("ExportUser", mock.Anything, 1, "zip").Return([]byte("PK"), nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "PK",
+			expectedContentType: "application/zip",
+			expectedDisposition: `attachment; filename="eventbooker-user-1.zip"`,
+		},
+		{
+			name:   "export me invalid format",
+			method: "GET",
+			path:   "/me/export?format=xml",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("ExportUser", mock.Anything, 1, "xml").Return(nil, service.ErrInvalidExportFormat)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidExportFormat.Error(),
+		},
+		{
+			name:        "request export",
+			method:      "POST",
+			path:        "/admin/users/5/export",
+			requestBody: `{"format":"zip"}`,
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("RequestExport", mock.Anything, 1, 5, "zip").Return(model.DataExportInResponse{
+					ID: 3, UserID: 5, RequestedBy: &requestedBy, Format: "zip", Status: model.StatusExportPending,
+				}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `"status":"pending"`,
+		},
+		{
+			name:        "request export of unknown user",
+			method:      "POST",
+			path:        "/admin/users/5/export",
+			requestBody: `{}`,
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("RequestExport", mock.Anything, 1, 5, "").Return(model.DataExportInResponse{}, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   service.ErrUserNotFound.Error(),
+		},
+		{
+			name:   "get export",
+			method: "GET",
+			path:   "/admin/exports/3",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("GetExport", mock.Anything, 3).Return(model.DataExportInResponse{ID: 3, Status: model.StatusExportReady}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status":"ready"`,
+		},
+		{
+			name:   "get unknown export",
+			method: "GET",
+			path:   "/admin/exports/3",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("GetExport", mock.Anything, 3).Return(model.DataExportInResponse{}, service.ErrExportNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   service.ErrExportNotFound.Error(),
+		},
+		{
+			name:   "download export",
+			method: "GET",
+			path:   "/admin/exports/3/download",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("DownloadExport", mock.Anything, 3).Return(model.DataExportInRepo{
+					ID: 3, UserID: 5, Format: "json", Status: model.StatusExportReady, Payload: []byte(`{"user":{"id":5}}`),
+				}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        `{"user":{"id":5}}`,
+			expectedContentType: "application/json",
+			expectedDisposition: `attachment; filename="eventbooker-user-5.json"`,
+		},
+		{
+			name:   "download pending export",
+			method: "GET",
+			path:   "/admin/exports/3/download",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("DownloadExport", mock.Anything, 3).Return(model.DataExportInRepo{}, service.ErrExportNotReady)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   service.ErrExportNotReady.Error(),
+		},
+		{
+			name:   "download service error",
+			method: "GET",
+			path:   "/admin/exports/3/download",
+			setupMocks: func(ms *mocks.MockExportService) {
+				ms.On("DownloadExport", mock.Anything, 3).Return(model.DataExportInRepo{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+		{
+			name:           "invalid export id",
+			method:         "GET",
+			path:           "/admin/exports/abc",
+			setupMocks:     func(ms *mocks.MockExportService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockExportService(t)
+			handler := NewExportHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.GET("/me/export", handler.ExportMe)
+			router.POST("/admin/users/:id/export", handler.RequestExport)
+			router.GET("/admin/exports/:id", handler.GetExport)
+			router.GET("/admin/exports/:id/download", handler.Download)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,17 +1,35 @@
 package handlers
 
-import "EventBooker/internal/service"
+import (
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/service"
+)
 
 type Handlers struct {
-	Event   *EventHandler
-	Booking *BookingHandler
-	User    *UserHandler
+	Event        *EventHandler
+	Booking      *BookingHandler
+	User         *UserHandler
+	Waitlist     *WaitlistHandler
+	Notification *NotificationHandler
+	Export       *ExportHandler
+	Payment      *PaymentHandler
+	PromoCode    *PromoCodeHandler
+	Venue        *VenueHandler
+	Auth         ginext.HandlerFunc
 }
 
 func NewHandlers(services *service.Services) *Handlers {
 	return &Handlers{
-		Event:   NewEventHandler(services.Event),
-		Booking: NewBookingService(services.Booking),
-		User:    NewUserHandler(services.User),
+		Event:        NewEventHandler(services.Event),
+		Booking:      NewBookingService(services.Booking),
+		User:         NewUserHandler(services.User),
+		Waitlist:     NewWaitlistHandler(services.Waitlist),
+		Notification: NewNotificationHandler(services.Notification),
+		Export:       NewExportHandler(services.Export),
+		Payment:      NewPaymentHandler(services.Payment),
+		PromoCode:    NewPromoCodeHandler(services.PromoCode),
+		Venue:        NewVenueHandler(services.Venue),
+		Auth:         AuthMiddleware(services.User),
 	}
 }
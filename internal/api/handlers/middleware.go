@@ -1,26 +1,49 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/wb-go/wbf/ginext"
 
+	"EventBooker/internal/model"
 	"EventBooker/internal/service"
 )
 
-func AdminMiddleware() ginext.HandlerFunc {
+// RequirePermission lets through users whose token carries the permission.
+func RequirePermission(permission string) ginext.HandlerFunc {
+	return RequireAnyPermission(permission)
+}
+
+// RequireAnyPermission lets through users whose token carries at least one of
+// the permissions.
+func RequireAnyPermission(permissions ...string) ginext.HandlerFunc {
 	return func(c *ginext.Context) {
-		role, exists := c.Get("role")
-		if !exists || role.(string) != "admin" {
-			NewErrorResponse(c, http.StatusForbidden, "admin success required")
-			return
+		granted := c.GetStringSlice("permissions")
+		for _, p := range permissions {
+			if slices.Contains(granted, p) {
+				c.Next()
+				return
+			}
 		}
-		c.Next()
+		NewErrorResponse(c, http.StatusForbidden, "permission required: "+strings.Join(permissions, " or "))
+	}
+}
+
+func actorFromContext(c *ginext.Context) model.Actor {
+	return model.Actor{
+		UserID:      c.GetInt("userID"),
+		Role:        c.GetString("role"),
+		Permissions: c.GetStringSlice("permissions"),
 	}
 }
 
-func AuthMiddleware() ginext.HandlerFunc {
+// AuthMiddleware accepts access tokens whose session is still active, so a
+// logout or revocation takes effect immediately rather than at token expiry.
+func AuthMiddleware(userService service.UserService) ginext.HandlerFunc {
 	return func(c *ginext.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -34,14 +57,23 @@ func AuthMiddleware() ginext.HandlerFunc {
 			return
 		}
 
-		claims, err := service.ValidateToken(tokenString)
+		claims, err := userService.Authenticate(context.Background(), tokenString)
 		if err != nil {
-			NewErrorResponse(c, http.StatusUnauthorized, "invalid or expired token")
+			if errors.Is(err, service.ErrSessionRevoked) {
+				NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+				return
+			}
+			if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrExpiredToken) {
+				NewErrorResponse(c, http.StatusUnauthorized, "invalid or expired token")
+				return
+			}
+			NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("permissions", claims.Permissions)
 		c.Set("email", claims.Email)
 		c.Next()
 
@@ -53,7 +85,7 @@ func CORSMiddleware() ginext.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Authorization, Accept, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
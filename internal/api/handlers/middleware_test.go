@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "active session",
+			header: "Bearer access",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Authenticate", mock.Anything, "access").
+					Return(&service.Claims{UserID: 7, Role: "organizer", Permissions: []string{"events:write"}, SessionID: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "7 [events:write]",
+		},
+		{
+			name:           "missing header",
+			setupMocks:     func(ms *mocks.MockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "authorization header required",
+		},
+		{
+			name:   "revoked session",
+			header: "Bearer access",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Authenticate", mock.Anything, "access").Return(nil, service.ErrSessionRevoked)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   service.ErrSessionRevoked.Error(),
+		},
+		{
+			name:   "expired token",
+			header: "Bearer access",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Authenticate", mock.Anything, "access").Return(nil, service.ErrExpiredToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "invalid or expired token",
+		},
+		{
+			name:   "storage error",
+			header: "Bearer access",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Authenticate", mock.Anything, "access").Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			router := setupUserTestRouter()
+
+			router.Use(AuthMiddleware(mockService))
+			router.GET("/me", func(c *ginext.Context) {
+				c.String(http.StatusOK, "%d %v", c.GetInt("userID"), c.GetStringSlice("permissions"))
+			})
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		permissions    []string
+		expectedStatus int
+	}{
+		{name: "granted", permissions: []string{"events:write", "users:read"}, expectedStatus: http.StatusOK},
+		{name: "other permission", permissions: []string{"users:read"}, expectedStatus: http.StatusForbidden},
+		{name: "no permissions", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupUserTestRouter()
+
+			router.Use(func(c *ginext.Context) {
+				if tt.permissions != nil {
+					c.Set("permissions", tt.permissions)
+				}
+				c.Next()
+			})
+			router.Use(RequirePermission("events:write"))
+			router.GET("/events", func(c *ginext.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/events", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(s service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: s}
+}
+
+func (h *NotificationHandler) GetList(c *ginext.Context) {
+	lastCreatedAtStr := c.Query("last_created_at")
+	lastCreatedAt, err := time.Parse(time.RFC3339, lastCreatedAtStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lastIDStr := c.Query("last_id")
+	lastID, err := strconv.Atoi(lastIDStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pageSizeStr := c.Query("page_size")
+	pageSize, err := strconv.Atoi(pageSizeStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req := model.NotificationGetRequest{
+		Status:        c.Query("status"),
+		Mode:          c.Query("mode"),
+		LastCreatedAt: lastCreatedAt,
+		LastID:        lastID,
+		PageSize:      pageSize,
+	}
+
+	n, err := h.notificationService.GetList(context.Background(), req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	count, err := h.notificationService.GetCount(context.Background(), req.Status)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"count":         count,
+		"notifications": n,
+	})
+}
+
+func (h *NotificationHandler) Resend(c *ginext.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.notificationService.Resend(context.Background(), id)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "notification queued for resend")
+}
+
+func (h *NotificationHandler) GetPreferences(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	prefs, err := h.notificationService.GetPreferences(context.Background(), userID)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"preferences": prefs,
+	})
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	var req model.NotificationPreferencesUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.notificationService.UpdatePreferences(context.Background(), userID, req.Preferences)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "notification preferences updated")
+}
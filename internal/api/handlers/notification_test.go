@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestNotificationHandler_GetList(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name           string
+		queryParams    map[string]string
+		setupMocks     func(ms *mocks.MockNotificationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success failed only",
+			queryParams: map[string]string{
+				"last_created_at": now.Format(time.RFC3339),
+				"last_id":         "0",
+				"page_size":       "10",
+				"mode":            "prev",
+				"status":          "failed",
+			},
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				n := []model.NotificationInResponse{
+					{ID: 3, UserID: 5, Channel: "telegram", Recipient: "77", Text: "hi", Status: "failed", Attempts: 5, LastError: "chat not found"},
+				}
+				ms.On("GetList", mock.Anything, mock.MatchedBy(func(req model.NotificationGetRequest) bool {
+					return req.Status == "failed" && req.PageSize == 10 && req.Mode == "prev"
+				})).Return(n, nil)
+				ms.On("GetCount", mock.Anything, "failed").Return(1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"last_error":"chat not found"`,
+		},
+		{
+			name: "invalid page size",
+			queryParams: map[string]string{
+				"last_created_at": now.Format(time.RFC3339),
+				"last_id":         "0",
+				"page_size":       "abc",
+			},
+			setupMocks:     func(ms *mocks.MockNotificationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name: "invalid status",
+			queryParams: map[string]string{
+				"last_created_at": now.Format(time.RFC3339),
+				"last_id":         "0",
+				"page_size":       "10",
+				"mode":            "next",
+				"status":          "lost",
+			},
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("GetList", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidNotificationStatus)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   service.ErrInvalidNotificationStatus.Error(),
+		},
+		{
+			name: "count error",
+			queryParams: map[string]string{
+				"last_created_at": now.Format(time.RFC3339),
+				"last_id":         "0",
+				"page_size":       "10",
+				"mode":            "next",
+			},
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("GetList", mock.Anything, mock.Anything).Return([]model.NotificationInResponse{}, nil)
+				ms.On("GetCount", mock.Anything, "").Return(0, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockNotificationService(t)
+			handler := NewNotificationHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.GET("/notifications", handler.GetList)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", buildURL("/notifications", tt.queryParams), nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_Resend(t *testing.T) {
+	tests := []struct {
+		name           string
+		idStr          string
+		setupMocks     func(ms *mocks.MockNotificationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			idStr: "7",
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("Resend", mock.Anything, 7).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "notification queued for resend",
+		},
+		{
+			name:           "invalid id",
+			idStr:          "abc",
+			setupMocks:     func(ms *mocks.MockNotificationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:  "not failed",
+			idStr: "8",
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("Resend", mock.Anything, 8).Return(service.ErrNotificationNotFailed)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   service.ErrNotificationNotFailed.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockNotificationService(t)
+			handler := NewNotificationHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.POST("/notifications/:id/resend", handler.Resend)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/notifications/"+tt.idStr+"/resend", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_GetPreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(ms *mocks.MockNotificationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				prefs := []model.NotificationPreference{
+					{EventType: "booking_expired", Channels: []string{"telegram", "email"}},
+					{EventType: "event_reminder", Channels: []string{}},
+				}
+				ms.On("GetPreferences", mock.Anything, 42).Return(prefs, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"event_type":"booking_expired","channels":["telegram","email"]}`,
+		},
+		{
+			name: "service error",
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("GetPreferences", mock.Anything, 42).Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   service.ErrUserNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockNotificationService(t)
+			handler := NewNotificationHandler(mockService)
+			router := setupTestRouter(42)
+
+			router.GET("/me/notifications", handler.GetPreferences)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/me/notifications", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_UpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMocks     func(ms *mocks.MockNotificationService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			body: `{"preferences":[{"event_type":"booking_expired","channels":["email"]}]}`,
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("UpdatePreferences", mock.Anything, 42, mock.MatchedBy(func(p []model.NotificationPreference) bool {
+					return len(p) == 1 && p[0].EventType == "booking_expired" &&
+						len(p[0].Channels) == 1 && p[0].Channels[0] == "email"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "notification preferences updated",
+		},
+		{
+			name:           "invalid json",
+			body:           `{"preferences":`,
+			setupMocks:     func(ms *mocks.MockNotificationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unexpected EOF",
+		},
+		{
+			name: "invalid channel",
+			body: `{"preferences":[{"event_type":"booking_expired","channels":["sms"]}]}`,
+			setupMocks: func(ms *mocks.MockNotificationService) {
+				ms.On("UpdatePreferences", mock.Anything, 42, mock.Anything).Return(service.ErrInvalidNotificationChannel)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   service.ErrInvalidNotificationChannel.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockNotificationService(t)
+			handler := NewNotificationHandler(mockService)
+			router := setupTestRouter(42)
+
+			router.PUT("/me/notifications", handler.UpdatePreferences)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/me/notifications", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
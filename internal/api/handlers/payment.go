@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/service"
+)
+
+// maxWebhookBodySize caps webhook bodies; provider callbacks are small JSON
+// documents.
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(s service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: s}
+}
+
+func (h *PaymentHandler) Pay(c *ginext.Context) {
+	userID := c.GetInt("userID")
+	eventID, err := strconv.Atoi(c.Param("event_id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payment, err := h.paymentService.Pay(context.Background(), bookID, eventID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrBookingNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrBookingNotPending) || errors.Is(err, service.ErrBookingExpired) ||
+			errors.Is(err, service.ErrPaymentNotRequired) || errors.Is(err, service.ErrPaymentExists) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPaymentUnavailable) {
+			NewErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// Webhook receives payment results. The body is passed on unparsed, since
+// providers sign the exact bytes they send.
+func (h *PaymentHandler) Webhook(c *ginext.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize)
+	body, err := c.GetRawData()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			NewErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.paymentService.HandleWebhook(context.Background(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentProviderNotFound) || errors.Is(err, service.ErrPaymentNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidWebhook) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "ok")
+}
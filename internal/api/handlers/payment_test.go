@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestPaymentHandler_Pay(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMocks     func(ms *mocks.MockPaymentService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "payment started",
+			path: "/events/7/pay/3",
+			setupMocks: func(ms *mocks.MockPaymentService) {
+				ms.On("Pay", mock.Anything, 3, 7, 42).Return(model.PaymentInResponse{
+					ID: 1, BookingID: 3, Provider: "fake", Amount: 300000, Currency: "RUB", Status: "pending",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"amount":300000`,
+		},
+		{
+			name:           "invalid booking id",
+			path:           "/events/7/pay/abc",
+			setupMocks:     func(ms *mocks.MockPaymentService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name: "free booking",
+			path: "/events/7/pay/3",
+			setupMocks: func(ms *mocks.MockPaymentService) {
+				ms.On("Pay", mock.Anything, 3, 7, 42).Return(model.PaymentInResponse{}, service.ErrPaymentNotRequired)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "booking is free",
+		},
+		{
+			name: "another payment",
+			path: "/events/7/pay/3",
+			setupMocks: func(ms *mocks.MockPaymentService) {
+				ms.On("Pay", mock.Anything, 3, 7, 42).Return(model.PaymentInResponse{}, service.ErrPaymentExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "another payment",
+		},
+		{
+			name: "payments disabled",
+			path: "/events/7/pay/3",
+			setupMocks: func(ms *mocks.MockPaymentService) {
+				ms.On("Pay", mock.Anything, 3, 7, 42).Return(model.PaymentInResponse{}, service.ErrPaymentUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPaymentService(t)
+			handler := NewPaymentHandler(mockService)
+			router := setupTestRouter(42)
+			router.POST("/events/:event_id/pay/:book_id", handler.Pay)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentHandler_Webhook(t *testing.T) {
+	body := `{"payment_id":"fake_1","status":"succeeded"}`
+
+	tests := []struct {
+		name           string
+		provider       string
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"accepted", "fake", nil, http.StatusOK, `"ok"`},
+		{"bad signature", "fake", service.ErrInvalidWebhook, http.StatusBadRequest, "signature"},
+		{"unknown provider", "stripe", service.ErrPaymentProviderNotFound, http.StatusNotFound, "unknown payment provider"},
+		{"unknown payment", "fake", service.ErrPaymentNotFound, http.StatusNotFound, "payment not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPaymentService(t)
+			handler := NewPaymentHandler(mockService)
+			router := setupTestRouter(0)
+			router.POST("/webhooks/payments/:provider", handler.Webhook)
+
+			mockService.On("HandleWebhook", mock.Anything, tt.provider,
+				mock.MatchedBy(func(h http.Header) bool { return h.Get(service.FakeSignatureHeader) == "abc" }),
+				[]byte(body)).Return(tt.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/webhooks/payments/"+tt.provider, strings.NewReader(body))
+			req.Header.Set(service.FakeSignatureHeader, "abc")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentHandler_WebhookBodyTooLarge(t *testing.T) {
+	mockService := mocks.NewMockPaymentService(t)
+	handler := NewPaymentHandler(mockService)
+	router := setupTestRouter(0)
+	router.POST("/webhooks/payments/:provider", handler.Webhook)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks/payments/fake",
+		strings.NewReader(strings.Repeat("x", maxWebhookBodySize+1)))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "status code should match")
+	mockService.AssertNotCalled(t, "HandleWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
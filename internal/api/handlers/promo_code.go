@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+)
+
+type PromoCodeHandler struct {
+	promoCodeService service.PromoCodeService
+}
+
+func NewPromoCodeHandler(s service.PromoCodeService) *PromoCodeHandler {
+	return &PromoCodeHandler{promoCodeService: s}
+}
+
+func (h *PromoCodeHandler) Create(c *ginext.Context) {
+	var p model.PromoCodeInput
+	err := c.ShouldBindJSON(&p)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promo, err := h.promoCodeService.Create(context.Background(), p)
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+func (h *PromoCodeHandler) GetList(c *ginext.Context) {
+	codes, err := h.promoCodeService.GetList(context.Background())
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"promo_codes": codes,
+	})
+}
+
+func (h *PromoCodeHandler) Get(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promo, err := h.promoCodeService.GetByID(context.Background(), id)
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+func (h *PromoCodeHandler) Update(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var p model.PromoCodeInput
+	err = c.ShouldBindJSON(&p)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promo, err := h.promoCodeService.Update(context.Background(), id, p)
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+func (h *PromoCodeHandler) Delete(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.promoCodeService.Delete(context.Background(), id)
+	if err != nil {
+		promoCodeError(c, err)
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "promo code deleted")
+}
+
+func promoCodeError(c *ginext.Context, err error) {
+	if errors.Is(err, service.ErrPromoCodeNotFound) || errors.Is(err, service.ErrEventNotFound) ||
+		errors.Is(err, service.ErrTicketTypeNotFound) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrPromoCodeExists) || errors.Is(err, service.ErrPromoCodeInUse) ||
+		errors.Is(err, service.ErrPromoLimitBelowUsed) {
+		NewErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrInvalidPromoCode) || errors.Is(err, service.ErrInvalidDiscount) ||
+		errors.Is(err, service.ErrInvalidCurrency) || errors.Is(err, service.ErrInvalidPromoLimits) ||
+		errors.Is(err, service.ErrInvalidPromoPeriod) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+}
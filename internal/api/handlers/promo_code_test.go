@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestPromoCodeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMocks     func(ms *mocks.MockPromoCodeService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/promo-codes",
+			body:   `{"code":"summer","discount_type":"percent","discount_value":10}`,
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("Create", mock.Anything, mock.MatchedBy(func(p model.PromoCodeInput) bool {
+					return p.Code == "summer" && p.DiscountValue == 10
+				})).Return(model.PromoCodeInResponse{ID: 1, Code: "SUMMER"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"code":"SUMMER"`,
+		},
+		{
+			name:   "create duplicate",
+			method: "POST",
+			path:   "/promo-codes",
+			body:   `{"code":"summer","discount_type":"percent","discount_value":10}`,
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("Create", mock.Anything, mock.Anything).Return(model.PromoCodeInResponse{}, service.ErrPromoCodeExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "already exists",
+		},
+		{
+			name:   "create invalid discount",
+			method: "POST",
+			path:   "/promo-codes",
+			body:   `{"code":"summer","discount_type":"percent","discount_value":150}`,
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("Create", mock.Anything, mock.Anything).Return(model.PromoCodeInResponse{}, service.ErrInvalidDiscount)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "discount must be",
+		},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/promo-codes",
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("GetList", mock.Anything).Return([]model.PromoCodeInResponse{{ID: 1, Code: "SUMMER"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"promo_codes":[`,
+		},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   "/promo-codes/9",
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("GetByID", mock.Anything, 9).Return(model.PromoCodeInResponse{}, service.ErrPromoCodeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "promo code not found",
+		},
+		{
+			name:   "update below used",
+			method: "PUT",
+			path:   "/promo-codes/1",
+			body:   `{"code":"summer","discount_type":"percent","discount_value":10,"max_uses":1}`,
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("Update", mock.Anything, 1, mock.Anything).Return(model.PromoCodeInResponse{}, service.ErrPromoLimitBelowUsed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "max_uses",
+		},
+		{
+			name:   "delete used",
+			method: "DELETE",
+			path:   "/promo-codes/1",
+			setupMocks: func(ms *mocks.MockPromoCodeService) {
+				ms.On("Delete", mock.Anything, 1).Return(service.ErrPromoCodeInUse)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "cannot be deleted",
+		},
+		{
+			name:           "invalid id",
+			method:         "DELETE",
+			path:           "/promo-codes/abc",
+			setupMocks:     func(ms *mocks.MockPromoCodeService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockPromoCodeService(t)
+			handler := NewPromoCodeHandler(mockService)
+			router := setupTestRouter(1)
+			router.POST("/promo-codes", handler.Create)
+			router.GET("/promo-codes", handler.GetList)
+			router.GET("/promo-codes/:id", handler.Get)
+			router.PUT("/promo-codes/:id", handler.Update)
+			router.DELETE("/promo-codes/:id", handler.Delete)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -29,15 +31,22 @@ func (h *UserHandler) Register(c *ginext.Context) {
 		return
 	}
 
-	token, err := h.userService.CreateUser(context.Background(), u)
+	tokens, err := h.userService.CreateUser(context.Background(), u)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidEmail) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusCreated, ginext.H{
-		"token": token,
-	})
+	if tokens.Token == "" {
+		NewSuccessResponse(c, http.StatusCreated, "check your email to verify the account")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tokens)
 
 }
 
@@ -50,14 +59,276 @@ func (h *UserHandler) Login(c *ginext.Context) {
 		return
 	}
 
-	token, err := h.userService.Login(context.Background(), u)
+	u.IP = c.ClientIP()
+
+	tokens, err := h.userService.Login(context.Background(), u)
+	if err != nil {
+		var locked *service.LoginLockedError
+		if errors.As(err, &locked) {
+			retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			NewErrorResponse(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) Refresh(c *ginext.Context) {
+	var req model.RefreshRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.userService.Refresh(context.Background(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) Logout(c *ginext.Context) {
+	var req model.RefreshRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.Logout(context.Background(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			NewErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "logged out")
+}
+
+func (h *UserHandler) VerifyEmail(c *ginext.Context) {
+	var req model.EmailVerifyRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.VerifyEmail(context.Background(), req.Token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVerifyToken) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "email verified")
+}
+
+func (h *UserHandler) ResendVerification(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	err := h.userService.ResendVerification(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "verification email sent")
+}
+
+func (h *UserHandler) ForgotPassword(c *ginext.Context) {
+	var req model.PasswordForgotRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.RequestPasswordReset(context.Background(), req.Email)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "if the account exists, a reset code has been sent")
+}
+
+func (h *UserHandler) ResetPassword(c *ginext.Context) {
+	var req model.PasswordResetRequest
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.ResetPassword(context.Background(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, service.ErrWeakPassword) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "password has been reset")
+}
+
+func (h *UserHandler) ChangePassword(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	var req model.PasswordChangeRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.userService.ChangePassword(context.Background(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrWrongPassword) || errors.Is(err, service.ErrWeakPassword) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) Unlock(c *ginext.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.UnlockUser(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "user unlocked")
+}
+
+func (h *UserHandler) ChangeRole(c *ginext.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req model.RoleUpdate
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.ChangeRole(context.Background(), c.GetInt("userID"), userID, req.Role)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRole) || errors.Is(err, service.ErrOwnRole) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "role changed")
+}
+
+func (h *UserHandler) GetRoles(c *ginext.Context) {
+	roles, err := h.userService.GetRolePermissions(context.Background())
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"roles": roles,
+	})
+}
+
+func (h *UserHandler) SetRolePermissions(c *ginext.Context) {
+	var req model.RolePermissionsUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.SetRolePermissions(context.Background(), c.Param("role"), req.Permissions)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRole) || errors.Is(err, service.ErrInvalidPermission) ||
+			errors.Is(err, service.ErrAdminPermissionRequired) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "role permissions updated")
+}
+
+func (h *UserHandler) RevokeSessions(c *ginext.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count, err := h.userService.RevokeSessions(context.Background(), userID)
 	if err != nil {
 		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	c.JSON(http.StatusOK, ginext.H{
-		"token": token,
+		"revoked": count,
 	})
 }
 
@@ -109,3 +380,107 @@ func (h *UserHandler) GetList(c *ginext.Context) {
 		"users": u,
 	})
 }
+
+func (h *UserHandler) CreateTgLink(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	link, err := h.userService.CreateTgLinkCode(context.Background(), userID)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+func (h *UserHandler) UnlinkTelegram(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	err := h.userService.UnlinkTelegram(context.Background(), userID)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "telegram unlinked")
+}
+
+func (h *UserHandler) GetProfile(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	user, err := h.userService.GetProfile(context.Background(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) UpdateProfile(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	var req model.ProfileUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(context.Background(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEmail) || errors.Is(err, service.ErrPasswordMismatch) ||
+			errors.Is(err, service.ErrInvalidName) || errors.Is(err, service.ErrInvalidPhone) ||
+			errors.Is(err, service.ErrInvalidLocale) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrEmailTaken) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) DeleteAccount(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	var req model.AccountDeleteRequest
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.userService.DeleteAccount(context.Background(), userID, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrPasswordMismatch) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrAdminAccountDelete) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "account deleted")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/wb-go/wbf/ginext"
 
 	"EventBooker/internal/model"
+	"EventBooker/internal/service"
 	"EventBooker/internal/service/mocks"
 )
 
@@ -38,10 +40,10 @@ func TestRegister(t *testing.T) {
 			setupMocks: func(ms *mocks.MockUserService) {
 				ms.On("CreateUser", mock.Anything, mock.MatchedBy(func(u model.UserInCreate) bool {
 					return u.Email == "test@mail.com"
-				})).Return("jwt_token_test", nil)
+				})).Return(model.AuthTokens{Token: "jwt_token_test", RefreshToken: "refresh_test"}, nil)
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `"token":"jwt_token_test"`,
+			expectedBody:   `"token":"jwt_token_test","expires_at"`,
 		},
 
 		{
@@ -51,11 +53,550 @@ func TestRegister(t *testing.T) {
 				Password: "pass",
 			},
 			setupMocks: func(ms *mocks.MockUserService) {
-				ms.On("CreateUser", mock.Anything, mock.Anything).Return("", errors.New("db failed"))
+				ms.On("CreateUser", mock.Anything, mock.Anything).Return(model.AuthTokens{}, errors.New("db failed"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "db failed",
 		},
+		{
+			name: "invalid email",
+			requestBody: model.UserInCreate{
+				Email:    "not-an-email",
+				Password: "securepass",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("CreateUser", mock.Anything, mock.Anything).Return(model.AuthTokens{}, service.ErrInvalidEmail)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidEmail.Error(),
+		},
+		{
+			name: "verification required before login",
+			requestBody: model.UserInCreate{
+				Email:    "strict@mail.com",
+				Password: "securepass",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("CreateUser", mock.Anything, mock.Anything).Return(model.AuthTokens{}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "check your email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.POST("/register", handler.Register)
+			tt.setupMocks(mockService)
+
+			body, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/register", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Login(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    model.UserLoginRequest
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success login",
+			requestBody: model.UserLoginRequest{
+				Email:    "user@mail.com",
+				Password: "mypassword",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Login", mock.Anything, mock.MatchedBy(func(u model.UserLoginRequest) bool {
+					return u.Email == "user@mail.com"
+				})).Return(model.AuthTokens{Token: "jwt_token_login", RefreshToken: "refresh_login"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"refresh_token":"refresh_login"`,
+		},
+
+		{
+			name: "login error",
+			requestBody: model.UserLoginRequest{
+				Email:    "unknown@mail.com",
+				Password: "nopass",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Login", mock.Anything, mock.Anything).Return(model.AuthTokens{}, errors.New("invalid credentials"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "invalid credentials",
+		},
+		{
+			name: "email not verified",
+			requestBody: model.UserLoginRequest{
+				Email:    "strict@mail.com",
+				Password: "mypassword",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Login", mock.Anything, mock.Anything).Return(model.AuthTokens{}, service.ErrEmailNotVerified)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   service.ErrEmailNotVerified.Error(),
+		},
+		{
+			name: "locked out",
+			requestBody: model.UserLoginRequest{
+				Email:    "user@mail.com",
+				Password: "guess",
+			},
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Login", mock.Anything, mock.Anything).
+					Return(model.AuthTokens{}, &service.LoginLockedError{Until: time.Now().Add(90 * time.Second)})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   service.ErrLoginLocked.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.POST("/login", handler.Login)
+			tt.setupMocks(mockService)
+
+			body, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "success",
+			requestBody: `{"refresh_token":"old_refresh"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Refresh", mock.Anything, "old_refresh").
+					Return(model.AuthTokens{Token: "new_access", RefreshToken: "new_refresh"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"refresh_token":"new_refresh"`,
+		},
+		{
+			name:        "rotated token",
+			requestBody: `{"refresh_token":"old_refresh"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Refresh", mock.Anything, "old_refresh").Return(model.AuthTokens{}, service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   service.ErrInvalidRefreshToken.Error(),
+		},
+		{
+			name:           "invalid json",
+			requestBody:    `{"refresh_token":`,
+			setupMocks:     func(ms *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.POST("/refresh", handler.Refresh)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/refresh", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Logout", mock.Anything, "refresh").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "logged out",
+		},
+		{
+			name: "unknown token",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("Logout", mock.Anything, "refresh").Return(service.ErrInvalidRefreshToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   service.ErrInvalidRefreshToken.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.POST("/logout", handler.Logout)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/logout", bytes.NewBufferString(`{"refresh_token":"refresh"}`))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_EmailVerification(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		requestBody    string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "verify",
+			path:        "/verify",
+			requestBody: `{"token":"verify"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("VerifyEmail", mock.Anything, "verify").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "email verified",
+		},
+		{
+			name:        "verify with expired token",
+			path:        "/verify",
+			requestBody: `{"token":"verify"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("VerifyEmail", mock.Anything, "verify").Return(service.ErrInvalidVerifyToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidVerifyToken.Error(),
+		},
+		{
+			name: "resend",
+			path: "/me/email/verify",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ResendVerification", mock.Anything, 1).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "verification email sent",
+		},
+		{
+			name: "resend when verified",
+			path: "/me/email/verify",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ResendVerification", mock.Anything, 1).Return(service.ErrEmailAlreadyVerified)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   service.ErrEmailAlreadyVerified.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.POST("/verify", handler.VerifyEmail)
+			router.POST("/me/email/verify", handler.ResendVerification)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_PasswordFlows(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		requestBody    string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "forgot password",
+			method:      "POST",
+			path:        "/password/forgot",
+			requestBody: `{"email":"user@mail.com"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("RequestPasswordReset", mock.Anything, "user@mail.com").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "reset code has been sent",
+		},
+		{
+			name:        "reset password",
+			method:      "POST",
+			path:        "/password/reset",
+			requestBody: `{"token":"reset","new_password":"newsecret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ResetPassword", mock.Anything, model.PasswordResetRequest{Token: "reset", NewPassword: "newsecret"}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "password has been reset",
+		},
+		{
+			name:        "reset with used token",
+			method:      "POST",
+			path:        "/password/reset",
+			requestBody: `{"token":"reset","new_password":"newsecret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ResetPassword", mock.Anything, mock.Anything).Return(service.ErrInvalidResetToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidResetToken.Error(),
+		},
+		{
+			name:        "change password",
+			method:      "PUT",
+			path:        "/me/password",
+			requestBody: `{"old_password":"secret","new_password":"newsecret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangePassword", mock.Anything, 1, model.PasswordChangeRequest{OldPassword: "secret", NewPassword: "newsecret"}).
+					Return(model.AuthTokens{Token: "new_access", RefreshToken: "new_refresh"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"refresh_token":"new_refresh"`,
+		},
+		{
+			name:        "change password wrong old password",
+			method:      "PUT",
+			path:        "/me/password",
+			requestBody: `{"old_password":"wrong","new_password":"newsecret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangePassword", mock.Anything, 1, mock.Anything).Return(model.AuthTokens{}, service.ErrWrongPassword)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrWrongPassword.Error(),
+		},
+		{
+			name:        "change password service error",
+			method:      "PUT",
+			path:        "/me/password",
+			requestBody: `{"old_password":"secret","new_password":"newsecret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangePassword", mock.Anything, 1, mock.Anything).Return(model.AuthTokens{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.POST("/password/forgot", handler.ForgotPassword)
+			router.POST("/password/reset", handler.ResetPassword)
+			router.PUT("/me/password", handler.ChangePassword)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_LoginRetryAfter(t *testing.T) {
+	mockService := mocks.NewMockUserService(t)
+	handler := NewUserHandler(mockService)
+	router := setupUserTestRouter()
+	router.POST("/login", handler.Login)
+
+	mockService.On("Login", mock.Anything, mock.Anything).
+		Return(model.AuthTokens{}, &service.LoginLockedError{Until: time.Now().Add(90 * time.Second)})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"email":"user@mail.com","password":"guess"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+}
+
+func TestUserHandler_Unlock(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "success",
+			userID: "5",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UnlockUser", mock.Anything, 5).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user unlocked",
+		},
+		{
+			name:   "unknown user",
+			userID: "5",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UnlockUser", mock.Anything, 5).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   service.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "invalid id",
+			userID:         "abc",
+			setupMocks:     func(ms *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.DELETE("/users/:id/lockout", handler.Unlock)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("DELETE", "/users/"+tt.userID+"/lockout", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_ChangeRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "success",
+			userID: "5",
+			body:   `{"role":"organizer"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangeRole", mock.Anything, 1, 5, model.RoleOrganizer).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "role changed",
+		},
+		{
+			name:   "unknown role",
+			userID: "5",
+			body:   `{"role":"owner"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangeRole", mock.Anything, 1, 5, "owner").Return(service.ErrInvalidRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidRole.Error(),
+		},
+		{
+			name:   "own role",
+			userID: "1",
+			body:   `{"role":"user"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangeRole", mock.Anything, 1, 1, model.RoleUser).Return(service.ErrOwnRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrOwnRole.Error(),
+		},
+		{
+			name:   "unknown user",
+			userID: "5",
+			body:   `{"role":"admin"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("ChangeRole", mock.Anything, 1, 5, model.RoleAdmin).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   service.ErrUserNotFound.Error(),
+		},
+		{
+			name:           "invalid id",
+			userID:         "abc",
+			body:           `{"role":"admin"}`,
+			setupMocks:     func(ms *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -64,14 +605,15 @@ func TestRegister(t *testing.T) {
 			handler := NewUserHandler(mockService)
 			router := setupUserTestRouter()
 
-			router.POST("/register", handler.Register)
+			router.Use(func(c *ginext.Context) {
+				c.Set("userID", 1)
+				c.Next()
+			})
+			router.PUT("/users/:id/role", handler.ChangeRole)
 			tt.setupMocks(mockService)
 
-			body, err := json.Marshal(tt.requestBody)
-			assert.NoError(t, err)
-
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("POST", "/register", bytes.NewReader(body))
+			req, _ := http.NewRequest("PUT", "/users/"+tt.userID+"/role", bytes.NewBufferString(tt.body))
 			req.Header.Set("Content-Type", "application/json")
 			router.ServeHTTP(w, req)
 
@@ -82,40 +624,119 @@ func TestRegister(t *testing.T) {
 	}
 }
 
-func TestUserHandler_Login(t *testing.T) {
+func TestUserHandler_RolePermissions(t *testing.T) {
 	tests := []struct {
 		name           string
-		requestBody    model.UserLoginRequest
+		method         string
+		path           string
+		body           string
 		setupMocks     func(ms *mocks.MockUserService)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
-			name: "success login",
-			requestBody: model.UserLoginRequest{
-				Email:    "user@mail.com",
-				Password: "mypassword",
+			name:   "list",
+			method: "GET",
+			path:   "/roles",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("GetRolePermissions", mock.Anything).Return([]model.RolePermissions{
+					{Role: model.RoleOrganizer, Permissions: []string{model.PermissionEventsWrite}},
+				}, nil)
 			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"permissions":["events:write"]`,
+		},
+		{
+			name:   "update",
+			method: "PUT",
+			path:   "/roles/organizer/permissions",
+			body:   `{"permissions":["events:write","users:read"]}`,
 			setupMocks: func(ms *mocks.MockUserService) {
-				ms.On("Login", mock.Anything, mock.MatchedBy(func(u model.UserLoginRequest) bool {
-					return u.Email == "user@mail.com"
-				})).Return("jwt_token_login", nil)
+				ms.On("SetRolePermissions", mock.Anything, model.RoleOrganizer,
+					[]string{model.PermissionEventsWrite, model.PermissionUsersRead}).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `"token":"jwt_token_login"`,
+			expectedBody:   "role permissions updated",
+		},
+		{
+			name:   "unknown permission",
+			method: "PUT",
+			path:   "/roles/organizer/permissions",
+			body:   `{"permissions":["events:delete"]}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("SetRolePermissions", mock.Anything, model.RoleOrganizer, []string{"events:delete"}).
+					Return(fmt.Errorf("%w: events:delete", service.ErrInvalidPermission))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unknown permission: events:delete",
+		},
+		{
+			name:   "admin keeps users:manage",
+			method: "PUT",
+			path:   "/roles/admin/permissions",
+			body:   `{"permissions":[]}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("SetRolePermissions", mock.Anything, model.RoleAdmin, []string{}).
+					Return(service.ErrAdminPermissionRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrAdminPermissionRequired.Error(),
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupUserTestRouter()
+
+			router.GET("/roles", handler.GetRoles)
+			router.PUT("/roles/:role/permissions", handler.SetRolePermissions)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
 
+func TestUserHandler_RevokeSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
 		{
-			name: "login error",
-			requestBody: model.UserLoginRequest{
-				Email:    "unknown@mail.com",
-				Password: "nopass",
+			name:   "success",
+			userID: "5",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("RevokeSessions", mock.Anything, 5).Return(int64(2), nil)
 			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"revoked":2`,
+		},
+		{
+			name:           "invalid id",
+			userID:         "abc",
+			setupMocks:     func(ms *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "service error",
+			userID: "5",
 			setupMocks: func(ms *mocks.MockUserService) {
-				ms.On("Login", mock.Anything, mock.Anything).Return("", errors.New("invalid credentials"))
+				ms.On("RevokeSessions", mock.Anything, 5).Return(int64(0), errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   "invalid credentials",
+			expectedBody:   "db error",
 		},
 	}
 
@@ -125,15 +746,11 @@ func TestUserHandler_Login(t *testing.T) {
 			handler := NewUserHandler(mockService)
 			router := setupUserTestRouter()
 
-			router.POST("/login", handler.Login)
+			router.DELETE("/users/:id/sessions", handler.RevokeSessions)
 			tt.setupMocks(mockService)
 
-			body, err := json.Marshal(tt.requestBody)
-			assert.NoError(t, err)
-
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("POST", "/login", bytes.NewReader(body))
-			req.Header.Set("Content-Type", "application/json")
+			req, _ := http.NewRequest("DELETE", "/users/"+tt.userID+"/sessions", nil)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
@@ -221,3 +838,225 @@ func TestUserHandler_GetList(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_CreateTgLink(t *testing.T) {
+	expiresAt := time.Now().Add(10 * time.Minute).UTC()
+	tests := []struct {
+		name           string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("CreateTgLinkCode", mock.Anything, 1).Return(model.TgLinkCodeInResponse{
+					Code:      "abc123",
+					Link:      "https://t.me/event_bot?start=abc123",
+					ExpiresAt: expiresAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"link":"https://t.me/event_bot?start=abc123"`,
+		},
+		{
+			name: "service error",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("CreateTgLinkCode", mock.Anything, 1).Return(model.TgLinkCodeInResponse{}, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.POST("/me/telegram/link", handler.CreateTgLink)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/me/telegram/link", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_UnlinkTelegram(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "success",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UnlinkTelegram", mock.Anything, 1).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "telegram unlinked",
+		},
+		{
+			name: "service error",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UnlinkTelegram", mock.Anything, 1).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.DELETE("/me/telegram", handler.UnlinkTelegram)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("DELETE", "/me/telegram", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Profile(t *testing.T) {
+	name := "Ivan"
+	email := "new@mail.com"
+
+	tests := []struct {
+		name           string
+		method         string
+		requestBody    string
+		setupMocks     func(ms *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "get profile",
+			method: "GET",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("GetProfile", mock.Anything, 1).Return(model.UserInResponse{ID: 1, Email: "user@mail.com", Name: &name}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name":"Ivan"`,
+		},
+		{
+			name:   "get deleted profile",
+			method: "GET",
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("GetProfile", mock.Anything, 1).Return(model.UserInResponse{}, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   service.ErrUserNotFound.Error(),
+		},
+		{
+			name:        "update profile",
+			method:      "PATCH",
+			requestBody: `{"name":"Ivan"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UpdateProfile", mock.Anything, 1, model.ProfileUpdate{Name: &name}).
+					Return(model.UserInResponse{ID: 1, Name: &name}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"name":"Ivan"`,
+		},
+		{
+			name:        "change email",
+			method:      "PATCH",
+			requestBody: `{"email":"new@mail.com","current_password":"secret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UpdateProfile", mock.Anything, 1, model.ProfileUpdate{Email: &email, CurrentPassword: "secret"}).
+					Return(model.UserInResponse{ID: 1, Email: email}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"email_verified":false`,
+		},
+		{
+			name:        "invalid phone",
+			method:      "PATCH",
+			requestBody: `{"phone":"call me"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UpdateProfile", mock.Anything, 1, mock.Anything).Return(model.UserInResponse{}, service.ErrInvalidPhone)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrInvalidPhone.Error(),
+		},
+		{
+			name:        "email taken",
+			method:      "PATCH",
+			requestBody: `{"email":"new@mail.com","current_password":"secret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("UpdateProfile", mock.Anything, 1, mock.Anything).Return(model.UserInResponse{}, service.ErrEmailTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   service.ErrEmailTaken.Error(),
+		},
+		{
+			name:        "delete account",
+			method:      "DELETE",
+			requestBody: `{"password":"secret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("DeleteAccount", mock.Anything, 1, "secret").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "account deleted",
+		},
+		{
+			name:        "delete with wrong password",
+			method:      "DELETE",
+			requestBody: `{"password":"wrong"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("DeleteAccount", mock.Anything, 1, "wrong").Return(service.ErrPasswordMismatch)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   service.ErrPasswordMismatch.Error(),
+		},
+		{
+			name:        "delete admin account",
+			method:      "DELETE",
+			requestBody: `{"password":"secret"}`,
+			setupMocks: func(ms *mocks.MockUserService) {
+				ms.On("DeleteAccount", mock.Anything, 1, "secret").Return(service.ErrAdminAccountDelete)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   service.ErrAdminAccountDelete.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockUserService(t)
+			handler := NewUserHandler(mockService)
+			router := setupTestRouter(1)
+
+			router.GET("/me", handler.GetProfile)
+			router.PATCH("/me", handler.UpdateProfile)
+			router.DELETE("/me", handler.DeleteAccount)
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/me", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+)
+
+type VenueHandler struct {
+	venueService service.VenueService
+}
+
+func NewVenueHandler(s service.VenueService) *VenueHandler {
+	return &VenueHandler{venueService: s}
+}
+
+func (h *VenueHandler) Create(c *ginext.Context) {
+	var v model.VenueInput
+	err := c.ShouldBindJSON(&v)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	venue, err := h.venueService.Create(context.Background(), v)
+	if err != nil {
+		venueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, venue)
+}
+
+func (h *VenueHandler) GetList(c *ginext.Context) {
+	venues, err := h.venueService.GetList(context.Background())
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"venues": venues,
+	})
+}
+
+func (h *VenueHandler) Get(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	venue, err := h.venueService.GetByID(context.Background(), id)
+	if err != nil {
+		venueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+func (h *VenueHandler) Update(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var v model.VenueInput
+	err = c.ShouldBindJSON(&v)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	venue, err := h.venueService.Update(context.Background(), id, v)
+	if err != nil {
+		venueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+func (h *VenueHandler) Delete(c *ginext.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.venueService.Delete(context.Background(), id)
+	if err != nil {
+		venueError(c, err)
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "venue deleted")
+}
+
+func venueError(c *ginext.Context, err error) {
+	if errors.Is(err, service.ErrVenueNotFound) {
+		NewErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrVenueInUse) {
+		NewErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrInvalidVenue) || errors.Is(err, service.ErrDuplicateSeat) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestVenueHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMocks     func(ms *mocks.MockVenueService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "create",
+			method: "POST",
+			path:   "/venues",
+			body:   `{"name":"Зал","sections":[{"name":"Партер","rows":[{"row":"1","seats":10,"accessible":[1]}]}]}`,
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("Create", mock.Anything, mock.MatchedBy(func(v model.VenueInput) bool {
+					return v.Name == "Зал" && len(v.Sections) == 1 && v.Sections[0].Rows[0].Seats == 10
+				})).Return(model.VenueInResponse{ID: 1, Name: "Зал", SeatCount: 10}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"seat_count":10`,
+		},
+		{
+			name:   "create duplicate row",
+			method: "POST",
+			path:   "/venues",
+			body:   `{"name":"Зал","sections":[{"name":"A","rows":[{"row":"1","seats":5},{"row":"1","seats":5}]}]}`,
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("Create", mock.Anything, mock.Anything).Return(model.VenueInResponse{}, service.ErrDuplicateSeat)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "must be unique",
+		},
+		{
+			name:   "list",
+			method: "GET",
+			path:   "/venues",
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("GetList", mock.Anything).Return([]model.VenueInResponse{{ID: 1, Name: "Зал"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"venues":[`,
+		},
+		{
+			name:   "get missing",
+			method: "GET",
+			path:   "/venues/9",
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("GetByID", mock.Anything, 9).Return(model.VenueInResponse{}, service.ErrVenueNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "venue not found",
+		},
+		{
+			name:   "update seats of used venue",
+			method: "PUT",
+			path:   "/venues/1",
+			body:   `{"name":"Зал","sections":[{"name":"A","rows":[{"row":"1","seats":5}]}]}`,
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("Update", mock.Anything, 1, mock.Anything).Return(model.VenueInResponse{}, service.ErrVenueInUse)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "used by events",
+		},
+		{
+			name:   "delete",
+			method: "DELETE",
+			path:   "/venues/1",
+			setupMocks: func(ms *mocks.MockVenueService) {
+				ms.On("Delete", mock.Anything, 1).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "venue deleted",
+		},
+		{
+			name:           "invalid id",
+			method:         "DELETE",
+			path:           "/venues/abc",
+			setupMocks:     func(ms *mocks.MockVenueService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockVenueService(t)
+			handler := NewVenueHandler(mockService)
+			router := setupTestRouter(1)
+			router.POST("/venues", handler.Create)
+			router.GET("/venues", handler.GetList)
+			router.GET("/venues/:id", handler.Get)
+			router.PUT("/venues/:id", handler.Update)
+			router.DELETE("/venues/:id", handler.Delete)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
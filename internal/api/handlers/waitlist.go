@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/wb-go/wbf/ginext"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+)
+
+type WaitlistHandler struct {
+	waitlistService service.WaitlistService
+}
+
+func NewWaitlistHandler(s service.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: s}
+}
+
+func (h *WaitlistHandler) Join(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	eventIDStr := c.Param("event_id")
+	eventID, err := strconv.Atoi(eventIDStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var w model.WaitlistInCreate
+	err = c.ShouldBindJSON(&w)
+	if err != nil && !errors.Is(err, io.EOF) {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.UserID = userID
+	w.EventID = eventID
+
+	err = h.waitlistService.Join(context.Background(), w)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			NewErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeNotFound) {
+			NewErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrTicketTypeRequired) {
+			NewErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrWaitlistSeated) {
+			NewErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusCreated, "added to waitlist")
+}
+
+func (h *WaitlistHandler) Leave(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	eventIDStr := c.Param("event_id")
+	eventID, err := strconv.Atoi(eventIDStr)
+	if err != nil {
+		NewErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.waitlistService.Leave(context.Background(), eventID, userID)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NewSuccessResponse(c, http.StatusOK, "removed from waitlist")
+}
+
+func (h *WaitlistHandler) GetList(c *ginext.Context) {
+	userID := c.GetInt("userID")
+
+	w, err := h.waitlistService.GetByUserID(context.Background(), userID)
+	if err != nil {
+		NewErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ginext.H{
+		"waitlist": w,
+	})
+}
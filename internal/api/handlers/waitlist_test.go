@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+func TestWaitlistHandler_Join(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventIDStr     string
+		body           string
+		userID         int
+		setupMocks     func(ms *mocks.MockWaitlistService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "success join",
+			eventIDStr: "15",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Join", mock.Anything, mock.MatchedBy(func(w model.WaitlistInCreate) bool {
+					return w.UserID == 42 && w.EventID == 15 && w.Quantity == 0
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"added to waitlist"`,
+		},
+		{
+			name:       "success join with quantity",
+			eventIDStr: "15",
+			body:       `{"quantity": 3}`,
+			userID:     42,
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Join", mock.Anything, mock.MatchedBy(func(w model.WaitlistInCreate) bool {
+					return w.UserID == 42 && w.EventID == 15 && w.Quantity == 3
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"added to waitlist"`,
+		},
+		{
+			name:           "invalid event id",
+			eventIDStr:     "invalid",
+			userID:         42,
+			setupMocks:     func(ms *mocks.MockWaitlistService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:       "seats still available",
+			eventIDStr: "15",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Join", mock.Anything, mock.Anything).Return(service.ErrSeatsAvailable)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "seats are available",
+		},
+		{
+			name:       "ticket type required",
+			eventIDStr: "15",
+			userID:     42,
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Join", mock.Anything, mock.Anything).Return(service.ErrTicketTypeRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "ticket_type_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockWaitlistService(t)
+			handler := NewWaitlistHandler(mockService)
+			router := setupTestRouter(tt.userID)
+			router.POST("/events/:event_id/waitlist", handler.Join)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/events/"+tt.eventIDStr+"/waitlist", strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWaitlistHandler_Leave(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventIDStr     string
+		setupMocks     func(ms *mocks.MockWaitlistService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "success leave",
+			eventIDStr: "15",
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Leave", mock.Anything, 15, 42).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"removed from waitlist"`,
+		},
+		{
+			name:           "invalid event id",
+			eventIDStr:     "abc",
+			setupMocks:     func(ms *mocks.MockWaitlistService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid syntax",
+		},
+		{
+			name:       "service error",
+			eventIDStr: "15",
+			setupMocks: func(ms *mocks.MockWaitlistService) {
+				ms.On("Leave", mock.Anything, 15, 42).Return(errors.New("database connection failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "database connection failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := mocks.NewMockWaitlistService(t)
+			handler := NewWaitlistHandler(mockService)
+			router := setupTestRouter(42)
+			router.DELETE("/events/:event_id/waitlist", handler.Leave)
+
+			tt.setupMocks(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("DELETE", "/events/"+tt.eventIDStr+"/waitlist", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, "status code should match")
+			assert.Contains(t, w.Body.String(), tt.expectedBody, "response body should contain expected text")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWaitlistHandler_GetList(t *testing.T) {
+	mockService := mocks.NewMockWaitlistService(t)
+	handler := NewWaitlistHandler(mockService)
+	router := setupTestRouter(42)
+	router.GET("/waitlist", handler.GetList)
+
+	now := time.Now().UTC()
+	mockService.On("GetByUserID", mock.Anything, 42).Return([]model.WaitlistInResponse{
+		{
+			ID:         1,
+			EventID:    10,
+			Quantity:   2,
+			Status:     model.StatusWaitlistWaiting,
+			Position:   3,
+			CreatedAt:  now,
+			EventTitle: "Sold Out Concert",
+			EventDate:  now.Add(48 * time.Hour),
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/waitlist", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Waitlist []model.WaitlistInResponse `json:"waitlist"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Waitlist, 1)
+	assert.Equal(t, 3, response.Waitlist[0].Position)
+	assert.Equal(t, "Sold Out Concert", response.Waitlist[0].EventTitle)
+
+	mockService.AssertExpectations(t)
+}
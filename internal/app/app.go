@@ -15,19 +15,18 @@ import (
 )
 
 type App struct {
-	Handler  *ginext.Engine
-	Port     string
-	Services *service.Services
-	Storage  *repository.Storage
-	TgBot    *service.TelegramBot
+	Handler   *ginext.Engine
+	Port      string
+	Services  *service.Services
+	Storage   *repository.Storage
+	TgBot     *service.TelegramBot
+	Notifiers []service.Notifier
 }
 
 func (a *App) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	msgForTgCh := make(chan service.RetryMessage, 100)
-
 	var wg sync.WaitGroup
 
 	server := http.Server{
@@ -44,13 +43,11 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}()
 
-	scheduler := service.NewSchedulerService(a.Storage.Booking)
+	scheduler := service.NewSchedulerService(a.Storage, a.Services.Waitlist)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		scheduler.Start(ctx, 1*time.Minute, msgForTgCh)
-		defer close(msgForTgCh)
-
+		scheduler.Start(ctx, 1*time.Minute)
 	}()
 
 	if a.TgBot != nil {
@@ -61,11 +58,14 @@ func (a *App) Run(ctx context.Context) error {
 		}()
 	}
 
-	for i := 0; i < 3; i++ {
+	// Messages for channels without a notifier stay pending and are
+	// delivered once the service is restarted with that channel configured.
+	if len(a.Notifiers) > 0 {
+		dispatcher := service.NewOutboxDispatcher(a.Storage, a.Notifiers...)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			go a.TgBot.RetryWorker(ctx, msgForTgCh)
+			dispatcher.Start(ctx, 5*time.Second)
 		}()
 	}
 
@@ -1,16 +1,28 @@
 package config
 
-import "github.com/wb-go/wbf/config"
+import (
+	"strings"
+
+	"github.com/wb-go/wbf/config"
+)
 
 type Config struct {
 	Postgre PostgreConfig
 	Server  ServerConfig
 	TgBot   TgBotConfig
+	SMTP    SMTPConfig
+	Auth    AuthConfig
+	Payment PaymentConfig
 }
 
+// ServerConfig.TrustedProxies lists the addresses (IPs or CIDRs) of the
+// reverse proxies whose X-Forwarded-For entries are believed. With none, the
+// client address is the address of the TCP peer.
 type ServerConfig struct {
-	Port   string
-	JwtKey string
+	Port           string
+	JwtKey         string
+	PublicURL      string
+	TrustedProxies []string
 }
 
 type PostgreConfig struct {
@@ -21,7 +33,30 @@ type PostgreConfig struct {
 }
 
 type TgBotConfig struct {
-	Token string
+	Token    string
+	Username string
+}
+
+// AuthConfig limits what accounts with an unverified email may do.
+type AuthConfig struct {
+	UnverifiedLogin   bool
+	UnverifiedBooking bool
+	LoginLimiter      string
+}
+
+// PaymentConfig selects the payment provider. WebhookSecret signs the
+// provider callbacks.
+type PaymentConfig struct {
+	Provider      string
+	WebhookSecret string
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
 }
 
 func NewConfig() (*Config, error) {
@@ -30,6 +65,7 @@ func NewConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.SetDefault("UNVERIFIED_LOGIN", true)
 
 	cfg := &Config{
 		Postgre: PostgreConfig{
@@ -39,12 +75,42 @@ func NewConfig() (*Config, error) {
 			Host:     c.GetString("POSTGRES_HOST"),
 		},
 		Server: ServerConfig{
-			Port:   c.GetString("PORT"),
-			JwtKey: c.GetString("JWT_SECREY_KEY"),
+			Port:           c.GetString("PORT"),
+			JwtKey:         c.GetString("JWT_SECREY_KEY"),
+			PublicURL:      c.GetString("PUBLIC_URL"),
+			TrustedProxies: splitList(c.GetString("TRUSTED_PROXIES")),
 		},
 		TgBot: TgBotConfig{
-			Token: c.GetString("TG_TOKEN"),
+			Token:    c.GetString("TG_TOKEN"),
+			Username: c.GetString("TG_BOT_USERNAME"),
+		},
+		SMTP: SMTPConfig{
+			Host:     c.GetString("SMTP_HOST"),
+			Port:     c.GetString("SMTP_PORT"),
+			Username: c.GetString("SMTP_USERNAME"),
+			Password: c.GetString("SMTP_PASSWORD"),
+			From:     c.GetString("SMTP_FROM"),
+		},
+		Auth: AuthConfig{
+			UnverifiedLogin:   c.GetBool("UNVERIFIED_LOGIN"),
+			UnverifiedBooking: c.GetBool("UNVERIFIED_BOOKING"),
+			LoginLimiter:      c.GetString("LOGIN_LIMITER"),
+		},
+		Payment: PaymentConfig{
+			Provider:      c.GetString("PAYMENT_PROVIDER"),
+			WebhookSecret: c.GetString("PAYMENT_WEBHOOK_SECRET"),
 		},
 	}
 	return cfg, nil
 }
+
+// splitList parses a comma-separated value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -6,40 +6,84 @@ var (
 	StatusBookingPending   = "pending"
 	StatusBookingConfirmed = "confirmed"
 	StatusBookingCanceled  = "cancelled"
+	StatusBookingExpired   = "expired"
+)
+
+var (
+	BookingReasonConfirmedByUser   = "confirmed by user"
+	BookingReasonCanceledByUser    = "cancelled by user"
+	BookingReasonEventCanceled     = "event cancelled"
+	BookingReasonReservationPassed = "reservation period elapsed"
+	BookingReasonAccountDeleted    = "account deleted"
+	BookingReasonPaymentReceived   = "payment received"
 )
 
 type BookingInCreate struct {
-	UserID    int `json:"user_id"`
-	EventID   int `json:"event_id"`
+	UserID       int `json:"user_id"`
+	EventID      int `json:"event_id"`
+	Quantity     int `json:"quantity"`
+	TicketTypeID int `json:"ticket_type_id"`
+	// PromoCode is redeemed together with the booking, Discount is the
+	// amount it takes off the total price.
+	PromoCode string `json:"promo_code"`
+	// SeatIDs pick the seats at events with assigned seating, the quantity
+	// is then their number.
+	SeatIDs   []int `json:"seat_ids"`
 	ExpiresAt time.Time
+	UnitPrice int64  `json:"-"`
+	Discount  int64  `json:"-"`
+	Currency  string `json:"-"`
 }
 
 type BookingWithEventDetails struct {
 	BookingInRepo
+	TicketTypeName   string
 	EventTitle       string
 	EventDescription string
 	EventDate        time.Time
 }
 
 type BookingInResponse struct {
-	ID               int       `json:"id"`
-	UserID           int       `json:"user_id"`
-	EventID          int       `json:"event_id"`
-	Status           string    `json:"status"`
-	ExpiresAt        time.Time `json:"expires_at"`
-	CreatedAt        time.Time `json:"created_at"`
-	EventTitle       string    `json:"event_title"`
-	EventDescription string    `json:"event_description"`
-	EventDate        time.Time `json:"event_date"`
+	ID               int              `json:"id"`
+	UserID           int              `json:"user_id"`
+	EventID          int              `json:"event_id"`
+	Quantity         int              `json:"quantity"`
+	Status           string           `json:"status"`
+	StatusChangedAt  *time.Time       `json:"status_changed_at,omitempty"`
+	StatusReason     string           `json:"status_reason,omitempty"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	TicketTypeID     int              `json:"ticket_type_id"`
+	TicketTypeName   string           `json:"ticket_type"`
+	UnitPrice        int64            `json:"unit_price"`
+	Discount         int64            `json:"discount"`
+	TotalPrice       int64            `json:"total_price"`
+	Currency         string           `json:"currency"`
+	EventTitle       string           `json:"event_title"`
+	EventDescription string           `json:"event_description"`
+	EventDate        time.Time        `json:"event_date"`
+	Seats            []SeatInResponse `json:"seats,omitempty"`
 }
 
 type BookingInRepo struct {
-	ID        int
-	UserID    int
-	EventID   int
-	Status    string
-	ExpiresAt time.Time
-	CreatedAt time.Time
+	ID              int
+	UserID          int
+	EventID         int
+	Quantity        int
+	Status          string
+	StatusChangedAt *time.Time
+	StatusReason    string
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	TicketTypeID    int
+	UnitPrice       int64
+	Discount        int64
+	Currency        string
+}
+
+// TotalPrice is what the booking costs after the promo code discount.
+func (b BookingInRepo) TotalPrice() int64 {
+	return b.UnitPrice*int64(b.Quantity) - b.Discount
 }
 
 type BookingGetRequest struct {
@@ -52,7 +96,10 @@ type BookingGetRequest struct {
 
 type BookingGetForTG struct {
 	ID         int
+	EventID    int
+	UserID     int
 	TgChatID   int64
+	Quantity   int
 	EventDate  time.Time
 	TitleEvent string
 }
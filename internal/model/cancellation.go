@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// CancellationPolicy describes when a confirmed booking can be cancelled and
+// how much of the payment comes back. All durations are counted back from
+// event_date, like reminders.
+type CancellationPolicy struct {
+	// FreeCancelBefore is the earliest point with a full refund, "0s" means
+	// a full refund until NoCancelWithin.
+	FreeCancelBefore string `json:"free_cancel_before"`
+	// PartialRefunds apply after the free window: the rule with the largest
+	// before that has not passed yet gives the refund, none means no refund.
+	PartialRefunds []RefundRule `json:"partial_refunds"`
+	// NoCancelWithin forbids cancellation this close to the event.
+	NoCancelWithin string `json:"no_cancel_within"`
+}
+
+type RefundRule struct {
+	Before        string `json:"before"`
+	RefundPercent int    `json:"refund_percent"`
+}
+
+type CancellationPolicyInRepo struct {
+	FreeCancelBefore time.Duration
+	PartialRefunds   []RefundRuleInRepo
+	NoCancelWithin   time.Duration
+}
+
+type RefundRuleInRepo struct {
+	Before        time.Duration
+	RefundPercent int
+}
+
+// CancellationQuote tells the user what cancelling the booking right now
+// would bring.
+type CancellationQuote struct {
+	BookingID     int                `json:"booking_id"`
+	Allowed       bool               `json:"allowed"`
+	RefundPercent int                `json:"refund_percent"`
+	RefundAmount  int64              `json:"refund_amount"`
+	Currency      string             `json:"currency"`
+	Policy        CancellationPolicy `json:"policy"`
+}
+
+var (
+	RefundReasonCanceledByUser = "cancelled by user"
+	RefundReasonEventCanceled  = "event cancelled"
+	RefundReasonNotPending     = "booking no longer pending"
+	RefundReasonAccountDeleted = "account deleted"
+)
+
+type RefundInCreate struct {
+	PaymentID     int
+	BookingID     int
+	Amount        int64
+	Currency      string
+	RefundPercent int
+	Reason        string
+}
@@ -2,23 +2,32 @@ package model
 
 import "time"
 
+// Event lifecycle: draft -> published <-> sales_closed -> finished.
+// Any state except finished can move to canceled.
 var (
-	EventStatusPending  = "pending"
-	EventSratusCanceled = "canceled"
-	EventStatusExpired  = "expired"
+	EventStatusDraft       = "draft"
+	EventStatusPublished   = "published"
+	EventStatusSalesClosed = "sales_closed"
+	EventStatusFinished    = "finished"
+	EventSratusCanceled    = "canceled"
 )
 
 type EventInResponse struct {
-	ID                 int       `json:"id"`
-	Title              string    `json:"title"`
-	Description        string    `json:"description"`
-	EventDate          time.Time `json:"event_date"`
-	TotalPlace         int       `json:"total_place"`
-	OccupiedPlace      int       `json:"occupied_place"`
-	EventStatus        string    `json:"event_status"`
-	ReservationPeriod  string    `json:"reservation_period"`
-	BookingConfimation bool      `json:"booking_confirmation"`
-	CreatedAt          time.Time `json:"created_at"`
+	ID                 int                    `json:"id"`
+	Title              string                 `json:"title"`
+	Description        string                 `json:"description"`
+	EventDate          time.Time              `json:"event_date"`
+	TotalPlace         int                    `json:"total_place"`
+	OccupiedPlace      int                    `json:"occupied_place"`
+	EventStatus        string                 `json:"event_status"`
+	ReservationPeriod  string                 `json:"reservation_period"`
+	BookingConfimation bool                   `json:"booking_confirmation"`
+	Reminders          []string               `json:"reminders,omitempty"`
+	OrganizerID        *int                   `json:"organizer_id,omitempty"`
+	VenueID            *int                   `json:"venue_id,omitempty"`
+	CreatedAt          time.Time              `json:"created_at"`
+	TicketTypes        []TicketTypeInResponse `json:"ticket_types"`
+	CancellationPolicy *CancellationPolicy    `json:"cancellation_policy,omitempty"`
 }
 
 type EventInCreate struct {
@@ -28,6 +37,38 @@ type EventInCreate struct {
 	TotalPlace         int       `json:"total_place"`
 	ReservationPeriod  string    `json:"reservation_period"`
 	BookingConfimation bool      `json:"booking_confirmation"`
+	Draft              bool      `json:"draft"`
+	// Reminders are durations before event_date ("24h", "1h"). Omitted means
+	// the default schedule, an empty list disables reminders.
+	Reminders []string `json:"reminders"`
+	// TicketTypes split the seats into tiers, total_place is then their sum.
+	// Omitted means one free standard tier with total_place seats.
+	TicketTypes []TicketTypeInput `json:"ticket_types"`
+	// CancellationPolicy limits cancellation of confirmed bookings. Omitted
+	// means free cancellation until the event.
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy"`
+	// VenueID switches the event to assigned seating: the seats of the venue
+	// are split between the tiers by section and total_place is ignored.
+	VenueID *int `json:"venue_id"`
+}
+
+type EventStatusUpdate struct {
+	Status string `json:"status"`
+}
+
+type EventInUpdate struct {
+	Title              *string    `json:"title"`
+	Description        *string    `json:"description"`
+	EventDate          *time.Time `json:"event_date"`
+	TotalPlace         *int       `json:"total_place"`
+	ReservationPeriod  *string    `json:"reservation_period"`
+	BookingConfimation *bool      `json:"booking_confirmation"`
+	Reminders          *[]string  `json:"reminders"`
+	// TicketTypes replaces the tiers: listed ids are updated, entries without
+	// id are added and missing tiers are removed. TotalPlace can only change
+	// alone for events with a single tier.
+	TicketTypes        *[]TicketTypeInput  `json:"ticket_types"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy"`
 }
 
 type EventInRepo struct {
@@ -40,6 +81,8 @@ type EventInRepo struct {
 	ReservationPeriod  time.Duration
 	BookingConfimation bool
 	CreatedAt          time.Time
+	OrganizerID        *int
+	VenueID            *int
 }
 
 type EventGetRequest struct {
@@ -47,4 +90,8 @@ type EventGetRequest struct {
 	LastID        int
 	Mode          string
 	PageSize      int
+	IncludeDrafts bool
+	// OrganizerID limits the list to the events of one organizer, 0 means
+	// every event.
+	OrganizerID int
 }
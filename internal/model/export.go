@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+var (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+)
+
+var ExportFormats = []string{
+	ExportFormatJSON,
+	ExportFormatZIP,
+}
+
+var (
+	StatusExportPending = "pending"
+	StatusExportReady   = "ready"
+	StatusExportFailed  = "failed"
+)
+
+// PersonalData is everything stored about one user.
+type PersonalData struct {
+	GeneratedAt   time.Time                `json:"generated_at"`
+	User          UserInResponse           `json:"user"`
+	Bookings      []BookingInResponse      `json:"bookings"`
+	Notifications []NotificationInResponse `json:"notifications"`
+	Sessions      []SessionInResponse      `json:"sessions"`
+}
+
+type DataExportInRepo struct {
+	ID          int
+	UserID      int
+	RequestedBy *int
+	Format      string
+	Status      string
+	Payload     []byte
+	LastError   string
+	CreatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+type DataExportInResponse struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	RequestedBy *int       `json:"requested_by,omitempty"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+type DataExportRequest struct {
+	Format string `json:"format"`
+}
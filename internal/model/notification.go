@@ -0,0 +1,114 @@
+package model
+
+import "time"
+
+var (
+	StatusNotificationPending = "pending"
+	StatusNotificationSent    = "sent"
+	StatusNotificationFailed  = "failed"
+)
+
+var (
+	NotificationChannelTelegram = "telegram"
+	NotificationChannelEmail    = "email"
+)
+
+var (
+	NotificationTypeBookingCreated   = "booking_created"
+	NotificationTypeBookingConfirmed = "booking_confirmed"
+	NotificationTypeBookingExpired   = "booking_expired"
+	NotificationTypeBookingCanceled  = "booking_cancelled"
+	NotificationTypeEventCanceled    = "event_cancelled"
+	NotificationTypeEventReminder    = "event_reminder"
+)
+
+// Account notifications are not listed in NotificationTypes, so users cannot
+// turn them off and they always go to the default channel.
+var (
+	NotificationTypePasswordReset     = "password_reset"
+	NotificationTypeEmailVerification = "email_verification"
+)
+
+// NotificationTypes lists every event type a user can configure.
+var NotificationTypes = []string{
+	NotificationTypeBookingCreated,
+	NotificationTypeBookingConfirmed,
+	NotificationTypeBookingExpired,
+	NotificationTypeBookingCanceled,
+	NotificationTypeEventReminder,
+	NotificationTypeEventCanceled,
+}
+
+var NotificationChannels = []string{
+	NotificationChannelTelegram,
+	NotificationChannelEmail,
+}
+
+// NotificationInCreate is delivered to the channels the user chose for
+// EventType, or only to Channel when it is set.
+type NotificationInCreate struct {
+	UserID    int
+	EventType string
+	Subject   string
+	Text      string
+	Channel   string
+}
+
+type NotificationInRepo struct {
+	ID            int
+	UserID        int
+	EventType     string
+	Channel       string
+	Recipient     string
+	Subject       string
+	Text          string
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+type NotificationInResponse struct {
+	ID            int        `json:"id"`
+	UserID        int        `json:"user_id"`
+	EventType     string     `json:"event_type"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Text          string     `json:"text"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+type NotificationGetRequest struct {
+	Status        string
+	LastCreatedAt time.Time
+	LastID        int
+	Mode          string
+	PageSize      int
+}
+
+type NotificationPreferenceInRepo struct {
+	UserID    int
+	EventType string
+	Channel   string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// NotificationPreference lists the channels a user wants for one event type.
+// An empty Channels mutes the event type.
+type NotificationPreference struct {
+	EventType string   `json:"event_type"`
+	Channels  []string `json:"channels"`
+}
+
+type NotificationPreferencesUpdate struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+var (
+	StatusPaymentPending   = "pending"
+	StatusPaymentSucceeded = "succeeded"
+	StatusPaymentFailed    = "failed"
+	StatusPaymentRefunded  = "refunded"
+
+	StatusPaymentPartiallyRefunded = "partially_refunded"
+)
+
+var PaymentProviderFake = "fake"
+
+// PaymentIntentRequest asks a provider to start collecting Amount, in minor
+// units of Currency, for a booking.
+type PaymentIntentRequest struct {
+	BookingID   int
+	Amount      int64
+	Currency    string
+	Description string
+}
+
+// PaymentIntent is the provider side of a started payment. The user pays on
+// ConfirmationURL when the provider has one.
+type PaymentIntent struct {
+	ProviderPaymentID string
+	ConfirmationURL   string
+}
+
+// PaymentWebhook is a verified callback: the payment reached Status.
+type PaymentWebhook struct {
+	ProviderPaymentID string
+	Status            string
+}
+
+type PaymentInCreate struct {
+	BookingID         int
+	Provider          string
+	ProviderPaymentID string
+	Amount            int64
+	Currency          string
+	ConfirmationURL   string
+}
+
+type PaymentInRepo struct {
+	ID                int
+	BookingID         int
+	Provider          string
+	ProviderPaymentID string
+	Amount            int64
+	Currency          string
+	Status            string
+	ConfirmationURL   string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type PaymentInResponse struct {
+	ID                int       `json:"id"`
+	BookingID         int       `json:"booking_id"`
+	Provider          string    `json:"provider"`
+	ProviderPaymentID string    `json:"provider_payment_id"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	Status            string    `json:"status"`
+	ConfirmationURL   string    `json:"confirmation_url,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+// Permissions guard the management routes. Roles are mapped to permissions in
+// the role_permissions table.
+var (
+	PermissionEventsWrite         = "events:write"
+	PermissionEventsManageAll     = "events:manage_all"
+	PermissionUsersRead           = "users:read"
+	PermissionUsersManage         = "users:manage"
+	PermissionNotificationsManage = "notifications:manage"
+	PermissionPromoCodesManage    = "promo_codes:manage"
+	PermissionVenuesManage        = "venues:manage"
+)
+
+var Permissions = []string{
+	PermissionEventsWrite,
+	PermissionEventsManageAll,
+	PermissionUsersRead,
+	PermissionUsersManage,
+	PermissionNotificationsManage,
+	PermissionPromoCodesManage,
+	PermissionVenuesManage,
+}
+
+type RolePermissions struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolePermissionsUpdate struct {
+	Permissions []string `json:"permissions"`
+}
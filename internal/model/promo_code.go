@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+var (
+	PromoDiscountPercent = "percent"
+	PromoDiscountFixed   = "fixed"
+)
+
+// PromoCodeInput creates a promo code or replaces all of its settings.
+// DiscountValue is a percent for "percent" codes and an amount in minor units
+// of Currency for "fixed" ones. Nil limits and dates mean no limit, empty
+// EventIDs and TicketTypeIDs mean every event and tier.
+type PromoCodeInput struct {
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`
+	DiscountValue  int64      `json:"discount_value"`
+	Currency       string     `json:"currency"`
+	MaxUses        *int       `json:"max_uses"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	EventIDs       []int      `json:"event_ids"`
+	TicketTypeIDs  []int      `json:"ticket_type_ids"`
+}
+
+type PromoCodeInRepo struct {
+	ID             int
+	Code           string
+	DiscountType   string
+	DiscountValue  int64
+	Currency       string
+	MaxUses        *int
+	MaxUsesPerUser *int
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	UsedCount      int
+	EventIDs       []int
+	TicketTypeIDs  []int
+	CreatedAt      time.Time
+}
+
+type PromoCodeInResponse struct {
+	ID             int        `json:"id"`
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`
+	DiscountValue  int64      `json:"discount_value"`
+	Currency       string     `json:"currency,omitempty"`
+	MaxUses        *int       `json:"max_uses"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	UsedCount      int        `json:"used_count"`
+	EventIDs       []int      `json:"event_ids"`
+	TicketTypeIDs  []int      `json:"ticket_type_ids"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type PromoRedemptionInCreate struct {
+	PromoCodeID int
+	BookingID   int
+	UserID      int
+	Discount    int64
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// DefaultEventReminders is used when an event is created without an explicit
+// reminder schedule.
+var DefaultEventReminders = []time.Duration{24 * time.Hour, time.Hour}
+
+type ReminderDue struct {
+	BookingID    int
+	EventID      int
+	UserID       int
+	Quantity     int
+	TitleEvent   string
+	EventDate    time.Time
+	RemindBefore time.Duration
+}
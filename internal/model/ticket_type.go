@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Events created without ticket types get a single free tier with all seats.
+var (
+	DefaultTicketTypeName = "standard"
+	DefaultCurrency       = "RUB"
+)
+
+// TicketTypeInput describes a ticket tier. Price is in minor units of the
+// currency (kopecks, cents). In event updates ID refers to an existing tier,
+// 0 creates a new one.
+type TicketTypeInput struct {
+	ID       int    `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	Price    int64  `json:"price"`
+	Currency string `json:"currency"`
+	// Sections of the venue sold through the tier, only for events with
+	// assigned seating. Capacity is then the number of seats in them.
+	Sections []string `json:"sections,omitempty"`
+}
+
+type TicketTypeInRepo struct {
+	ID        int
+	EventID   int
+	Name      string
+	Capacity  int
+	Price     int64
+	Currency  string
+	Occupied  int
+	CreatedAt time.Time
+}
+
+type TicketTypeInResponse struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Capacity      int    `json:"capacity"`
+	OccupiedPlace int    `json:"occupied_place"`
+	Price         int64  `json:"price"`
+	Currency      string `json:"currency"`
+}
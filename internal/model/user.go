@@ -1,32 +1,103 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+// What each role may do is defined by its permissions, see RolePermissions.
+var (
+	RoleUser      = "user"
+	RoleOrganizer = "organizer"
+	RoleAdmin     = "admin"
+)
+
+var Roles = []string{RoleUser, RoleOrganizer, RoleAdmin}
+
+// Actor is the authenticated user a management action is performed for.
+type Actor struct {
+	UserID      int
+	Role        string
+	Permissions []string
+}
+
+func (a Actor) Can(permission string) bool {
+	return slices.Contains(a.Permissions, permission)
+}
 
 type UserInCreate struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
-	TgChatID *int   `json:"tg_chatid,omitempty"`
 }
 
 type UserInRepo struct {
-	ID        int
-	Email     string
-	Password  string
-	Role      string
-	TgChatID  *int
-	CreatedAt time.Time
+	ID              int
+	Email           string
+	Password        string
+	Role            string
+	TgChatID        *int
+	CreatedAt       time.Time
+	EmailVerifiedAt *time.Time
+	Name            *string
+	Phone           *string
+	Locale          *string
+	DeletedAt       *time.Time
 }
 
 type UserLoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	IP       string `json:"-"`
+}
+
+type PasswordForgotRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type PasswordChangeRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
 }
 
 type UserInResponse struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	TgChatID  *int      `json:"tg_chatid"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	TgChatID        *int       `json:"tg_chatid"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Name            *string    `json:"name"`
+	Phone           *string    `json:"phone"`
+	Locale          *string    `json:"locale"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ProfileUpdate changes only the fields that are present, an empty string
+// clears a field. Changing the email requires the current password.
+type ProfileUpdate struct {
+	Email           *string `json:"email"`
+	CurrentPassword string  `json:"current_password"`
+	Name            *string `json:"name"`
+	Phone           *string `json:"phone"`
+	Locale          *string `json:"locale"`
+}
+
+type AccountDeleteRequest struct {
+	Password string `json:"password"`
+}
+
+type RoleUpdate struct {
+	Role string `json:"role"`
+}
+
+type EmailVerifyRequest struct {
+	Token string `json:"token"`
 }
 
 type UserGetRequest struct {
@@ -35,3 +106,9 @@ type UserGetRequest struct {
 	Mode          string
 	PageSize      int
 }
+
+type TgLinkCodeInResponse struct {
+	Code      string    `json:"code"`
+	Link      string    `json:"link,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+// Seat states on the seat map of an event. A seat is held while a pending
+// booking for it has not reached expires_at and sold once the booking is
+// confirmed.
+var (
+	SeatStateFree = "free"
+	SeatStateHeld = "held"
+	SeatStateSold = "sold"
+)
+
+// VenueInput creates a venue or replaces its settings. In updates omitted
+// sections keep the current seat map.
+type VenueInput struct {
+	Name     string         `json:"name"`
+	Address  string         `json:"address"`
+	Sections []SectionInput `json:"sections"`
+}
+
+type SectionInput struct {
+	Name string     `json:"name"`
+	Rows []RowInput `json:"rows"`
+}
+
+// RowInput describes a row of seats numbered from 1 to Seats. Accessible
+// lists the seat numbers suitable for wheelchair users.
+type RowInput struct {
+	Row        string `json:"row"`
+	Seats      int    `json:"seats"`
+	Accessible []int  `json:"accessible"`
+}
+
+type VenueInCreate struct {
+	Name    string
+	Address string
+	// Seats replace the seat map of the venue, nil keeps it on update.
+	Seats []SeatInCreate
+}
+
+type SeatInCreate struct {
+	Section    string
+	Row        string
+	Number     int
+	Accessible bool
+}
+
+type VenueInRepo struct {
+	ID        int
+	Name      string
+	Address   string
+	SeatCount int
+	CreatedAt time.Time
+}
+
+type SeatInRepo struct {
+	ID         int
+	VenueID    int
+	Section    string
+	Row        string
+	Number     int
+	Accessible bool
+}
+
+// EventSeatInRepo is a venue seat as sold at an event.
+type EventSeatInRepo struct {
+	SeatInRepo
+	TicketTypeID int
+	State        string
+}
+
+type VenueInResponse struct {
+	ID        int              `json:"id"`
+	Name      string           `json:"name"`
+	Address   string           `json:"address"`
+	SeatCount int              `json:"seat_count"`
+	Seats     []SeatInResponse `json:"seats,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type SeatInResponse struct {
+	ID         int    `json:"id"`
+	Section    string `json:"section"`
+	Row        string `json:"row"`
+	Number     int    `json:"number"`
+	Accessible bool   `json:"accessible"`
+}
+
+type EventSeatInResponse struct {
+	SeatInResponse
+	TicketTypeID int    `json:"ticket_type_id"`
+	State        string `json:"state"`
+}
+
+type SeatMapInResponse struct {
+	EventID   int                   `json:"event_id"`
+	VenueID   int                   `json:"venue_id"`
+	VenueName string                `json:"venue_name"`
+	Free      int                   `json:"free"`
+	Held      int                   `json:"held"`
+	Sold      int                   `json:"sold"`
+	Seats     []EventSeatInResponse `json:"seats"`
+}
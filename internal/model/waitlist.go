@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+var (
+	StatusWaitlistWaiting  = "waiting"
+	StatusWaitlistPromoted = "promoted"
+	StatusWaitlistCanceled = "cancelled"
+)
+
+type WaitlistInCreate struct {
+	UserID       int `json:"user_id"`
+	EventID      int `json:"event_id"`
+	Quantity     int `json:"quantity"`
+	TicketTypeID int `json:"ticket_type_id"`
+}
+
+type WaitlistInRepo struct {
+	ID           int
+	UserID       int
+	EventID      int
+	Quantity     int
+	TicketTypeID int
+	Status       string
+	CreatedAt    time.Time
+}
+
+type WaitlistWithEventDetails struct {
+	WaitlistInRepo
+	Position   int
+	EventTitle string
+	EventDate  time.Time
+}
+
+type WaitlistInResponse struct {
+	ID           int       `json:"id"`
+	EventID      int       `json:"event_id"`
+	Quantity     int       `json:"quantity"`
+	TicketTypeID int       `json:"ticket_type_id"`
+	Status       string    `json:"status"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+	EventTitle   string    `json:"event_title"`
+	EventDate    time.Time `json:"event_date"`
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/wb-go/wbf/zlog"
@@ -10,13 +11,18 @@ import (
 )
 
 type BookingRepository interface {
-	Create(ctx context.Context, b model.BookingInCreate, status string) error
+	Create(ctx context.Context, b model.BookingInCreate, status string) (int, error)
+	GetByID(ctx context.Context, id int) (model.BookingInRepo, error)
+	GetByIDForUpdate(ctx context.Context, id int) (model.BookingInRepo, error)
+	GetForTG(ctx context.Context, id int) (model.BookingGetForTG, error)
 	GetListBooking(ctx context.Context, req model.BookingGetRequest) ([]model.BookingWithEventDetails, error)
-	UpdateStatus(ctx context.Context, status string, bookID, eventID, userID int) error
+	GetAllByUser(ctx context.Context, userID int) ([]model.BookingWithEventDetails, error)
+	UpdateStatus(ctx context.Context, from, status, reason string, bookID, eventID, userID int) error
 	GetOccupiedPlace(ctx context.Context, eventID int) (int, error)
-	GetExpiredBooking(ctx context.Context) ([]model.BookingGetForTG, error)
+	ExpireBooking(ctx context.Context) ([]model.BookingGetForTG, error)
 	GetCountUserBooking(ctx context.Context, id int) (int, error)
-	DeleteExpiredBooking(ctx context.Context) error
+	CancelByEvent(ctx context.Context, eventID int) ([]model.BookingGetForTG, error)
+	CancelByUser(ctx context.Context, userID int, reason string) ([]model.BookingGetForTG, error)
 }
 
 type bookingRepository struct {
@@ -27,15 +33,81 @@ func NewBookingRepository(db dbInterface) BookingRepository {
 	return &bookingRepository{db: db}
 }
 
-func (br *bookingRepository) Create(ctx context.Context, b model.BookingInCreate, status string) error {
+func (br *bookingRepository) Create(ctx context.Context, b model.BookingInCreate, status string) (int, error) {
 
-	query := `INSERT INTO booking (user_id, event_id,status, expires_at, created_at)
-				VALUES ($1, $2, $3, $4, $5)`
-	_, err := br.db.ExecContext(ctx, query, b.UserID, b.EventID, status, b.ExpiresAt, time.Now())
+	query := `INSERT INTO booking (user_id, event_id, quantity, status, expires_at, created_at,
+				ticket_type_id, unit_price, discount, currency)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				RETURNING booking_id`
+	res := br.db.QueryRowContext(ctx, query, b.UserID, b.EventID, b.Quantity, status, b.ExpiresAt, time.Now(),
+		b.TicketTypeID, b.UnitPrice, b.Discount, b.Currency)
+
+	var id int
+	err := res.Scan(&id)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	return id, nil
+}
+
+func (br *bookingRepository) GetByID(ctx context.Context, id int) (model.BookingInRepo, error) {
+	query := `SELECT booking_id, user_id, event_id, quantity, status, status_changed_at,
+				COALESCE(status_reason, ''), expires_at, created_at, ticket_type_id, unit_price, discount, currency
+				FROM booking
+				WHERE booking_id=$1`
+	res := br.db.QueryRowContext(ctx, query, id)
+
+	var record model.BookingInRepo
+	err := res.Scan(&record.ID, &record.UserID, &record.EventID, &record.Quantity,
+		&record.Status, &record.StatusChangedAt, &record.StatusReason, &record.ExpiresAt, &record.CreatedAt,
+		&record.TicketTypeID, &record.UnitPrice, &record.Discount, &record.Currency)
+	if err != nil {
+		return model.BookingInRepo{}, err
+	}
+	return record, nil
+}
+
+// GetByIDForUpdate locks the booking row until the end of the transaction,
+// so a payment callback and the expiry job cannot both change its status.
+func (br *bookingRepository) GetByIDForUpdate(ctx context.Context, id int) (model.BookingInRepo, error) {
+	query := `SELECT booking_id, user_id, event_id, quantity, status, status_changed_at,
+				COALESCE(status_reason, ''), expires_at, created_at, ticket_type_id, unit_price, discount, currency
+				FROM booking
+				WHERE booking_id=$1
+				FOR UPDATE`
+	res := br.db.QueryRowContext(ctx, query, id)
+
+	var record model.BookingInRepo
+	err := res.Scan(&record.ID, &record.UserID, &record.EventID, &record.Quantity,
+		&record.Status, &record.StatusChangedAt, &record.StatusReason, &record.ExpiresAt, &record.CreatedAt,
+		&record.TicketTypeID, &record.UnitPrice, &record.Discount, &record.Currency)
+	if err != nil {
+		return model.BookingInRepo{}, err
+	}
+	return record, nil
+}
+
+func (br *bookingRepository) GetForTG(ctx context.Context, id int) (model.BookingGetForTG, error) {
+	query := `SELECT
+				b.booking_id,
+				b.event_id,
+				b.user_id,
+				COALESCE(u.tg_chatid, 0),
+				b.quantity,
+				e.title,
+				e.event_date
+				FROM booking b
+				INNER JOIN events e ON b.event_id = e.event_id
+				INNER JOIN users u ON b.user_id = u.user_id
+				WHERE b.booking_id=$1`
+	res := br.db.QueryRowContext(ctx, query, id)
+
+	var record model.BookingGetForTG
+	err := res.Scan(&record.ID, &record.EventID, &record.UserID, &record.TgChatID, &record.Quantity, &record.TitleEvent, &record.EventDate)
+	if err != nil {
+		return model.BookingGetForTG{}, err
+	}
+	return record, nil
 }
 
 func getQueryFromMode(mode string) string {
@@ -47,14 +119,23 @@ func getQueryFromMode(mode string) string {
 					b.booking_id,
 					b.event_id,
 					b.user_id,
+					b.quantity,
 					b.status,
+					b.status_changed_at,
+					COALESCE(b.status_reason, ''),
 					b.expires_at,
 					b.created_at,
+					b.ticket_type_id,
+					b.unit_price,
+					b.discount,
+					b.currency,
+					t.name,
 					e.title,
 					e.event_date,
 					e.event_description
 					FROM booking b
 					INNER JOIN events e ON e.event_id = b.event_id
+					INNER JOIN ticket_types t ON t.ticket_type_id = b.ticket_type_id
 					WHERE b.user_id=$1 AND b.created_at > $2 AND b.booking_id > $3
 					ORDER BY b.created_at ASC, b.booking_id ASC
 					LIMIT $4`
@@ -63,14 +144,23 @@ func getQueryFromMode(mode string) string {
 					b.booking_id,
 					b.event_id,
 					b.user_id,
+					b.quantity,
 					b.status,
+					b.status_changed_at,
+					COALESCE(b.status_reason, ''),
 					b.expires_at,
 					b.created_at,
+					b.ticket_type_id,
+					b.unit_price,
+					b.discount,
+					b.currency,
+					t.name,
 					e.title,
 					e.event_date,
 					e.event_description
 					FROM booking b
 					INNER JOIN events e ON e.event_id = b.event_id
+					INNER JOIN ticket_types t ON t.ticket_type_id = b.ticket_type_id
 					WHERE b.user_id=$1 AND ((b.created_at < $2) OR ( b.created_at = $2 AND b.booking_id < $3)) 
 					ORDER BY b.created_at DESC, b.booking_id DESC
 					LIMIT $4`
@@ -92,37 +182,92 @@ func (br *bookingRepository) GetListBooking(ctx context.Context, req model.Booki
 		}
 	}()
 
+	return scanBookingsWithEvent(res)
+}
+
+// GetAllByUser returns the whole booking history of the user, oldest first.
+func (br *bookingRepository) GetAllByUser(ctx context.Context, userID int) ([]model.BookingWithEventDetails, error) {
+	query := `SELECT 
+				b.booking_id,
+				b.event_id,
+				b.user_id,
+				b.quantity,
+				b.status,
+				b.status_changed_at,
+				COALESCE(b.status_reason, ''),
+				b.expires_at,
+				b.created_at,
+				b.ticket_type_id,
+				b.unit_price,
+				b.discount,
+				b.currency,
+				t.name,
+				e.title,
+				e.event_date,
+				e.event_description
+				FROM booking b
+				INNER JOIN events e ON e.event_id = b.event_id
+				INNER JOIN ticket_types t ON t.ticket_type_id = b.ticket_type_id
+				WHERE b.user_id=$1
+				ORDER BY b.created_at ASC, b.booking_id ASC`
+	res, err := br.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return scanBookingsWithEvent(res)
+}
+
+func scanBookingsWithEvent(res *sql.Rows) ([]model.BookingWithEventDetails, error) {
 	var b []model.BookingWithEventDetails
 	for res.Next() {
 		var temp model.BookingWithEventDetails
-		err := res.Scan(&temp.ID, &temp.EventID, &temp.UserID,
-			&temp.Status, &temp.ExpiresAt, &temp.CreatedAt, &temp.EventTitle,
+		err := res.Scan(&temp.ID, &temp.EventID, &temp.UserID, &temp.Quantity,
+			&temp.Status, &temp.StatusChangedAt, &temp.StatusReason, &temp.ExpiresAt, &temp.CreatedAt,
+			&temp.TicketTypeID, &temp.UnitPrice, &temp.Discount, &temp.Currency, &temp.TicketTypeName, &temp.EventTitle,
 			&temp.EventDate, &temp.EventDescription)
 		if err != nil {
 			return nil, err
 		}
 		b = append(b, temp)
 	}
-
 	return b, nil
 }
 
-func (br *bookingRepository) UpdateStatus(ctx context.Context, status string, bookID, eventID, userID int) error {
+// UpdateStatus moves the booking from status from to status. It returns
+// sql.ErrNoRows if the booking is no longer in from, so a transition read
+// from stale state never overwrites a concurrent one.
+func (br *bookingRepository) UpdateStatus(ctx context.Context, from, status, reason string, bookID, eventID, userID int) error {
 	query := `UPDATE booking
-				SET status=$1
-				WHERE user_id=$2 AND event_id=$3 AND booking_ID=$4`
-	_, err := br.db.ExecContext(ctx, query, status, userID, eventID, bookID)
+				SET status=$1, status_reason=$2, status_changed_at=$3
+				WHERE user_id=$4 AND event_id=$5 AND booking_ID=$6 AND status=$7`
+	res, err := br.db.ExecContext(ctx, query, status, reason, time.Now(), userID, eventID, bookID, from)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
+// GetOccupiedPlace counts confirmed bookings and pending ones whose hold has
+// not run out yet, the same rule the seat states follow.
 func (br *bookingRepository) GetOccupiedPlace(ctx context.Context, eventID int) (int, error) {
-	query := `SELECT COUNT(*)
+	query := `SELECT COALESCE(SUM(quantity), 0)
 				FROM booking
-				WHERE event_id=$1 AND status IN ('pending', 'confirmed')`
-	res := br.db.QueryRowContext(ctx, query, eventID)
+				WHERE event_id=$1 AND (status='confirmed' OR (status='pending' AND expires_at > $2))`
+	res := br.db.QueryRowContext(ctx, query, eventID, time.Now())
 	if res.Err() != nil {
 		return 0, res.Err()
 	}
@@ -135,17 +280,30 @@ func (br *bookingRepository) GetOccupiedPlace(ctx context.Context, eventID int)
 	return count, nil
 }
 
-func (br *bookingRepository) GetExpiredBooking(ctx context.Context) ([]model.BookingGetForTG, error) {
-	query := `SELECT 
-				b.booking_id,
-				u.tg_chatid,
+// ExpireBooking moves every pending booking past its expires_at to the
+// expired status and returns exactly the rows it changed. Notifications are
+// built from this result, so a booking is never notified without being
+// expired or expired without being notified, even with several instances
+// running the scheduler.
+func (br *bookingRepository) ExpireBooking(ctx context.Context) ([]model.BookingGetForTG, error) {
+	query := `WITH expired AS (
+				UPDATE booking
+				SET status='expired', status_reason=$2, status_changed_at=$1
+				WHERE status='pending' AND expires_at < $1
+				RETURNING booking_id, event_id, user_id, quantity
+			)
+			SELECT
+				x.booking_id,
+				x.event_id,
+				x.user_id,
+				COALESCE(u.tg_chatid, 0),
+				x.quantity,
 				e.title,
 				e.event_date
-				FROM booking b
-				INNER JOIN events e ON b.event_id = e.event_id
-				INNER JOIN users u ON b.user_id = u.user_id
-				WHERE expires_at < $1 AND status='pending'`
-	res, err := br.db.QueryContext(ctx, query, time.Now())
+				FROM expired x
+				INNER JOIN events e ON x.event_id = e.event_id
+				INNER JOIN users u ON x.user_id = u.user_id`
+	res, err := br.db.QueryContext(ctx, query, time.Now(), model.BookingReasonReservationPassed)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +317,7 @@ func (br *bookingRepository) GetExpiredBooking(ctx context.Context) ([]model.Boo
 	var b []model.BookingGetForTG
 	for res.Next() {
 		var temp model.BookingGetForTG
-		err := res.Scan(&temp.ID, &temp.TgChatID, &temp.TitleEvent, &temp.EventDate)
+		err := res.Scan(&temp.ID, &temp.EventID, &temp.UserID, &temp.TgChatID, &temp.Quantity, &temp.TitleEvent, &temp.EventDate)
 		if err != nil {
 			return nil, err
 		}
@@ -187,12 +345,89 @@ func (br *bookingRepository) GetCountUserBooking(ctx context.Context, id int) (i
 
 }
 
-func (br *bookingRepository) DeleteExpiredBooking(ctx context.Context) error {
-	query := `DELETE FROM booking
-				WHERE status = 'pending' AND expires_at < $1`
-	_, err := br.db.ExecContext(ctx, query, time.Now())
+// CancelByEvent cancels every active booking of the event and returns the
+// cancelled rows so their holders can be notified.
+func (br *bookingRepository) CancelByEvent(ctx context.Context, eventID int) ([]model.BookingGetForTG, error) {
+	query := `WITH cancelled AS (
+				UPDATE booking
+				SET status='cancelled', status_reason=$2, status_changed_at=$3
+				WHERE event_id=$1 AND status IN ('pending', 'confirmed')
+				RETURNING booking_id, event_id, user_id, quantity
+			)
+			SELECT
+				c.booking_id,
+				c.event_id,
+				c.user_id,
+				COALESCE(u.tg_chatid, 0),
+				c.quantity,
+				e.title,
+				e.event_date
+				FROM cancelled c
+				INNER JOIN events e ON c.event_id = e.event_id
+				INNER JOIN users u ON c.user_id = u.user_id`
+	res, err := br.db.QueryContext(ctx, query, eventID, model.BookingReasonEventCanceled, time.Now())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return err
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var b []model.BookingGetForTG
+	for res.Next() {
+		var temp model.BookingGetForTG
+		err := res.Scan(&temp.ID, &temp.EventID, &temp.UserID, &temp.TgChatID, &temp.Quantity, &temp.TitleEvent, &temp.EventDate)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, temp)
+	}
+	return b, nil
+}
+
+// CancelByUser cancels every active booking of the user and returns the
+// cancelled rows, ordered by event.
+func (br *bookingRepository) CancelByUser(ctx context.Context, userID int, reason string) ([]model.BookingGetForTG, error) {
+	query := `WITH cancelled AS (
+				UPDATE booking
+				SET status='cancelled', status_reason=$2, status_changed_at=$3
+				WHERE user_id=$1 AND status IN ('pending', 'confirmed')
+				RETURNING booking_id, event_id, user_id, quantity
+			)
+			SELECT
+				c.booking_id,
+				c.event_id,
+				c.user_id,
+				COALESCE(u.tg_chatid, 0),
+				c.quantity,
+				e.title,
+				e.event_date
+				FROM cancelled c
+				INNER JOIN events e ON c.event_id = e.event_id
+				INNER JOIN users u ON c.user_id = u.user_id
+				ORDER BY c.event_id, c.booking_id`
+	res, err := br.db.QueryContext(ctx, query, userID, reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var b []model.BookingGetForTG
+	for res.Next() {
+		var temp model.BookingGetForTG
+		err := res.Scan(&temp.ID, &temp.EventID, &temp.UserID, &temp.TgChatID, &temp.Quantity, &temp.TitleEvent, &temp.EventDate)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, temp)
+	}
+	return b, nil
 }
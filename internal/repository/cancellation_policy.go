@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type CancellationPolicyRepository interface {
+	SetForEvent(ctx context.Context, eventID int, p model.CancellationPolicyInRepo) error
+	GetByEvent(ctx context.Context, eventID int) (model.CancellationPolicyInRepo, error)
+}
+
+type cancellationPolicyRepository struct {
+	db dbInterface
+}
+
+func NewCancellationPolicyRepository(db dbInterface) CancellationPolicyRepository {
+	return &cancellationPolicyRepository{db: db}
+}
+
+// SetForEvent replaces the policy of the event together with its refund
+// rules.
+func (cr *cancellationPolicyRepository) SetForEvent(ctx context.Context, eventID int, p model.CancellationPolicyInRepo) error {
+	query := `INSERT INTO cancellation_policies (event_id, free_cancel_before, no_cancel_within)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (event_id) DO UPDATE
+				SET free_cancel_before=EXCLUDED.free_cancel_before, no_cancel_within=EXCLUDED.no_cancel_within`
+	_, err := cr.db.ExecContext(ctx, query, eventID, p.FreeCancelBefore, p.NoCancelWithin)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM cancellation_refund_rules
+				WHERE event_id=$1`
+	_, err = cr.db.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO cancellation_refund_rules (event_id, cancel_before, refund_percent)
+				VALUES ($1, $2, $3)`
+	for _, r := range p.PartialRefunds {
+		_, err := cr.db.ExecContext(ctx, query, eventID, r.Before, r.RefundPercent)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByEvent returns the policy with the rules ordered from the earliest
+// one. Events without a policy get the zero value: free cancellation at any
+// time.
+func (cr *cancellationPolicyRepository) GetByEvent(ctx context.Context, eventID int) (model.CancellationPolicyInRepo, error) {
+	query := `SELECT free_cancel_before, no_cancel_within
+				FROM cancellation_policies
+				WHERE event_id=$1`
+	var p model.CancellationPolicyInRepo
+	err := cr.db.QueryRowContext(ctx, query, eventID).Scan(&p.FreeCancelBefore, &p.NoCancelWithin)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.CancellationPolicyInRepo{}, nil
+	}
+	if err != nil {
+		return model.CancellationPolicyInRepo{}, err
+	}
+
+	query = `SELECT cancel_before, refund_percent
+				FROM cancellation_refund_rules
+				WHERE event_id=$1
+				ORDER BY cancel_before DESC`
+	res, err := cr.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return model.CancellationPolicyInRepo{}, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	for res.Next() {
+		var r model.RefundRuleInRepo
+		err := res.Scan(&r.Before, &r.RefundPercent)
+		if err != nil {
+			return model.CancellationPolicyInRepo{}, err
+		}
+		p.PartialRefunds = append(p.PartialRefunds, r)
+	}
+	return p, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, userID, requestedBy int, format string) (int, error)
+	GetByID(ctx context.Context, id int) (model.DataExportInRepo, error)
+	ClaimPending(ctx context.Context, limit int) ([]model.DataExportInRepo, error)
+	MarkReady(ctx context.Context, id int, payload []byte) error
+	MarkFailed(ctx context.Context, id int, lastError string) error
+	DeleteByUser(ctx context.Context, userID int) error
+}
+
+type dataExportRepository struct {
+	db dbInterface
+}
+
+func NewDataExportRepository(db dbInterface) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+func (dr *dataExportRepository) Create(ctx context.Context, userID, requestedBy int, format string) (int, error) {
+	query := `INSERT INTO data_exports (user_id, requested_by, format, status, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING export_id`
+	var id int
+	err := dr.db.QueryRowContext(ctx, query, userID, requestedBy, format, model.StatusExportPending, time.Now()).
+		Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID returns the export with its payload. Returns sql.ErrNoRows if
+// there is no such export.
+func (dr *dataExportRepository) GetByID(ctx context.Context, id int) (model.DataExportInRepo, error) {
+	query := `SELECT export_id, user_id, requested_by, format, status, payload, COALESCE(last_error, ''),
+				created_at, finished_at
+				FROM data_exports
+				WHERE export_id=$1`
+	var e model.DataExportInRepo
+	err := dr.db.QueryRowContext(ctx, query, id).Scan(&e.ID, &e.UserID, &e.RequestedBy, &e.Format, &e.Status,
+		&e.Payload, &e.LastError, &e.CreatedAt, &e.FinishedAt)
+	if err != nil {
+		return model.DataExportInRepo{}, err
+	}
+	return e, nil
+}
+
+// ClaimPending locks up to limit pending exports, oldest first. Rows locked
+// by another instance are skipped, so an export is built only once.
+func (dr *dataExportRepository) ClaimPending(ctx context.Context, limit int) ([]model.DataExportInRepo, error) {
+	query := `SELECT export_id, user_id, requested_by, format, status, created_at
+				FROM data_exports
+				WHERE status='pending'
+				ORDER BY created_at ASC, export_id ASC
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED`
+	res, err := dr.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var exports []model.DataExportInRepo
+	for res.Next() {
+		var e model.DataExportInRepo
+		err := res.Scan(&e.ID, &e.UserID, &e.RequestedBy, &e.Format, &e.Status, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, e)
+	}
+	return exports, nil
+}
+
+func (dr *dataExportRepository) MarkReady(ctx context.Context, id int, payload []byte) error {
+	query := `UPDATE data_exports
+				SET status='ready', payload=$2, finished_at=$3
+				WHERE export_id=$1`
+	_, err := dr.db.ExecContext(ctx, query, id, payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dr *dataExportRepository) MarkFailed(ctx context.Context, id int, lastError string) error {
+	query := `UPDATE data_exports
+				SET status='failed', last_error=$2, finished_at=$3
+				WHERE export_id=$1`
+	_, err := dr.db.ExecContext(ctx, query, id, lastError, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (dr *dataExportRepository) DeleteByUser(ctx context.Context, userID int) error {
+	query := `DELETE FROM data_exports
+				WHERE user_id=$1`
+	_, err := dr.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
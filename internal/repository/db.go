@@ -16,18 +16,54 @@ type dbInterface interface {
 }
 
 type Storage struct {
-	Event   EventRepository
-	Booking BookingRepository
-	User    UserRepository
-	db      *dbpg.DB
+	Event                  EventRepository
+	Booking                BookingRepository
+	User                   UserRepository
+	Waitlist               WaitlistRepository
+	Outbox                 OutboxRepository
+	NotificationPreference NotificationPreferenceRepository
+	Reminder               ReminderRepository
+	TgLinkCode             TgLinkCodeRepository
+	Session                SessionRepository
+	PasswordReset          PasswordResetRepository
+	EmailVerification      EmailVerificationRepository
+	LoginAttempt           LoginAttemptRepository
+	Permission             PermissionRepository
+	DataExport             DataExportRepository
+	TicketType             TicketTypeRepository
+	Payment                PaymentRepository
+	Refund                 RefundRepository
+	CancellationPolicy     CancellationPolicyRepository
+	PromoCode              PromoCodeRepository
+	Venue                  VenueRepository
+	Seat                   SeatRepository
+	db                     *dbpg.DB
 }
 
 func NewStorage(db *dbpg.DB) *Storage {
 	return &Storage{
-		Event:   NewEventRepository(db),
-		Booking: NewBookingRepository(db),
-		User:    NewUserRepository(db),
-		db:      db,
+		Event:                  NewEventRepository(db),
+		Booking:                NewBookingRepository(db),
+		User:                   NewUserRepository(db),
+		Waitlist:               NewWaitlistRepository(db),
+		Outbox:                 NewOutboxRepository(db),
+		NotificationPreference: NewNotificationPreferenceRepository(db),
+		Reminder:               NewReminderRepository(db),
+		TgLinkCode:             NewTgLinkCodeRepository(db),
+		Session:                NewSessionRepository(db),
+		PasswordReset:          NewPasswordResetRepository(db),
+		EmailVerification:      NewEmailVerificationRepository(db),
+		LoginAttempt:           NewLoginAttemptRepository(db),
+		Permission:             NewPermissionRepository(db),
+		DataExport:             NewDataExportRepository(db),
+		TicketType:             NewTicketTypeRepository(db),
+		Payment:                NewPaymentRepository(db),
+		Refund:                 NewRefundRepository(db),
+		CancellationPolicy:     NewCancellationPolicyRepository(db),
+		PromoCode:              NewPromoCodeRepository(db),
+		Venue:                  NewVenueRepository(db),
+		Seat:                   NewSeatRepository(db),
+		db:                     db,
 	}
 }
 
@@ -42,9 +78,27 @@ func (s *Storage) WithTx(ctx context.Context, fn func(*Storage) error) error {
 	}
 
 	txStorage := &Storage{
-		Event:   NewEventRepository(tx),
-		Booking: NewBookingRepository(tx),
-		User:    NewUserRepository(tx),
+		Event:                  NewEventRepository(tx),
+		Booking:                NewBookingRepository(tx),
+		User:                   NewUserRepository(tx),
+		Waitlist:               NewWaitlistRepository(tx),
+		Outbox:                 NewOutboxRepository(tx),
+		NotificationPreference: NewNotificationPreferenceRepository(tx),
+		Reminder:               NewReminderRepository(tx),
+		TgLinkCode:             NewTgLinkCodeRepository(tx),
+		Session:                NewSessionRepository(tx),
+		PasswordReset:          NewPasswordResetRepository(tx),
+		EmailVerification:      NewEmailVerificationRepository(tx),
+		LoginAttempt:           NewLoginAttemptRepository(tx),
+		Permission:             NewPermissionRepository(tx),
+		DataExport:             NewDataExportRepository(tx),
+		TicketType:             NewTicketTypeRepository(tx),
+		Payment:                NewPaymentRepository(tx),
+		Refund:                 NewRefundRepository(tx),
+		CancellationPolicy:     NewCancellationPolicyRepository(tx),
+		PromoCode:              NewPromoCodeRepository(tx),
+		Venue:                  NewVenueRepository(tx),
+		Seat:                   NewSeatRepository(tx),
 	}
 
 	defer func() {
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	DeleteByUser(ctx context.Context, userID int) error
+	Consume(ctx context.Context, tokenHash string) (int, error)
+}
+
+type emailVerificationRepository struct {
+	db dbInterface
+}
+
+func NewEmailVerificationRepository(db dbInterface) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (er *emailVerificationRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO email_verification_tokens (token_hash, user_id, expires_at, created_at)
+				VALUES ($1, $2, $3, $4)`
+	_, err := er.db.ExecContext(ctx, query, tokenHash, userID, expiresAt, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (er *emailVerificationRepository) DeleteByUser(ctx context.Context, userID int) error {
+	query := `DELETE FROM email_verification_tokens
+				WHERE user_id=$1`
+	_, err := er.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns its owner.
+// Returns sql.ErrNoRows if there is no such token.
+func (er *emailVerificationRepository) Consume(ctx context.Context, tokenHash string) (int, error) {
+	query := `UPDATE email_verification_tokens
+				SET used_at=$2
+				WHERE token_hash=$1 AND used_at IS NULL AND expires_at > $2
+				RETURNING user_id`
+	var userID int
+	err := er.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
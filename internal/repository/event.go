@@ -11,10 +11,15 @@ import (
 )
 
 type EventRepository interface {
-	Create(ctx context.Context, e model.EventInCreate) error
+	Create(ctx context.Context, e model.EventInCreate, organizerID int) (int, error)
 	GetByID(ctx context.Context, id int) (model.EventInRepo, error)
+	GetByIDForUpdate(ctx context.Context, id int) (model.EventInRepo, error)
 	GetListEvents(ctx context.Context, req model.EventGetRequest) ([]model.EventInRepo, error)
-	GetCountEvents(ctx context.Context) (int, error)
+	GetCountEvents(ctx context.Context, includeDrafts bool, organizerID int) (int, error)
+	Update(ctx context.Context, e model.EventInRepo) error
+	UpdateStatus(ctx context.Context, id int, status string) error
+	Delete(ctx context.Context, id int) error
+	FinishPastEvents(ctx context.Context) (int64, error)
 }
 
 type eventRepository struct {
@@ -25,23 +30,31 @@ func NewEventRepository(db dbInterface) EventRepository {
 	return &eventRepository{db: db}
 }
 
-func (er *eventRepository) Create(ctx context.Context, e model.EventInCreate) error {
+func (er *eventRepository) Create(ctx context.Context, e model.EventInCreate, organizerID int) (int, error) {
 
 	reservationPeriod, err := time.ParseDuration(e.ReservationPeriod)
 	if err != nil {
-		return fmt.Errorf("invalid reservation_period format (use '30m', '1h', etc): %w", err)
+		return 0, fmt.Errorf("invalid reservation_period format (use '30m', '1h', etc): %w", err)
+	}
+
+	status := model.EventStatusPublished
+	if e.Draft {
+		status = model.EventStatusDraft
 	}
 
 	query := `INSERT INTO events (title, event_description, event_date,
-				event_status, total_place, reservation_period, booking_confirmation,created_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err = er.db.ExecContext(ctx,
+				event_status, total_place, reservation_period, booking_confirmation,created_at, organizer_id, venue_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				RETURNING event_id`
+	var id int
+	err = er.db.QueryRowContext(ctx,
 		query,
-		e.Title, e.Description, e.EventDate, model.EventStatusPending, e.TotalPlace, reservationPeriod, e.BookingConfimation, time.Now())
+		e.Title, e.Description, e.EventDate, status, e.TotalPlace, reservationPeriod, e.BookingConfimation, time.Now(),
+		organizerID, e.VenueID).Scan(&id)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
 func (er *eventRepository) GetByID(ctx context.Context, id int) (model.EventInRepo, error) {
@@ -62,7 +75,8 @@ func (er *eventRepository) GetByID(ctx context.Context, id int) (model.EventInRe
 	var record model.EventInRepo
 	if res.Next() {
 		err := res.Scan(&record.ID, &record.Title, &record.Description, &record.EventDate, &record.Status,
-			&record.TotalPlace, &record.ReservationPeriod, &record.BookingConfimation, &record.CreatedAt)
+			&record.TotalPlace, &record.ReservationPeriod, &record.BookingConfimation, &record.CreatedAt,
+			&record.OrganizerID, &record.VenueID)
 		if err != nil {
 			return model.EventInRepo{}, err
 		}
@@ -70,6 +84,27 @@ func (er *eventRepository) GetByID(ctx context.Context, id int) (model.EventInRe
 	return record, nil
 }
 
+// GetByIDForUpdate locks the event row until the end of the transaction.
+// Every seat allocation takes this lock first, so occupancy checks for one
+// event are serialized and parallel bookings cannot oversell it.
+func (er *eventRepository) GetByIDForUpdate(ctx context.Context, id int) (model.EventInRepo, error) {
+	query := `SELECT event_id, title, event_description, event_date, event_status,
+				total_place, reservation_period, booking_confirmation, created_at, organizer_id, venue_id
+				FROM events
+				WHERE event_id=$1
+				FOR UPDATE`
+	res := er.db.QueryRowContext(ctx, query, id)
+
+	var record model.EventInRepo
+	err := res.Scan(&record.ID, &record.Title, &record.Description, &record.EventDate, &record.Status,
+		&record.TotalPlace, &record.ReservationPeriod, &record.BookingConfimation, &record.CreatedAt,
+		&record.OrganizerID, &record.VenueID)
+	if err != nil {
+		return model.EventInRepo{}, err
+	}
+	return record, nil
+}
+
 func (er *eventRepository) GetListEvents(ctx context.Context, req model.EventGetRequest) ([]model.EventInRepo, error) {
 	var query string
 	switch req.Mode {
@@ -77,16 +112,20 @@ func (er *eventRepository) GetListEvents(ctx context.Context, req model.EventGet
 		query = `SELECT *
 					FROM events
 					WHERE created_at > $1 AND event_id > $2
+						AND ($4 OR event_status <> 'draft')
+						AND ($5 = 0 OR organizer_id = $5)
 					ORDER BY created_at ASC, event_id ASC
 					LIMIT $3`
 	case "prev":
 		query = `SELECT *
 					FROM events
-					WHERE (created_at < $1) OR (created_at = $1 AND event_id < $2)
+					WHERE ((created_at < $1) OR (created_at = $1 AND event_id < $2))
+						AND ($4 OR event_status <> 'draft')
+						AND ($5 = 0 OR organizer_id = $5)
 					ORDER BY created_at DESC, event_id DESC
 					LIMIT $3`
 	}
-	args := []any{req.LastCreatedAt, req.LastID, req.PageSize}
+	args := []any{req.LastCreatedAt, req.LastID, req.PageSize, req.IncludeDrafts, req.OrganizerID}
 
 	res, err := er.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -103,7 +142,8 @@ func (er *eventRepository) GetListEvents(ctx context.Context, req model.EventGet
 	for res.Next() {
 		var temp model.EventInRepo
 		err := res.Scan(&temp.ID, &temp.Title, &temp.Description, &temp.EventDate, &temp.Status,
-			&temp.TotalPlace, &temp.ReservationPeriod, &temp.BookingConfimation, &temp.CreatedAt)
+			&temp.TotalPlace, &temp.ReservationPeriod, &temp.BookingConfimation, &temp.CreatedAt,
+			&temp.OrganizerID, &temp.VenueID)
 		if err != nil {
 			return nil, err
 		}
@@ -112,10 +152,12 @@ func (er *eventRepository) GetListEvents(ctx context.Context, req model.EventGet
 	return e, nil
 }
 
-func (er *eventRepository) GetCountEvents(ctx context.Context) (int, error) {
+func (er *eventRepository) GetCountEvents(ctx context.Context, includeDrafts bool, organizerID int) (int, error) {
 	query := `SELECT COUNT(*)
-				FROM events`
-	res := er.db.QueryRowContext(ctx, query)
+				FROM events
+				WHERE ($1 OR event_status <> 'draft')
+					AND ($2 = 0 OR organizer_id = $2)`
+	res := er.db.QueryRowContext(ctx, query, includeDrafts, organizerID)
 	if res.Err() != nil {
 		return 0, res.Err()
 	}
@@ -127,3 +169,50 @@ func (er *eventRepository) GetCountEvents(ctx context.Context) (int, error) {
 	}
 	return count, nil
 }
+
+func (er *eventRepository) Update(ctx context.Context, e model.EventInRepo) error {
+	query := `UPDATE events
+				SET title=$1, event_description=$2, event_date=$3, total_place=$4,
+					reservation_period=$5, booking_confirmation=$6
+				WHERE event_id=$7`
+	_, err := er.db.ExecContext(ctx, query, e.Title, e.Description, e.EventDate, e.TotalPlace,
+		e.ReservationPeriod, e.BookingConfimation, e.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (er *eventRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	query := `UPDATE events
+				SET event_status=$1
+				WHERE event_id=$2`
+	_, err := er.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (er *eventRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM events
+				WHERE event_id=$1`
+	_, err := er.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FinishPastEvents moves every event whose date has passed and that is still
+// on sale (or closed for sales) to the finished state.
+func (er *eventRepository) FinishPastEvents(ctx context.Context) (int64, error) {
+	query := `UPDATE events
+				SET event_status='finished'
+				WHERE event_date < $1 AND event_status IN ('published', 'sales_closed')`
+	res, err := er.db.ExecContext(ctx, query, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
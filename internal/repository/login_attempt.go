@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"EventBooker/internal/model"
+)
+
+type LoginAttemptRepository interface {
+	RegisterAttempt(ctx context.Context, key string, now, windowStart time.Time) (model.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Forgive(ctx context.Context, key string, lockedUntil *time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+type loginAttemptRepository struct {
+	db dbInterface
+}
+
+func NewLoginAttemptRepository(db dbInterface) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// RegisterAttempt counts an attempt as failed unless a lockout is running,
+// and returns the counters after it. The count starts over when the previous
+// failure is older than windowStart. The row stays locked until the end of
+// the transaction.
+func (lr *loginAttemptRepository) RegisterAttempt(ctx context.Context, key string, now, windowStart time.Time) (model.LoginAttempt, error) {
+	query := `INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+				VALUES ($1, 1, $2)
+				ON CONFLICT (attempt_key) DO UPDATE
+				SET failures = CASE
+						WHEN login_attempts.locked_until > $2 THEN login_attempts.failures
+						WHEN login_attempts.last_failure_at < $3 THEN 1
+						ELSE login_attempts.failures + 1
+					END,
+					last_failure_at = CASE
+						WHEN login_attempts.locked_until > $2 THEN login_attempts.last_failure_at
+						ELSE $2
+					END
+				RETURNING attempt_key, failures, last_failure_at, locked_until`
+	var a model.LoginAttempt
+	err := lr.db.QueryRowContext(ctx, query, key, now, windowStart).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &a.LockedUntil)
+	if err != nil {
+		return model.LoginAttempt{}, err
+	}
+	return a, nil
+}
+
+func (lr *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE login_attempts
+				SET locked_until=$2
+				WHERE attempt_key=$1`
+	_, err := lr.db.ExecContext(ctx, query, key, until)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Forgive takes back one failure and clears the lockout if it is the one
+// given.
+func (lr *loginAttemptRepository) Forgive(ctx context.Context, key string, lockedUntil *time.Time) error {
+	query := `UPDATE login_attempts
+				SET failures = GREATEST(failures - 1, 0),
+					locked_until = CASE WHEN locked_until = $2 THEN NULL ELSE locked_until END
+				WHERE attempt_key=$1`
+	_, err := lr.db.ExecContext(ctx, query, key, lockedUntil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *loginAttemptRepository) Delete(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts
+				WHERE attempt_key=$1`
+	_, err := lr.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
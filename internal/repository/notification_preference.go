@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type NotificationPreferenceRepository interface {
+	GetByUser(ctx context.Context, userID int) ([]model.NotificationPreferenceInRepo, error)
+	Upsert(ctx context.Context, userID int, eventType, channel string, enabled bool) error
+}
+
+type notificationPreferenceRepository struct {
+	db dbInterface
+}
+
+func NewNotificationPreferenceRepository(db dbInterface) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (npr *notificationPreferenceRepository) GetByUser(ctx context.Context, userID int) ([]model.NotificationPreferenceInRepo, error) {
+	query := `SELECT user_id, event_type, channel, enabled, updated_at
+				FROM notification_preferences
+				WHERE user_id=$1`
+	res, err := npr.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var p []model.NotificationPreferenceInRepo
+	for res.Next() {
+		var temp model.NotificationPreferenceInRepo
+		err := res.Scan(&temp.UserID, &temp.EventType, &temp.Channel, &temp.Enabled, &temp.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, temp)
+	}
+	return p, nil
+}
+
+func (npr *notificationPreferenceRepository) Upsert(ctx context.Context, userID int, eventType, channel string, enabled bool) error {
+	query := `INSERT INTO notification_preferences (user_id, event_type, channel, enabled, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (user_id, event_type, channel)
+				DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at`
+	_, err := npr.db.ExecContext(ctx, query, userID, eventType, channel, enabled, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
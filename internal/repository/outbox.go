@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, n model.NotificationInCreate, channel, recipient string) error
+	ClaimDue(ctx context.Context, limit int, channels []string, leaseUntil time.Time) ([]model.NotificationInRepo, error)
+	MarkSent(ctx context.Context, id int) error
+	MarkRetry(ctx context.Context, id, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id, attempts int, lastError string) error
+	Resend(ctx context.Context, id int) error
+	DeleteByUser(ctx context.Context, userID int) error
+	GetList(ctx context.Context, req model.NotificationGetRequest) ([]model.NotificationInRepo, error)
+	GetByUser(ctx context.Context, userID int) ([]model.NotificationInRepo, error)
+	GetCount(ctx context.Context, status string) (int, error)
+}
+
+type outboxRepository struct {
+	db dbInterface
+}
+
+func NewOutboxRepository(db dbInterface) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (or *outboxRepository) Create(ctx context.Context, n model.NotificationInCreate, channel, recipient string) error {
+	query := `INSERT INTO notification_outbox (user_id, event_type, channel, recipient, subject, message,
+				status, next_attempt_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)`
+	_, err := or.db.ExecContext(ctx, query, n.UserID, n.EventType, channel, recipient, n.Subject, n.Text,
+		model.StatusNotificationPending, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimDue takes up to limit pending notifications for the given channels
+// whose next attempt is due and pushes their next attempt to leaseUntil, so
+// other dispatchers leave them alone while they are being sent. Rows locked by
+// a concurrent claim are skipped. A claim that is never recorded expires with
+// its lease and the message is picked up again.
+func (or *outboxRepository) ClaimDue(ctx context.Context, limit int, channels []string, leaseUntil time.Time) ([]model.NotificationInRepo, error) {
+	query := `UPDATE notification_outbox
+				SET next_attempt_at=$4
+				WHERE notification_id IN (
+					SELECT notification_id
+					FROM notification_outbox
+					WHERE status='pending' AND next_attempt_at <= $1 AND channel = ANY($3)
+					ORDER BY next_attempt_at ASC, notification_id ASC
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED
+				)
+				RETURNING notification_id, COALESCE(user_id, 0), event_type, channel, recipient, subject, message,
+				status, attempts, COALESCE(last_error, ''),
+				next_attempt_at, created_at, sent_at`
+	res, err := or.db.QueryContext(ctx, query, time.Now(), limit, pq.Array(channels), leaseUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return scanNotifications(res)
+}
+
+func (or *outboxRepository) MarkSent(ctx context.Context, id int) error {
+	query := `UPDATE notification_outbox
+				SET status='sent', attempts=attempts+1, sent_at=$1
+				WHERE notification_id=$2`
+	_, err := or.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (or *outboxRepository) MarkRetry(ctx context.Context, id, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE notification_outbox
+				SET attempts=$1, next_attempt_at=$2, last_error=$3
+				WHERE notification_id=$4`
+	_, err := or.db.ExecContext(ctx, query, attempts, nextAttemptAt, lastError, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (or *outboxRepository) MarkFailed(ctx context.Context, id, attempts int, lastError string) error {
+	query := `UPDATE notification_outbox
+				SET status='failed', attempts=$1, last_error=$2
+				WHERE notification_id=$3`
+	_, err := or.db.ExecContext(ctx, query, attempts, lastError, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Resend puts a dead-lettered notification back into the queue with a fresh
+// attempt budget. Returns sql.ErrNoRows if there is no failed notification
+// with this id.
+func (or *outboxRepository) Resend(ctx context.Context, id int) error {
+	query := `UPDATE notification_outbox
+				SET status='pending', attempts=0, next_attempt_at=$1
+				WHERE notification_id=$2 AND status='failed'`
+	res, err := or.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteByUser removes the messages of the user, sent or not, since they hold
+// its address and message texts.
+func (or *outboxRepository) DeleteByUser(ctx context.Context, userID int) error {
+	query := `DELETE FROM notification_outbox
+				WHERE user_id=$1`
+	_, err := or.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (or *outboxRepository) GetList(ctx context.Context, req model.NotificationGetRequest) ([]model.NotificationInRepo, error) {
+	var query string
+	switch req.Mode {
+	case "next":
+		query = `SELECT notification_id, COALESCE(user_id, 0), event_type, channel, recipient, subject, message,
+					status, attempts, COALESCE(last_error, ''),
+					next_attempt_at, created_at, sent_at
+					FROM notification_outbox
+					WHERE created_at > $1 AND notification_id > $2
+						AND ($4 = '' OR status = $4)
+					ORDER BY created_at ASC, notification_id ASC
+					LIMIT $3`
+	case "prev":
+		query = `SELECT notification_id, COALESCE(user_id, 0), event_type, channel, recipient, subject, message,
+					status, attempts, COALESCE(last_error, ''),
+					next_attempt_at, created_at, sent_at
+					FROM notification_outbox
+					WHERE ((created_at < $1) OR (created_at = $1 AND notification_id < $2))
+						AND ($4 = '' OR status = $4)
+					ORDER BY created_at DESC, notification_id DESC
+					LIMIT $3`
+	}
+	args := []any{req.LastCreatedAt, req.LastID, req.PageSize, req.Status}
+
+	res, err := or.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return scanNotifications(res)
+}
+
+// GetByUser returns every message addressed to the user, oldest first.
+func (or *outboxRepository) GetByUser(ctx context.Context, userID int) ([]model.NotificationInRepo, error) {
+	query := `SELECT notification_id, COALESCE(user_id, 0), event_type, channel, recipient, subject, message,
+				status, attempts, COALESCE(last_error, ''),
+				next_attempt_at, created_at, sent_at
+				FROM notification_outbox
+				WHERE user_id=$1
+				ORDER BY created_at ASC, notification_id ASC`
+	res, err := or.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return scanNotifications(res)
+}
+
+func (or *outboxRepository) GetCount(ctx context.Context, status string) (int, error) {
+	query := `SELECT COUNT(*)
+				FROM notification_outbox
+				WHERE $1 = '' OR status = $1`
+	res := or.db.QueryRowContext(ctx, query, status)
+	if res.Err() != nil {
+		return 0, res.Err()
+	}
+
+	var count int
+	err := res.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func scanNotifications(res *sql.Rows) ([]model.NotificationInRepo, error) {
+	var n []model.NotificationInRepo
+	for res.Next() {
+		var temp model.NotificationInRepo
+		err := res.Scan(&temp.ID, &temp.UserID, &temp.EventType, &temp.Channel, &temp.Recipient, &temp.Subject,
+			&temp.Text, &temp.Status, &temp.Attempts, &temp.LastError,
+			&temp.NextAttemptAt, &temp.CreatedAt, &temp.SentAt)
+		if err != nil {
+			return nil, err
+		}
+		n = append(n, temp)
+	}
+	return n, nil
+}
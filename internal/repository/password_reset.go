@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	DeleteByUser(ctx context.Context, userID int) error
+	Consume(ctx context.Context, tokenHash string) (int, error)
+}
+
+type passwordResetRepository struct {
+	db dbInterface
+}
+
+func NewPasswordResetRepository(db dbInterface) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (pr *passwordResetRepository) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at)
+				VALUES ($1, $2, $3, $4)`
+	_, err := pr.db.ExecContext(ctx, query, tokenHash, userID, expiresAt, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *passwordResetRepository) DeleteByUser(ctx context.Context, userID int) error {
+	query := `DELETE FROM password_reset_tokens
+				WHERE user_id=$1`
+	_, err := pr.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired token as used and returns its owner.
+// Returns sql.ErrNoRows if there is no such token.
+func (pr *passwordResetRepository) Consume(ctx context.Context, tokenHash string) (int, error) {
+	query := `UPDATE password_reset_tokens
+				SET used_at=$2
+				WHERE token_hash=$1 AND used_at IS NULL AND expires_at > $2
+				RETURNING user_id`
+	var userID int
+	err := pr.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
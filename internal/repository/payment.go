@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"EventBooker/internal/model"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, p model.PaymentInCreate) (model.PaymentInRepo, error)
+	GetByProviderIDForUpdate(ctx context.Context, provider, providerPaymentID string) (model.PaymentInRepo, error)
+	GetPendingByBooking(ctx context.Context, bookingID int) (model.PaymentInRepo, error)
+	GetSucceededByBooking(ctx context.Context, bookingID int) (model.PaymentInRepo, error)
+	GetSucceededByBookingForUpdate(ctx context.Context, bookingID int) (model.PaymentInRepo, error)
+	UpdateStatus(ctx context.Context, id int, status string) error
+	ExistsByEvent(ctx context.Context, eventID int) (bool, error)
+}
+
+type paymentRepository struct {
+	db dbInterface
+}
+
+func NewPaymentRepository(db dbInterface) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (pr *paymentRepository) Create(ctx context.Context, p model.PaymentInCreate) (model.PaymentInRepo, error) {
+	query := `INSERT INTO payments (booking_id, provider, provider_payment_id, amount, currency, status,
+				confirmation_url, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $8)
+				RETURNING payment_id, created_at`
+	record := model.PaymentInRepo{
+		BookingID:         p.BookingID,
+		Provider:          p.Provider,
+		ProviderPaymentID: p.ProviderPaymentID,
+		Amount:            p.Amount,
+		Currency:          p.Currency,
+		Status:            model.StatusPaymentPending,
+		ConfirmationURL:   p.ConfirmationURL,
+	}
+	err := pr.db.QueryRowContext(ctx, query, p.BookingID, p.Provider, p.ProviderPaymentID, p.Amount, p.Currency,
+		model.StatusPaymentPending, p.ConfirmationURL, time.Now()).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return model.PaymentInRepo{}, err
+	}
+	record.UpdatedAt = record.CreatedAt
+	return record, nil
+}
+
+// GetByProviderIDForUpdate locks the payment, so concurrent deliveries of
+// the same webhook are applied one after another. Returns sql.ErrNoRows if
+// there is no such payment.
+func (pr *paymentRepository) GetByProviderIDForUpdate(ctx context.Context, provider, providerPaymentID string) (model.PaymentInRepo, error) {
+	query := `SELECT payment_id, booking_id, provider, provider_payment_id, amount, currency, status,
+				COALESCE(confirmation_url, ''), created_at, updated_at
+				FROM payments
+				WHERE provider=$1 AND provider_payment_id=$2
+				FOR UPDATE`
+	return scanPayment(pr.db.QueryRowContext(ctx, query, provider, providerPaymentID))
+}
+
+// GetPendingByBooking returns the latest unfinished payment of the booking.
+// Returns sql.ErrNoRows if there is none.
+func (pr *paymentRepository) GetPendingByBooking(ctx context.Context, bookingID int) (model.PaymentInRepo, error) {
+	query := `SELECT payment_id, booking_id, provider, provider_payment_id, amount, currency, status,
+				COALESCE(confirmation_url, ''), created_at, updated_at
+				FROM payments
+				WHERE booking_id=$1 AND status='pending'
+				ORDER BY created_at DESC, payment_id DESC
+				LIMIT 1`
+	return scanPayment(pr.db.QueryRowContext(ctx, query, bookingID))
+}
+
+// GetSucceededByBooking returns the payment the booking was confirmed with.
+// Returns sql.ErrNoRows for bookings that were never paid.
+func (pr *paymentRepository) GetSucceededByBooking(ctx context.Context, bookingID int) (model.PaymentInRepo, error) {
+	query := `SELECT payment_id, booking_id, provider, provider_payment_id, amount, currency, status,
+				COALESCE(confirmation_url, ''), created_at, updated_at
+				FROM payments
+				WHERE booking_id=$1 AND status='succeeded'
+				ORDER BY created_at DESC, payment_id DESC
+				LIMIT 1`
+	return scanPayment(pr.db.QueryRowContext(ctx, query, bookingID))
+}
+
+// GetSucceededByBookingForUpdate is GetSucceededByBooking that locks the
+// payment until the end of the transaction, so it is refunded only once.
+func (pr *paymentRepository) GetSucceededByBookingForUpdate(ctx context.Context, bookingID int) (model.PaymentInRepo, error) {
+	query := `SELECT payment_id, booking_id, provider, provider_payment_id, amount, currency, status,
+				COALESCE(confirmation_url, ''), created_at, updated_at
+				FROM payments
+				WHERE booking_id=$1 AND status='succeeded'
+				ORDER BY created_at DESC, payment_id DESC
+				LIMIT 1
+				FOR UPDATE`
+	return scanPayment(pr.db.QueryRowContext(ctx, query, bookingID))
+}
+
+func scanPayment(res *sql.Row) (model.PaymentInRepo, error) {
+	var p model.PaymentInRepo
+	err := res.Scan(&p.ID, &p.BookingID, &p.Provider, &p.ProviderPaymentID, &p.Amount, &p.Currency, &p.Status,
+		&p.ConfirmationURL, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return model.PaymentInRepo{}, err
+	}
+	return p, nil
+}
+
+func (pr *paymentRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	query := `UPDATE payments
+				SET status=$1, updated_at=$2
+				WHERE payment_id=$3`
+	_, err := pr.db.ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ExistsByEvent reports whether any booking of the event has a payment,
+// whatever its status.
+func (pr *paymentRepository) ExistsByEvent(ctx context.Context, eventID int) (bool, error) {
+	query := `SELECT EXISTS (
+					SELECT 1
+					FROM payments p
+					JOIN booking b ON b.booking_id = p.booking_id
+					WHERE b.event_id=$1
+				)`
+	var exists bool
+	err := pr.db.QueryRowContext(ctx, query, eventID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wb-go/wbf/zlog"
+)
+
+type PermissionRepository interface {
+	GetByRole(ctx context.Context, role string) ([]string, error)
+	GetAll(ctx context.Context) (map[string][]string, error)
+	SetForRole(ctx context.Context, role string, permissions []string) error
+}
+
+type permissionRepository struct {
+	db dbInterface
+}
+
+func NewPermissionRepository(db dbInterface) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (pr *permissionRepository) GetByRole(ctx context.Context, role string) ([]string, error) {
+	query := `SELECT permission
+				FROM role_permissions
+				WHERE role=$1
+				ORDER BY permission`
+	res, err := pr.db.QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var p []string
+	for res.Next() {
+		var temp string
+		err := res.Scan(&temp)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, temp)
+	}
+	return p, nil
+}
+
+func (pr *permissionRepository) GetAll(ctx context.Context) (map[string][]string, error) {
+	query := `SELECT role, permission
+				FROM role_permissions
+				ORDER BY role, permission`
+	res, err := pr.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	p := make(map[string][]string)
+	for res.Next() {
+		var role, permission string
+		err := res.Scan(&role, &permission)
+		if err != nil {
+			return nil, err
+		}
+		p[role] = append(p[role], permission)
+	}
+	return p, nil
+}
+
+// SetForRole replaces the permissions of the role.
+func (pr *permissionRepository) SetForRole(ctx context.Context, role string, permissions []string) error {
+	query := `DELETE FROM role_permissions
+				WHERE role=$1`
+	_, err := pr.db.ExecContext(ctx, query, role)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO role_permissions (role, permission)
+				VALUES ($1, $2)`
+	for _, p := range permissions {
+		_, err = pr.db.ExecContext(ctx, query, role, p)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type PromoCodeRepository interface {
+	Create(ctx context.Context, p model.PromoCodeInput) (int, error)
+	GetByID(ctx context.Context, id int) (model.PromoCodeInRepo, error)
+	GetByIDForUpdate(ctx context.Context, id int) (model.PromoCodeInRepo, error)
+	GetByCode(ctx context.Context, code string) (model.PromoCodeInRepo, error)
+	GetByCodeForUpdate(ctx context.Context, code string) (model.PromoCodeInRepo, error)
+	GetList(ctx context.Context) ([]model.PromoCodeInRepo, error)
+	Update(ctx context.Context, id int, p model.PromoCodeInput) error
+	Delete(ctx context.Context, id int) error
+	CountUserRedemptions(ctx context.Context, promoCodeID, userID int) (int, error)
+	Redeem(ctx context.Context, r model.PromoRedemptionInCreate) error
+	Release(ctx context.Context, bookingIDs []int) error
+}
+
+type promoCodeRepository struct {
+	db dbInterface
+}
+
+func NewPromoCodeRepository(db dbInterface) PromoCodeRepository {
+	return &promoCodeRepository{db: db}
+}
+
+const promoCodeColumns = `promo_code_id, code, discount_type, discount_value, COALESCE(currency, ''),
+				max_uses, max_uses_per_user, valid_from, valid_until, used_count, created_at`
+
+func (pr *promoCodeRepository) Create(ctx context.Context, p model.PromoCodeInput) (int, error) {
+	query := `INSERT INTO promo_codes (code, discount_type, discount_value, currency, max_uses,
+				max_uses_per_user, valid_from, valid_until, created_at)
+				VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+				RETURNING promo_code_id`
+	var id int
+	err := pr.db.QueryRowContext(ctx, query, p.Code, p.DiscountType, p.DiscountValue, p.Currency, p.MaxUses,
+		p.MaxUsesPerUser, p.ValidFrom, p.ValidUntil, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = pr.setRestrictions(ctx, id, p)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID returns the code with its restrictions. Returns sql.ErrNoRows if
+// there is no such code.
+func (pr *promoCodeRepository) GetByID(ctx context.Context, id int) (model.PromoCodeInRepo, error) {
+	query := `SELECT ` + promoCodeColumns + `
+				FROM promo_codes
+				WHERE promo_code_id=$1`
+	return pr.getOne(ctx, query, id)
+}
+
+// GetByIDForUpdate locks the code until the end of the transaction, so an
+// admin change sees the used_count that concurrent bookings leave behind.
+func (pr *promoCodeRepository) GetByIDForUpdate(ctx context.Context, id int) (model.PromoCodeInRepo, error) {
+	query := `SELECT ` + promoCodeColumns + `
+				FROM promo_codes
+				WHERE promo_code_id=$1
+				FOR UPDATE`
+	return pr.getOne(ctx, query, id)
+}
+
+func (pr *promoCodeRepository) GetByCode(ctx context.Context, code string) (model.PromoCodeInRepo, error) {
+	query := `SELECT ` + promoCodeColumns + `
+				FROM promo_codes
+				WHERE code=$1`
+	return pr.getOne(ctx, query, code)
+}
+
+// GetByCodeForUpdate locks the code until the end of the transaction, so
+// concurrent bookings check the usage limits one after another.
+func (pr *promoCodeRepository) GetByCodeForUpdate(ctx context.Context, code string) (model.PromoCodeInRepo, error) {
+	query := `SELECT ` + promoCodeColumns + `
+				FROM promo_codes
+				WHERE code=$1
+				FOR UPDATE`
+	return pr.getOne(ctx, query, code)
+}
+
+func (pr *promoCodeRepository) GetList(ctx context.Context) ([]model.PromoCodeInRepo, error) {
+	query := `SELECT ` + promoCodeColumns + `
+				FROM promo_codes
+				ORDER BY promo_code_id DESC`
+	res, err := pr.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var codes []model.PromoCodeInRepo
+	for res.Next() {
+		var p model.PromoCodeInRepo
+		err := res.Scan(&p.ID, &p.Code, &p.DiscountType, &p.DiscountValue, &p.Currency, &p.MaxUses,
+			&p.MaxUsesPerUser, &p.ValidFrom, &p.ValidUntil, &p.UsedCount, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, p)
+	}
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range codes {
+		err = pr.loadRestrictions(ctx, &codes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// Update replaces the settings and restrictions of the code, the usage count
+// is kept.
+func (pr *promoCodeRepository) Update(ctx context.Context, id int, p model.PromoCodeInput) error {
+	query := `UPDATE promo_codes
+				SET code=$2, discount_type=$3, discount_value=$4, currency=NULLIF($5, ''), max_uses=$6,
+				max_uses_per_user=$7, valid_from=$8, valid_until=$9
+				WHERE promo_code_id=$1`
+	_, err := pr.db.ExecContext(ctx, query, id, p.Code, p.DiscountType, p.DiscountValue, p.Currency, p.MaxUses,
+		p.MaxUsesPerUser, p.ValidFrom, p.ValidUntil)
+	if err != nil {
+		return err
+	}
+	return pr.setRestrictions(ctx, id, p)
+}
+
+func (pr *promoCodeRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM promo_codes
+				WHERE promo_code_id=$1`
+	_, err := pr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *promoCodeRepository) CountUserRedemptions(ctx context.Context, promoCodeID, userID int) (int, error) {
+	query := `SELECT COUNT(*)
+				FROM promo_redemptions
+				WHERE promo_code_id=$1 AND user_id=$2`
+	var count int
+	err := pr.db.QueryRowContext(ctx, query, promoCodeID, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Redeem records the use of the code by the booking and counts it.
+func (pr *promoCodeRepository) Redeem(ctx context.Context, r model.PromoRedemptionInCreate) error {
+	query := `INSERT INTO promo_redemptions (promo_code_id, booking_id, user_id, discount, created_at)
+				VALUES ($1, $2, $3, $4, $5)`
+	_, err := pr.db.ExecContext(ctx, query, r.PromoCodeID, r.BookingID, r.UserID, r.Discount, time.Now())
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE promo_codes
+				SET used_count = used_count + 1
+				WHERE promo_code_id=$1`
+	_, err = pr.db.ExecContext(ctx, query, r.PromoCodeID)
+	return err
+}
+
+// Release gives back the uses taken by bookings that are no longer active,
+// so they count neither against max_uses nor against the per-user limit.
+// Bookings without a code are skipped.
+func (pr *promoCodeRepository) Release(ctx context.Context, bookingIDs []int) error {
+	query := `WITH released AS (
+				DELETE FROM promo_redemptions
+				WHERE booking_id = ANY($1)
+				RETURNING promo_code_id
+			)
+			UPDATE promo_codes p
+				SET used_count = p.used_count - r.uses
+				FROM (SELECT promo_code_id, COUNT(*) AS uses
+					FROM released
+					GROUP BY promo_code_id) r
+				WHERE p.promo_code_id = r.promo_code_id`
+	_, err := pr.db.ExecContext(ctx, query, pq.Array(bookingIDs))
+	return err
+}
+
+func (pr *promoCodeRepository) getOne(ctx context.Context, query string, arg any) (model.PromoCodeInRepo, error) {
+	var p model.PromoCodeInRepo
+	err := pr.db.QueryRowContext(ctx, query, arg).Scan(&p.ID, &p.Code, &p.DiscountType, &p.DiscountValue,
+		&p.Currency, &p.MaxUses, &p.MaxUsesPerUser, &p.ValidFrom, &p.ValidUntil, &p.UsedCount, &p.CreatedAt)
+	if err != nil {
+		return model.PromoCodeInRepo{}, err
+	}
+
+	err = pr.loadRestrictions(ctx, &p)
+	if err != nil {
+		return model.PromoCodeInRepo{}, err
+	}
+	return p, nil
+}
+
+func (pr *promoCodeRepository) setRestrictions(ctx context.Context, id int, p model.PromoCodeInput) error {
+	query := `DELETE FROM promo_code_events
+				WHERE promo_code_id=$1`
+	_, err := pr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM promo_code_ticket_types
+				WHERE promo_code_id=$1`
+	_, err = pr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO promo_code_events (promo_code_id, event_id)
+				VALUES ($1, $2)`
+	for _, eventID := range p.EventIDs {
+		_, err = pr.db.ExecContext(ctx, query, id, eventID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query = `INSERT INTO promo_code_ticket_types (promo_code_id, ticket_type_id)
+				VALUES ($1, $2)`
+	for _, ticketTypeID := range p.TicketTypeIDs {
+		_, err = pr.db.ExecContext(ctx, query, id, ticketTypeID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pr *promoCodeRepository) loadRestrictions(ctx context.Context, p *model.PromoCodeInRepo) error {
+	var err error
+	p.EventIDs, err = pr.queryIDs(ctx, `SELECT event_id
+				FROM promo_code_events
+				WHERE promo_code_id=$1
+				ORDER BY event_id`, p.ID)
+	if err != nil {
+		return err
+	}
+
+	p.TicketTypeIDs, err = pr.queryIDs(ctx, `SELECT ticket_type_id
+				FROM promo_code_ticket_types
+				WHERE promo_code_id=$1
+				ORDER BY ticket_type_id`, p.ID)
+	return err
+}
+
+func (pr *promoCodeRepository) queryIDs(ctx context.Context, query string, id int) ([]int, error) {
+	res, err := pr.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	ids := []int{}
+	for res.Next() {
+		var v int
+		if err := res.Scan(&v); err != nil {
+			return nil, err
+		}
+		ids = append(ids, v)
+	}
+	return ids, res.Err()
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"EventBooker/internal/model"
+)
+
+type RefundRepository interface {
+	Create(ctx context.Context, r model.RefundInCreate) (int, error)
+}
+
+type refundRepository struct {
+	db dbInterface
+}
+
+func NewRefundRepository(db dbInterface) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (rr *refundRepository) Create(ctx context.Context, r model.RefundInCreate) (int, error) {
+	query := `INSERT INTO refunds (payment_id, booking_id, amount, currency, refund_percent, reason, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING refund_id`
+	var id int
+	err := rr.db.QueryRowContext(ctx, query, r.PaymentID, r.BookingID, r.Amount, r.Currency, r.RefundPercent,
+		r.Reason, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
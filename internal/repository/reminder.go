@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type ReminderRepository interface {
+	SetForEvent(ctx context.Context, eventID int, remindBefore []time.Duration) error
+	GetByEvent(ctx context.Context, eventID int) ([]time.Duration, error)
+	ResetDeliveries(ctx context.Context, eventID int) error
+	ClaimDue(ctx context.Context) ([]model.ReminderDue, error)
+}
+
+type reminderRepository struct {
+	db dbInterface
+}
+
+func NewReminderRepository(db dbInterface) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// SetForEvent replaces the reminder schedule of the event.
+func (rr *reminderRepository) SetForEvent(ctx context.Context, eventID int, remindBefore []time.Duration) error {
+	query := `DELETE FROM event_reminders
+				WHERE event_id=$1`
+	_, err := rr.db.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO event_reminders (event_id, remind_before)
+				VALUES ($1, $2)
+				ON CONFLICT DO NOTHING`
+	for _, r := range remindBefore {
+		_, err := rr.db.ExecContext(ctx, query, eventID, r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rr *reminderRepository) GetByEvent(ctx context.Context, eventID int) ([]time.Duration, error) {
+	query := `SELECT remind_before
+				FROM event_reminders
+				WHERE event_id=$1
+				ORDER BY remind_before DESC`
+	res, err := rr.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var r []time.Duration
+	for res.Next() {
+		var temp time.Duration
+		err := res.Scan(&temp)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, temp)
+	}
+	return r, nil
+}
+
+// ResetDeliveries forgets which reminders of the event were already sent,
+// so a rescheduled event reminds its visitors again.
+func (rr *reminderRepository) ResetDeliveries(ctx context.Context, eventID int) error {
+	query := `DELETE FROM reminder_deliveries d
+				USING booking b
+				WHERE d.booking_id = b.booking_id AND b.event_id=$1`
+	_, err := rr.db.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimDue records a delivery for every reminder whose time has come and
+// returns the newly claimed ones. The primary key on reminder_deliveries makes
+// the claim idempotent: a reminder already recorded by an earlier run or by
+// another instance is skipped. When several reminders of a booking are due at
+// once (a late confirmation or downtime), all are recorded but only the
+// closest to the event is returned.
+func (rr *reminderRepository) ClaimDue(ctx context.Context) ([]model.ReminderDue, error) {
+	query := `WITH claimed AS (
+				INSERT INTO reminder_deliveries (booking_id, remind_before, sent_at)
+				SELECT b.booking_id, r.remind_before, $1
+					FROM event_reminders r
+					INNER JOIN events e ON r.event_id = e.event_id
+					INNER JOIN booking b ON r.event_id = b.event_id
+					WHERE b.status='confirmed'
+						AND e.event_status IN ('published', 'sales_closed')
+						AND e.event_date > $1
+						AND e.event_date - make_interval(secs => r.remind_before / 1000000000.0) <= $1
+				ON CONFLICT (booking_id, remind_before) DO NOTHING
+				RETURNING booking_id, remind_before
+			)
+			SELECT DISTINCT ON (c.booking_id)
+				c.booking_id,
+				b.event_id,
+				b.user_id,
+				b.quantity,
+				e.title,
+				e.event_date,
+				c.remind_before
+				FROM claimed c
+				INNER JOIN booking b ON c.booking_id = b.booking_id
+				INNER JOIN events e ON b.event_id = e.event_id
+				ORDER BY c.booking_id, c.remind_before ASC`
+	res, err := rr.db.QueryContext(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var r []model.ReminderDue
+	for res.Next() {
+		var temp model.ReminderDue
+		err := res.Scan(&temp.BookingID, &temp.EventID, &temp.UserID, &temp.Quantity,
+			&temp.TitleEvent, &temp.EventDate, &temp.RemindBefore)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, temp)
+	}
+	return r, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type SeatRepository interface {
+	AssignToEvent(ctx context.Context, eventID, ticketTypeID int, seatIDs []int) error
+	GetEventSeats(ctx context.Context, eventID int, now time.Time) ([]model.EventSeatInRepo, error)
+	GetEventSeatsByID(ctx context.Context, eventID int, seatIDs []int, now time.Time) ([]model.EventSeatInRepo, error)
+	Hold(ctx context.Context, bookingID, eventID int, seatIDs []int) error
+	GetByBookings(ctx context.Context, bookingIDs []int) (map[int][]model.SeatInRepo, error)
+}
+
+type seatRepository struct {
+	db dbInterface
+}
+
+func NewSeatRepository(db dbInterface) SeatRepository {
+	return &seatRepository{db: db}
+}
+
+// eventSeatQuery lists the seats of event $1 with their state at $2. Pending
+// bookings hold their seats only until expires_at, so a hold ends on time
+// even before the expiry job marks the booking expired.
+const eventSeatQuery = `SELECT s.seat_id, s.venue_id, s.section, s.seat_row, s.seat_number, s.accessible,
+				es.ticket_type_id,
+				(SELECT CASE
+						WHEN bool_or(b.status = 'confirmed') THEN 'sold'
+						WHEN COUNT(*) > 0 THEN 'held'
+						ELSE 'free'
+					END
+					FROM booking_seats bs
+					JOIN booking b ON b.booking_id = bs.booking_id
+					WHERE bs.event_id = es.event_id AND bs.seat_id = es.seat_id
+						AND (b.status = 'confirmed' OR (b.status = 'pending' AND b.expires_at > $2)))
+				FROM event_seats es
+				JOIN venue_seats s ON s.seat_id = es.seat_id
+				WHERE es.event_id=$1`
+
+func (sr *seatRepository) AssignToEvent(ctx context.Context, eventID, ticketTypeID int, seatIDs []int) error {
+	query := `INSERT INTO event_seats (event_id, seat_id, ticket_type_id)
+				SELECT $1, seat_id, $2
+				FROM unnest($3::int[]) AS seat_id`
+	_, err := sr.db.ExecContext(ctx, query, eventID, ticketTypeID, pq.Array(seatIDs))
+	return err
+}
+
+func (sr *seatRepository) GetEventSeats(ctx context.Context, eventID int, now time.Time) ([]model.EventSeatInRepo, error) {
+	query := eventSeatQuery + `
+				ORDER BY s.seat_id ASC`
+	res, err := sr.db.QueryContext(ctx, query, eventID, now)
+	if err != nil {
+		return nil, err
+	}
+	return scanEventSeats(res)
+}
+
+// GetEventSeatsByID returns the listed seats that are sold at the event,
+// unknown ids are left out.
+func (sr *seatRepository) GetEventSeatsByID(ctx context.Context, eventID int, seatIDs []int, now time.Time) ([]model.EventSeatInRepo, error) {
+	query := eventSeatQuery + `
+					AND es.seat_id = ANY($3::int[])
+				ORDER BY s.seat_id ASC`
+	res, err := sr.db.QueryContext(ctx, query, eventID, now, pq.Array(seatIDs))
+	if err != nil {
+		return nil, err
+	}
+	return scanEventSeats(res)
+}
+
+func (sr *seatRepository) Hold(ctx context.Context, bookingID, eventID int, seatIDs []int) error {
+	query := `INSERT INTO booking_seats (booking_id, event_id, seat_id)
+				SELECT $1, $2, seat_id
+				FROM unnest($3::int[]) AS seat_id`
+	_, err := sr.db.ExecContext(ctx, query, bookingID, eventID, pq.Array(seatIDs))
+	return err
+}
+
+// GetByBookings returns the seats of a page of bookings, keyed by booking id.
+// Bookings without seats are absent from the map.
+func (sr *seatRepository) GetByBookings(ctx context.Context, bookingIDs []int) (map[int][]model.SeatInRepo, error) {
+	query := `SELECT bs.booking_id, s.seat_id, s.venue_id, s.section, s.seat_row, s.seat_number, s.accessible
+				FROM booking_seats bs
+				JOIN venue_seats s ON s.seat_id = bs.seat_id
+				WHERE bs.booking_id = ANY($1)
+				ORDER BY bs.booking_id ASC, s.seat_id ASC`
+	res, err := sr.db.QueryContext(ctx, query, pq.Array(bookingIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	seats := make(map[int][]model.SeatInRepo)
+	for res.Next() {
+		var bookingID int
+		var s model.SeatInRepo
+		err := res.Scan(&bookingID, &s.ID, &s.VenueID, &s.Section, &s.Row, &s.Number, &s.Accessible)
+		if err != nil {
+			return nil, err
+		}
+		seats[bookingID] = append(seats[bookingID], s)
+	}
+	return seats, res.Err()
+}
+
+func scanEventSeats(res *sql.Rows) ([]model.EventSeatInRepo, error) {
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var seats []model.EventSeatInRepo
+	for res.Next() {
+		var s model.EventSeatInRepo
+		err := res.Scan(&s.ID, &s.VenueID, &s.Section, &s.Row, &s.Number, &s.Accessible, &s.TicketTypeID, &s.State)
+		if err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, res.Err()
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error)
+	Rotate(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (model.SessionInRepo, error)
+	RevokeByRefresh(ctx context.Context, refreshHash string) (int, error)
+	RevokeByPrevious(ctx context.Context, refreshHash string) (bool, error)
+	RevokeAllByUser(ctx context.Context, userID int) (int64, error)
+	IsActive(ctx context.Context, sessionID, userID int) (bool, error)
+	GetByUser(ctx context.Context, userID int) ([]model.SessionInRepo, error)
+}
+
+type sessionRepository struct {
+	db dbInterface
+}
+
+func NewSessionRepository(db dbInterface) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (sr *sessionRepository) Create(ctx context.Context, userID int, refreshHash string, expiresAt time.Time) (int, error) {
+	query := `INSERT INTO sessions (user_id, refresh_hash, expires_at, last_used_at, created_at)
+				VALUES ($1, $2, $3, $4, $4)
+				RETURNING session_id`
+	var id int
+	err := sr.db.QueryRowContext(ctx, query, userID, refreshHash, expiresAt, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Rotate replaces the refresh token of an active session and keeps the old
+// hash so that a replay of it can be detected. Returns sql.ErrNoRows if no
+// active session holds the token.
+func (sr *sessionRepository) Rotate(ctx context.Context, refreshHash, newHash string, expiresAt time.Time) (model.SessionInRepo, error) {
+	query := `UPDATE sessions
+				SET previous_hash=refresh_hash, refresh_hash=$2, expires_at=$3, last_used_at=$4
+				WHERE refresh_hash=$1 AND revoked_at IS NULL AND expires_at > $4
+				RETURNING session_id, user_id, expires_at, revoked_at, created_at`
+	var s model.SessionInRepo
+	err := sr.db.QueryRowContext(ctx, query, refreshHash, newHash, expiresAt, time.Now()).
+		Scan(&s.ID, &s.UserID, &s.ExpiresAt, &s.RevokedAt, &s.CreatedAt)
+	if err != nil {
+		return model.SessionInRepo{}, err
+	}
+	return s, nil
+}
+
+// RevokeByRefresh revokes the active session holding the token and returns
+// its id. Returns sql.ErrNoRows if there is no such session.
+func (sr *sessionRepository) RevokeByRefresh(ctx context.Context, refreshHash string) (int, error) {
+	query := `UPDATE sessions
+				SET revoked_at=$2
+				WHERE refresh_hash=$1 AND revoked_at IS NULL
+				RETURNING session_id`
+	var id int
+	err := sr.db.QueryRowContext(ctx, query, refreshHash, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// RevokeByPrevious revokes the session whose previous refresh token is
+// presented again, which means the token has leaked.
+func (sr *sessionRepository) RevokeByPrevious(ctx context.Context, refreshHash string) (bool, error) {
+	query := `UPDATE sessions
+				SET revoked_at=$2
+				WHERE previous_hash=$1 AND revoked_at IS NULL`
+	res, err := sr.db.ExecContext(ctx, query, refreshHash, time.Now())
+	if err != nil {
+		return false, err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (sr *sessionRepository) RevokeAllByUser(ctx context.Context, userID int) (int64, error) {
+	query := `UPDATE sessions
+				SET revoked_at=$2
+				WHERE user_id=$1 AND revoked_at IS NULL`
+	res, err := sr.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (sr *sessionRepository) IsActive(ctx context.Context, sessionID, userID int) (bool, error) {
+	query := `SELECT EXISTS (
+					SELECT 1 FROM sessions
+					WHERE session_id=$1 AND user_id=$2 AND revoked_at IS NULL AND expires_at > $3
+				)`
+	var active bool
+	err := sr.db.QueryRowContext(ctx, query, sessionID, userID, time.Now()).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+func (sr *sessionRepository) GetByUser(ctx context.Context, userID int) ([]model.SessionInRepo, error) {
+	query := `SELECT session_id, user_id, expires_at, revoked_at, last_used_at, created_at
+				FROM sessions
+				WHERE user_id=$1
+				ORDER BY created_at ASC, session_id ASC`
+	res, err := sr.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var sessions []model.SessionInRepo
+	for res.Next() {
+		var s model.SessionInRepo
+		err := res.Scan(&s.ID, &s.UserID, &s.ExpiresAt, &s.RevokedAt, &s.LastUsedAt, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
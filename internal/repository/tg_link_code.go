@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type TgLinkCodeRepository interface {
+	Create(ctx context.Context, userID int, codeHash string, expiresAt time.Time) error
+	DeleteByUser(ctx context.Context, userID int) error
+	Consume(ctx context.Context, codeHash string) (int, error)
+}
+
+type tgLinkCodeRepository struct {
+	db dbInterface
+}
+
+func NewTgLinkCodeRepository(db dbInterface) TgLinkCodeRepository {
+	return &tgLinkCodeRepository{db: db}
+}
+
+func (tr *tgLinkCodeRepository) Create(ctx context.Context, userID int, codeHash string, expiresAt time.Time) error {
+	query := `INSERT INTO tg_link_codes (code_hash, user_id, expires_at, created_at)
+				VALUES ($1, $2, $3, $4)`
+	_, err := tr.db.ExecContext(ctx, query, codeHash, userID, expiresAt, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *tgLinkCodeRepository) DeleteByUser(ctx context.Context, userID int) error {
+	query := `DELETE FROM tg_link_codes
+				WHERE user_id=$1`
+	_, err := tr.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Consume marks an unused, unexpired code as used and returns its owner.
+// Returns sql.ErrNoRows if there is no such code.
+func (tr *tgLinkCodeRepository) Consume(ctx context.Context, codeHash string) (int, error) {
+	query := `UPDATE tg_link_codes
+				SET used_at=$2
+				WHERE code_hash=$1 AND used_at IS NULL AND expires_at > $2
+				RETURNING user_id`
+	var userID int
+	err := tr.db.QueryRowContext(ctx, query, codeHash, time.Now()).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type TicketTypeRepository interface {
+	Create(ctx context.Context, eventID int, t model.TicketTypeInput) (int, error)
+	GetByID(ctx context.Context, id int) (model.TicketTypeInRepo, error)
+	GetByEvent(ctx context.Context, eventID int) ([]model.TicketTypeInRepo, error)
+	GetByEvents(ctx context.Context, eventIDs []int) (map[int][]model.TicketTypeInRepo, error)
+	Update(ctx context.Context, t model.TicketTypeInRepo) error
+	Delete(ctx context.Context, id int) error
+	HasBookings(ctx context.Context, id int) (bool, error)
+}
+
+type ticketTypeRepository struct {
+	db dbInterface
+}
+
+func NewTicketTypeRepository(db dbInterface) TicketTypeRepository {
+	return &ticketTypeRepository{db: db}
+}
+
+func (tr *ticketTypeRepository) Create(ctx context.Context, eventID int, t model.TicketTypeInput) (int, error) {
+	query := `INSERT INTO ticket_types (event_id, name, capacity, price, currency, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING ticket_type_id`
+	var id int
+	err := tr.db.QueryRowContext(ctx, query, eventID, t.Name, t.Capacity, t.Price, t.Currency, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID returns the tier with the seats held by its active bookings:
+// confirmed ones and pending ones whose hold has not run out yet.
+// Returns sql.ErrNoRows if there is no such tier.
+func (tr *ticketTypeRepository) GetByID(ctx context.Context, id int) (model.TicketTypeInRepo, error) {
+	query := `SELECT t.ticket_type_id, t.event_id, t.name, t.capacity, t.price, t.currency,
+				(SELECT COALESCE(SUM(b.quantity), 0)
+					FROM booking b
+					WHERE b.ticket_type_id = t.ticket_type_id
+						AND (b.status = 'confirmed' OR (b.status = 'pending' AND b.expires_at > $2))),
+				t.created_at
+				FROM ticket_types t
+				WHERE t.ticket_type_id=$1`
+	var t model.TicketTypeInRepo
+	err := tr.db.QueryRowContext(ctx, query, id, time.Now()).Scan(&t.ID, &t.EventID, &t.Name, &t.Capacity, &t.Price,
+		&t.Currency, &t.Occupied, &t.CreatedAt)
+	if err != nil {
+		return model.TicketTypeInRepo{}, err
+	}
+	return t, nil
+}
+
+// GetByEvent returns the tiers of the event with their occupancy counted as
+// in GetByID, cheapest first.
+func (tr *ticketTypeRepository) GetByEvent(ctx context.Context, eventID int) ([]model.TicketTypeInRepo, error) {
+	query := `SELECT t.ticket_type_id, t.event_id, t.name, t.capacity, t.price, t.currency,
+				COALESCE(SUM(b.quantity) FILTER (
+					WHERE b.status = 'confirmed' OR (b.status = 'pending' AND b.expires_at > $2)), 0),
+				t.created_at
+				FROM ticket_types t
+				LEFT JOIN booking b ON b.ticket_type_id = t.ticket_type_id
+				WHERE t.event_id=$1
+				GROUP BY t.ticket_type_id
+				ORDER BY t.price ASC, t.ticket_type_id ASC`
+	res, err := tr.db.QueryContext(ctx, query, eventID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	return scanTicketTypes(res)
+}
+
+// GetByEvents is GetByEvent for a page of events, keyed by event id. Events
+// without tiers are absent from the map.
+func (tr *ticketTypeRepository) GetByEvents(ctx context.Context, eventIDs []int) (map[int][]model.TicketTypeInRepo, error) {
+	query := `SELECT t.ticket_type_id, t.event_id, t.name, t.capacity, t.price, t.currency,
+				COALESCE(SUM(b.quantity) FILTER (
+					WHERE b.status = 'confirmed' OR (b.status = 'pending' AND b.expires_at > $2)), 0),
+				t.created_at
+				FROM ticket_types t
+				LEFT JOIN booking b ON b.ticket_type_id = t.ticket_type_id
+				WHERE t.event_id = ANY($1)
+				GROUP BY t.ticket_type_id
+				ORDER BY t.price ASC, t.ticket_type_id ASC`
+	res, err := tr.db.QueryContext(ctx, query, pq.Array(eventIDs), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	tiers, err := scanTicketTypes(res)
+	if err != nil {
+		return nil, err
+	}
+
+	byEvent := make(map[int][]model.TicketTypeInRepo, len(eventIDs))
+	for _, t := range tiers {
+		byEvent[t.EventID] = append(byEvent[t.EventID], t)
+	}
+	return byEvent, nil
+}
+
+func (tr *ticketTypeRepository) Update(ctx context.Context, t model.TicketTypeInRepo) error {
+	query := `UPDATE ticket_types
+				SET name=$2, capacity=$3, price=$4, currency=$5
+				WHERE ticket_type_id=$1`
+	_, err := tr.db.ExecContext(ctx, query, t.ID, t.Name, t.Capacity, t.Price, t.Currency)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *ticketTypeRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM ticket_types
+				WHERE ticket_type_id=$1`
+	_, err := tr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// HasBookings reports whether any booking, active or not, refers to the tier.
+func (tr *ticketTypeRepository) HasBookings(ctx context.Context, id int) (bool, error) {
+	query := `SELECT EXISTS (
+				SELECT 1
+				FROM booking
+				WHERE ticket_type_id=$1)`
+	var exists bool
+	err := tr.db.QueryRowContext(ctx, query, id).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func scanTicketTypes(res *sql.Rows) ([]model.TicketTypeInRepo, error) {
+	var tiers []model.TicketTypeInRepo
+	for res.Next() {
+		var t model.TicketTypeInRepo
+		err := res.Scan(&t.ID, &t.EventID, &t.Name, &t.Capacity, &t.Price, &t.Currency, &t.Occupied, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers, nil
+}
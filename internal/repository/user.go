@@ -10,9 +10,18 @@ import (
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, u model.UserInCreate) error
+	Create(ctx context.Context, u model.UserInCreate, role string) error
 	GetByID(ctx context.Context, id int) (model.UserInRepo, error)
 	GetByEmail(ctx context.Context, email string) (model.UserInRepo, error)
+	GetByTgChatID(ctx context.Context, chatID int64) (model.UserInRepo, error)
+	LinkTgChat(ctx context.Context, userID int, chatID int64) error
+	UnlinkTgChat(ctx context.Context, userID int) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, userID int) error
+	UpdateRole(ctx context.Context, userID int, role string) error
+	UpdateProfile(ctx context.Context, u model.UserInRepo) error
+	ChangeEmail(ctx context.Context, userID int, email string) error
+	Anonymize(ctx context.Context, userID int) error
 	GetListUsers(ctx context.Context, req model.UserGetRequest) ([]model.UserInRepo, error)
 	GetCountUsers(ctx context.Context) (int, error)
 }
@@ -25,17 +34,18 @@ func NewUserRepository(db dbInterface) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (ur *userRepository) Create(ctx context.Context, u model.UserInCreate) error {
-	query := `INSERT INTO users (email, password, role, tg_chatid, created_at)
-				VALUES ($1, $2, $3, $4, $5)`
+func (ur *userRepository) Create(ctx context.Context, u model.UserInCreate, role string) error {
+	query := `INSERT INTO users (email, password, role, created_at)
+				VALUES ($1, $2, $3, $4)`
 
-	_, err := ur.db.ExecContext(ctx, query, u.Email, u.Password, "user", u.TgChatID, time.Now())
+	_, err := ur.db.ExecContext(ctx, query, u.Email, u.Password, role, time.Now())
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetByID returns an empty record if there is no such user.
 func (ur *userRepository) GetByID(ctx context.Context, id int) (model.UserInRepo, error) {
 	query := `SELECT *
 				FROM users
@@ -45,9 +55,16 @@ func (ur *userRepository) GetByID(ctx context.Context, id int) (model.UserInRepo
 		return model.UserInRepo{}, err
 	}
 
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
 	var record model.UserInRepo
 	if res.Next() {
-		err := res.Scan(&record.ID, &record.Email, &record.Password, &record.Role, &record.TgChatID, &record.CreatedAt)
+		err := res.Scan(&record.ID, &record.Email, &record.Password, &record.Role, &record.TgChatID, &record.CreatedAt,
+			&record.EmailVerifiedAt, &record.Name, &record.Phone, &record.Locale, &record.DeletedAt)
 		if err != nil {
 			return model.UserInRepo{}, err
 		}
@@ -55,21 +72,141 @@ func (ur *userRepository) GetByID(ctx context.Context, id int) (model.UserInRepo
 	return record, nil
 }
 
-func (ur *userRepository) GetByEmail(ctx context.Context, email string) (model.UserInRepo, error) {
+// GetByTgChatID returns sql.ErrNoRows if no account is linked to the chat.
+func (ur *userRepository) GetByTgChatID(ctx context.Context, chatID int64) (model.UserInRepo, error) {
 	query := `SELECT *
 				FROM users
-				WHERE email = $1`
-	res, err := ur.db.QueryContext(ctx, query, email)
+				WHERE tg_chatid = $1`
+	res := ur.db.QueryRowContext(ctx, query, chatID)
+
+	var record model.UserInRepo
+	err := res.Scan(&record.ID, &record.Email, &record.Password, &record.Role, &record.TgChatID, &record.CreatedAt,
+		&record.EmailVerifiedAt, &record.Name, &record.Phone, &record.Locale, &record.DeletedAt)
 	if err != nil {
 		return model.UserInRepo{}, err
 	}
+	return record, nil
+}
+
+// LinkTgChat binds the chat to the user. A chat belongs to one account, so
+// it is taken away from whichever account had it before.
+func (ur *userRepository) LinkTgChat(ctx context.Context, userID int, chatID int64) error {
+	query := `UPDATE users
+				SET tg_chatid=NULL
+				WHERE tg_chatid=$1 AND user_id<>$2`
+	_, err := ur.db.ExecContext(ctx, query, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE users
+				SET tg_chatid=$1
+				WHERE user_id=$2`
+	_, err = ur.db.ExecContext(ctx, query, chatID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *userRepository) UnlinkTgChat(ctx context.Context, userID int) error {
+	query := `UPDATE users
+				SET tg_chatid=NULL
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *userRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	query := `UPDATE users
+				SET password=$2
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *userRepository) MarkEmailVerified(ctx context.Context, userID int) error {
+	query := `UPDATE users
+				SET email_verified_at=$2
+				WHERE user_id=$1 AND email_verified_at IS NULL`
+	_, err := ur.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *userRepository) UpdateRole(ctx context.Context, userID int, role string) error {
+	query := `UPDATE users
+				SET role=$2
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, userID, role)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ur *userRepository) UpdateProfile(ctx context.Context, u model.UserInRepo) error {
+	query := `UPDATE users
+				SET display_name=$2, phone=$3, locale=$4
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, u.ID, u.Name, u.Phone, u.Locale)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ChangeEmail sets a new, unverified email.
+func (ur *userRepository) ChangeEmail(ctx context.Context, userID int, email string) error {
+	query := `UPDATE users
+				SET email=$2, email_verified_at=NULL
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, userID, email)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Anonymize erases the personal data of a deleted account but keeps the row,
+// so bookings and events still point to it. The empty password hash matches
+// no password.
+func (ur *userRepository) Anonymize(ctx context.Context, userID int) error {
+	query := `UPDATE users
+				SET email='deleted-' || user_id || '@deleted.invalid', password='', tg_chatid=NULL,
+					display_name=NULL, phone=NULL, locale=NULL, deleted_at=$2
+				WHERE user_id=$1`
+	_, err := ur.db.ExecContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetByEmail looks the user up by a lower-cased email; accounts registered
+// with mixed case before emails were normalized are found too. It returns
+// sql.ErrNoRows if there is no such user.
+func (ur *userRepository) GetByEmail(ctx context.Context, email string) (model.UserInRepo, error) {
+	query := `SELECT *
+				FROM users
+				WHERE lower(email) = $1
+				ORDER BY user_id ASC
+				LIMIT 1`
+	res := ur.db.QueryRowContext(ctx, query, email)
 
 	var record model.UserInRepo
-	if res.Next() {
-		err := res.Scan(&record.ID, &record.Email, &record.Password, &record.Role, &record.TgChatID, &record.CreatedAt)
-		if err != nil {
-			return model.UserInRepo{}, err
-		}
+	err := res.Scan(&record.ID, &record.Email, &record.Password, &record.Role, &record.TgChatID, &record.CreatedAt,
+		&record.EmailVerifiedAt, &record.Name, &record.Phone, &record.Locale, &record.DeletedAt)
+	if err != nil {
+		return model.UserInRepo{}, err
 	}
 	return record, nil
 }
@@ -106,7 +243,8 @@ func (ur *userRepository) GetListUsers(ctx context.Context, req model.UserGetReq
 	var e []model.UserInRepo
 	for res.Next() {
 		var temp model.UserInRepo
-		err := res.Scan(&temp.ID, &temp.Email, &temp.Password, &temp.Role, &temp.TgChatID, &temp.CreatedAt)
+		err := res.Scan(&temp.ID, &temp.Email, &temp.Password, &temp.Role, &temp.TgChatID, &temp.CreatedAt,
+			&temp.EmailVerifiedAt, &temp.Name, &temp.Phone, &temp.Locale, &temp.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
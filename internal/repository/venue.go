@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type VenueRepository interface {
+	Create(ctx context.Context, v model.VenueInCreate) (int, error)
+	GetByID(ctx context.Context, id int) (model.VenueInRepo, error)
+	GetList(ctx context.Context) ([]model.VenueInRepo, error)
+	GetSeats(ctx context.Context, venueID int) ([]model.SeatInRepo, error)
+	Update(ctx context.Context, id int, v model.VenueInCreate) error
+	Delete(ctx context.Context, id int) error
+	IsUsed(ctx context.Context, id int) (bool, error)
+}
+
+type venueRepository struct {
+	db dbInterface
+}
+
+func NewVenueRepository(db dbInterface) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+func (vr *venueRepository) Create(ctx context.Context, v model.VenueInCreate) (int, error) {
+	query := `INSERT INTO venues (name, address, created_at)
+				VALUES ($1, $2, $3)
+				RETURNING venue_id`
+	var id int
+	err := vr.db.QueryRowContext(ctx, query, v.Name, v.Address, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = vr.insertSeats(ctx, id, v.Seats)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByID returns the venue with the number of its seats. Returns
+// sql.ErrNoRows if there is no such venue.
+func (vr *venueRepository) GetByID(ctx context.Context, id int) (model.VenueInRepo, error) {
+	query := `SELECT v.venue_id, v.name, v.address,
+				(SELECT COUNT(*) FROM venue_seats s WHERE s.venue_id = v.venue_id),
+				v.created_at
+				FROM venues v
+				WHERE v.venue_id=$1`
+	var v model.VenueInRepo
+	err := vr.db.QueryRowContext(ctx, query, id).Scan(&v.ID, &v.Name, &v.Address, &v.SeatCount, &v.CreatedAt)
+	if err != nil {
+		return model.VenueInRepo{}, err
+	}
+	return v, nil
+}
+
+func (vr *venueRepository) GetList(ctx context.Context) ([]model.VenueInRepo, error) {
+	query := `SELECT v.venue_id, v.name, v.address, COUNT(s.seat_id), v.created_at
+				FROM venues v
+				LEFT JOIN venue_seats s ON s.venue_id = v.venue_id
+				GROUP BY v.venue_id
+				ORDER BY v.name ASC, v.venue_id ASC`
+	res, err := vr.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var venues []model.VenueInRepo
+	for res.Next() {
+		var v model.VenueInRepo
+		err := res.Scan(&v.ID, &v.Name, &v.Address, &v.SeatCount, &v.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		venues = append(venues, v)
+	}
+	return venues, res.Err()
+}
+
+// GetSeats returns the seat map in the order it was described.
+func (vr *venueRepository) GetSeats(ctx context.Context, venueID int) ([]model.SeatInRepo, error) {
+	query := `SELECT seat_id, venue_id, section, seat_row, seat_number, accessible
+				FROM venue_seats
+				WHERE venue_id=$1
+				ORDER BY seat_id ASC`
+	res, err := vr.db.QueryContext(ctx, query, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var seats []model.SeatInRepo
+	for res.Next() {
+		var s model.SeatInRepo
+		err := res.Scan(&s.ID, &s.VenueID, &s.Section, &s.Row, &s.Number, &s.Accessible)
+		if err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, res.Err()
+}
+
+// Update changes the name and address and replaces the seat map when seats
+// are given. Seats sold at events cannot be removed, the foreign key from
+// event_seats rejects it.
+func (vr *venueRepository) Update(ctx context.Context, id int, v model.VenueInCreate) error {
+	query := `UPDATE venues
+				SET name=$2, address=$3
+				WHERE venue_id=$1`
+	_, err := vr.db.ExecContext(ctx, query, id, v.Name, v.Address)
+	if err != nil {
+		return err
+	}
+
+	if v.Seats == nil {
+		return nil
+	}
+
+	query = `DELETE FROM venue_seats
+				WHERE venue_id=$1`
+	_, err = vr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return vr.insertSeats(ctx, id, v.Seats)
+}
+
+func (vr *venueRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM venues
+				WHERE venue_id=$1`
+	_, err := vr.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsUsed reports whether any event, including cancelled and finished ones,
+// takes place at the venue.
+func (vr *venueRepository) IsUsed(ctx context.Context, id int) (bool, error) {
+	query := `SELECT EXISTS (
+				SELECT 1 FROM events WHERE venue_id=$1
+				)`
+	var used bool
+	err := vr.db.QueryRowContext(ctx, query, id).Scan(&used)
+	if err != nil {
+		return false, err
+	}
+	return used, nil
+}
+
+func (vr *venueRepository) insertSeats(ctx context.Context, venueID int, seats []model.SeatInCreate) error {
+	query := `INSERT INTO venue_seats (venue_id, section, seat_row, seat_number, accessible)
+				VALUES ($1, $2, $3, $4, $5)`
+	for _, s := range seats {
+		_, err := vr.db.ExecContext(ctx, query, venueID, s.Section, s.Row, s.Number, s.Accessible)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
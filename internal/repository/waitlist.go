@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, w model.WaitlistInCreate) error
+	GetNext(ctx context.Context, ticketTypeID int) (model.WaitlistInRepo, error)
+	IsWaiting(ctx context.Context, eventID, userID int) (bool, error)
+	UpdateStatus(ctx context.Context, status string, id int) error
+	Cancel(ctx context.Context, eventID, userID int) error
+	CancelByEvent(ctx context.Context, eventID int) error
+	CancelByUser(ctx context.Context, userID int) error
+	GetListByUser(ctx context.Context, userID int) ([]model.WaitlistWithEventDetails, error)
+}
+
+type waitlistRepository struct {
+	db dbInterface
+}
+
+func NewWaitlistRepository(db dbInterface) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (wr *waitlistRepository) Create(ctx context.Context, w model.WaitlistInCreate) error {
+	query := `INSERT INTO waitlist (user_id, event_id, quantity, ticket_type_id, status, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := wr.db.ExecContext(ctx, query, w.UserID, w.EventID, w.Quantity, w.TicketTypeID, model.StatusWaitlistWaiting,
+		time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetNext returns the head of the ticket type queue and locks it, so
+// parallel promotions never hand the same entry out twice. Returns
+// sql.ErrNoRows when nobody is waiting.
+func (wr *waitlistRepository) GetNext(ctx context.Context, ticketTypeID int) (model.WaitlistInRepo, error) {
+	query := `SELECT waitlist_id, user_id, event_id, quantity, ticket_type_id, status, created_at
+				FROM waitlist
+				WHERE ticket_type_id=$1 AND status='waiting'
+				ORDER BY created_at ASC, waitlist_id ASC
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED`
+	res := wr.db.QueryRowContext(ctx, query, ticketTypeID)
+
+	var record model.WaitlistInRepo
+	err := res.Scan(&record.ID, &record.UserID, &record.EventID, &record.Quantity, &record.TicketTypeID,
+		&record.Status, &record.CreatedAt)
+	if err != nil {
+		return model.WaitlistInRepo{}, err
+	}
+	return record, nil
+}
+
+func (wr *waitlistRepository) IsWaiting(ctx context.Context, eventID, userID int) (bool, error) {
+	query := `SELECT EXISTS (
+				SELECT 1
+				FROM waitlist
+				WHERE event_id=$1 AND user_id=$2 AND status='waiting')`
+	res := wr.db.QueryRowContext(ctx, query, eventID, userID)
+
+	var exists bool
+	err := res.Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (wr *waitlistRepository) UpdateStatus(ctx context.Context, status string, id int) error {
+	query := `UPDATE waitlist
+				SET status=$1
+				WHERE waitlist_id=$2`
+	_, err := wr.db.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wr *waitlistRepository) Cancel(ctx context.Context, eventID, userID int) error {
+	query := `UPDATE waitlist
+				SET status='cancelled'
+				WHERE event_id=$1 AND user_id=$2 AND status='waiting'`
+	_, err := wr.db.ExecContext(ctx, query, eventID, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wr *waitlistRepository) CancelByEvent(ctx context.Context, eventID int) error {
+	query := `UPDATE waitlist
+				SET status='cancelled'
+				WHERE event_id=$1 AND status='waiting'`
+	_, err := wr.db.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wr *waitlistRepository) CancelByUser(ctx context.Context, userID int) error {
+	query := `UPDATE waitlist
+				SET status='cancelled'
+				WHERE user_id=$1 AND status='waiting'`
+	_, err := wr.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wr *waitlistRepository) GetListByUser(ctx context.Context, userID int) ([]model.WaitlistWithEventDetails, error) {
+	query := `SELECT
+				w.waitlist_id,
+				w.user_id,
+				w.event_id,
+				w.quantity,
+				w.ticket_type_id,
+				w.status,
+				w.created_at,
+				CASE WHEN w.status = 'waiting' THEN (
+					SELECT COUNT(*)
+					FROM waitlist q
+					WHERE q.ticket_type_id = w.ticket_type_id AND q.status = 'waiting'
+						AND (q.created_at, q.waitlist_id) <= (w.created_at, w.waitlist_id)
+				) ELSE 0 END,
+				e.title,
+				e.event_date
+				FROM waitlist w
+				INNER JOIN events e ON e.event_id = w.event_id
+				WHERE w.user_id=$1
+				ORDER BY w.created_at DESC, w.waitlist_id DESC`
+	res, err := wr.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := res.Close(); err != nil {
+			zlog.Logger.Error().Msg(err.Error())
+		}
+	}()
+
+	var w []model.WaitlistWithEventDetails
+	for res.Next() {
+		var temp model.WaitlistWithEventDetails
+		err := res.Scan(&temp.ID, &temp.UserID, &temp.EventID, &temp.Quantity, &temp.TicketTypeID, &temp.Status,
+			&temp.CreatedAt, &temp.Position, &temp.EventTitle, &temp.EventDate)
+		if err != nil {
+			return nil, err
+		}
+		w = append(w, temp)
+	}
+	return w, nil
+}
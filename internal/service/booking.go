@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/wb-go/wbf/zlog"
@@ -18,56 +21,162 @@ type BookingService interface {
 	GetByUserID(ctx context.Context, req model.BookingGetRequest) ([]model.BookingInResponse, error)
 	GetCountUserBooking(ctx context.Context, userID int) (int, error)
 	CancelBook(ctx context.Context, bookID, eventID, userID int) error
+	GetCancellationQuote(ctx context.Context, bookID, eventID, userID int) (model.CancellationQuote, error)
 }
 
 type bookingService struct {
-	storage *repository.Storage
+	storage   *repository.Storage
+	providers map[string]PaymentProvider
 }
 
-func NewBookingService(s *repository.Storage) BookingService {
-	return &bookingService{storage: s}
+func NewBookingService(s *repository.Storage, providers map[string]PaymentProvider) BookingService {
+	return &bookingService{storage: s, providers: providers}
 }
 
 func (bs *bookingService) Book(ctx context.Context, b model.BookingInCreate) error {
+	if len(b.SeatIDs) > 0 {
+		slices.Sort(b.SeatIDs)
+		b.SeatIDs = slices.Compact(b.SeatIDs)
+		b.Quantity = len(b.SeatIDs)
+	}
+	if b.Quantity == 0 {
+		b.Quantity = 1
+	}
+	if b.Quantity < 0 {
+		return ErrInvalidQuantity
+	}
+
 	return bs.storage.WithTx(ctx, func(s *repository.Storage) error {
-		event, err := s.Event.GetByID(ctx, b.EventID)
+		err := checkCanBook(ctx, s, b.UserID)
 		if err != nil {
-			zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
-			if errors.Is(err, sql.ErrNoRows) {
-				return ErrBookingNotFound
-			}
 			return err
 		}
-		occupiedPlace, err := s.Booking.GetOccupiedPlace(ctx, b.EventID)
+
+		event, err := s.Event.GetByIDForUpdate(ctx, b.EventID)
 		if err != nil {
 			zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
 			return err
 		}
+		if event.Status == model.EventSratusCanceled {
+			return ErrEventCanceled
+		}
+
+		if event.Status != model.EventStatusPublished {
+			return ErrEventNotOnSale
+		}
 
 		if event.EventDate.Before(time.Now()) {
 			return ErrEventAlreadyPassed
 		}
 
-		if occupiedPlace >= event.TotalPlace {
+		// At events with assigned seating the seats decide the tier. Their
+		// states are checked under the event lock, so two bookings cannot
+		// take the same seat.
+		seated := event.VenueID != nil
+		if seated {
+			if len(b.SeatIDs) == 0 {
+				return ErrSeatsRequired
+			}
+			ticketTypeID, err := checkSeats(ctx, s, b.EventID, b.SeatIDs, time.Now())
+			if err != nil {
+				if !isSeatError(err) {
+					zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+				}
+				return err
+			}
+			if b.TicketTypeID != 0 && b.TicketTypeID != ticketTypeID {
+				return ErrSeatsMixedTiers
+			}
+			b.TicketTypeID = ticketTypeID
+		} else if len(b.SeatIDs) > 0 {
+			return ErrSeatsNotAssigned
+		}
+
+		tier, err := resolveTicketType(ctx, s, b.EventID, b.TicketTypeID)
+		if err != nil {
+			if !errors.Is(err, ErrTicketTypeNotFound) && !errors.Is(err, ErrTicketTypeRequired) {
+				zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+			}
+			return err
+		}
+
+		// Pending bookings stop counting once their hold runs out, even
+		// before the expiry job gets to them. Seated events rely on the seat
+		// states instead.
+		if !seated && tier.Occupied+b.Quantity > tier.Capacity {
 			return ErrNoSeatsAvailable
 		}
 
+		// The price is fixed at booking time, later tier changes do not
+		// affect it.
+		b.TicketTypeID = tier.ID
+		b.UnitPrice = tier.Price
+		b.Currency = tier.Currency
 		b.ExpiresAt = time.Now().Add(event.ReservationPeriod)
 
+		var promo model.PromoCodeInRepo
+		if b.PromoCode != "" {
+			promo, b.Discount, err = redeemPromoCode(ctx, s, b.PromoCode, b, time.Now())
+			if err != nil {
+				if !isPromoRedeemError(err) {
+					zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+				}
+				return err
+			}
+		}
+
+		// Paid bookings wait for the payment even when the event needs no
+		// confirmation.
 		var status string
-		if event.BookingConfimation {
+		if event.BookingConfimation || b.UnitPrice*int64(b.Quantity) > b.Discount {
 			status = model.StatusBookingPending
 
 		} else {
 			status = model.StatusBookingConfirmed
 		}
 
-		err = s.Booking.Create(ctx, b, status)
+		id, err := s.Booking.Create(ctx, b, status)
 		if err != nil {
 			zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
 
 			return err
 		}
+
+		if seated {
+			err = s.Seat.Hold(ctx, id, b.EventID, b.SeatIDs)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+				return err
+			}
+		}
+
+		if promo.ID != 0 {
+			err = s.PromoCode.Redeem(ctx, model.PromoRedemptionInCreate{
+				PromoCodeID: promo.ID,
+				BookingID:   id,
+				UserID:      b.UserID,
+				Discount:    b.Discount,
+			})
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+				return err
+			}
+		}
+
+		created, err := s.Booking.GetForTG(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+			return err
+		}
+
+		err = enqueueNotifications(ctx, s, []model.NotificationInCreate{buildBookingCreatedMessage(created, status, b.ExpiresAt)})
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.Book error: %v", err)
+			return err
+		}
 		return nil
 	})
 }
@@ -76,6 +185,16 @@ func (bs *bookingService) Confirm(ctx context.Context, bookID, eventID, userID i
 	return bs.storage.WithTx(ctx, func(s *repository.Storage) error {
 
 		event, err := s.Event.GetByID(ctx, eventID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.Confirm error: %v", err)
+			return err
+		}
+
+		if event.ID == 0 {
+			return ErrBookingNotFound
+		}
+
+		booking, err := s.Booking.GetByIDForUpdate(ctx, bookID)
 		if err != nil {
 			zlog.Logger.Error().Msgf("service.BookingService.Confirm error: %v", err)
 			if errors.Is(err, sql.ErrNoRows) {
@@ -84,11 +203,46 @@ func (bs *bookingService) Confirm(ctx context.Context, bookID, eventID, userID i
 			return err
 		}
 
-		if !event.BookingConfimation {
+		if booking.UserID != userID || booking.EventID != eventID {
+			return ErrBookingNotFound
+		}
+
+		// Events without confirmation only get pending bookings through
+		// waitlist promotion, so those still have to be accepted.
+		if !event.BookingConfimation && booking.Status != model.StatusBookingPending {
 			return ErrBookingNotRequired
 		}
 
-		err = bs.storage.Booking.UpdateStatus(ctx, model.StatusBookingConfirmed, bookID, eventID, userID)
+		if booking.Status != model.StatusBookingPending {
+			return ErrBookingNotPending
+		}
+
+		if booking.ExpiresAt.Before(time.Now()) {
+			return ErrBookingExpired
+		}
+
+		// Paid bookings are confirmed by the payment webhook only.
+		if booking.TotalPrice() > 0 {
+			return ErrPaymentRequired
+		}
+
+		err = s.Booking.UpdateStatus(ctx, booking.Status, model.StatusBookingConfirmed, model.BookingReasonConfirmedByUser,
+			bookID, eventID, userID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.Confirm error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrBookingNotPending
+			}
+			return err
+		}
+
+		b, err := s.Booking.GetForTG(ctx, bookID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.Confirm error: %v", err)
+			return err
+		}
+
+		err = enqueueNotifications(ctx, s, []model.NotificationInCreate{buildBookingConfirmedMessage(b)})
 		if err != nil {
 			zlog.Logger.Error().Msgf("service.BookingService.Confirm error: %v", err)
 			return err
@@ -106,29 +260,247 @@ func (bs *bookingService) GetByUserID(ctx context.Context, req model.BookingGetR
 		return nil, err
 	}
 
+	ids := make([]int, 0, len(bookingInRepo))
+	for _, b := range bookingInRepo {
+		ids = append(ids, b.ID)
+	}
+	seats, err := bs.storage.Seat.GetByBookings(ctx, ids)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.BookingService.GetByUserID error: %v", err)
+		return nil, err
+	}
+
 	bookingInResponse := make([]model.BookingInResponse, 0, len(bookingInRepo))
 
 	for _, b := range bookingInRepo {
-		bookingInResponse = append(bookingInResponse, model.BookingInResponse{
-			ID:               b.ID,
-			UserID:           b.UserID,
-			EventID:          b.EventID,
-			Status:           b.Status,
-			ExpiresAt:        b.ExpiresAt,
-			CreatedAt:        b.CreatedAt,
-			EventTitle:       b.EventTitle,
-			EventDescription: b.EventDescription,
-			EventDate:        b.EventDate,
-		})
+		res := toBookingResponse(b)
+		if len(seats[b.ID]) > 0 {
+			res.Seats = toSeatResponses(seats[b.ID])
+		}
+		bookingInResponse = append(bookingInResponse, res)
 	}
 
 	return bookingInResponse, nil
 }
 
+func bookingIDs(bookings []model.BookingGetForTG) []int {
+	ids := make([]int, 0, len(bookings))
+	for _, b := range bookings {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+func toBookingResponse(b model.BookingWithEventDetails) model.BookingInResponse {
+	return model.BookingInResponse{
+		ID:               b.ID,
+		UserID:           b.UserID,
+		EventID:          b.EventID,
+		Quantity:         b.Quantity,
+		Status:           b.Status,
+		StatusChangedAt:  b.StatusChangedAt,
+		StatusReason:     b.StatusReason,
+		ExpiresAt:        b.ExpiresAt,
+		CreatedAt:        b.CreatedAt,
+		TicketTypeID:     b.TicketTypeID,
+		TicketTypeName:   b.TicketTypeName,
+		UnitPrice:        b.UnitPrice,
+		Discount:         b.Discount,
+		TotalPrice:       b.TotalPrice(),
+		Currency:         b.Currency,
+		EventTitle:       b.EventTitle,
+		EventDescription: b.EventDescription,
+		EventDate:        b.EventDate,
+	}
+}
+
 func (bs *bookingService) GetCountUserBooking(ctx context.Context, userID int) (int, error) {
 	return bs.storage.Booking.GetCountUserBooking(ctx, userID)
 }
 
 func (bs *bookingService) CancelBook(ctx context.Context, bookID, eventID, userID int) error {
-	return bs.storage.Booking.UpdateStatus(ctx, model.StatusBookingCanceled, bookID, eventID, userID)
+	return bs.storage.WithTx(ctx, func(s *repository.Storage) error {
+		// Locked like in paymentSucceeded and the expiry job, so the refund
+		// and the promo release below see the status they change.
+		booking, err := s.Booking.GetByIDForUpdate(ctx, bookID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrBookingNotFound
+			}
+			return err
+		}
+
+		if booking.UserID != userID || booking.EventID != eventID {
+			return ErrBookingNotFound
+		}
+
+		if booking.Status != model.StatusBookingPending && booking.Status != model.StatusBookingConfirmed {
+			return ErrBookingNotActive
+		}
+
+		percent, _, err := cancellationTerms(ctx, s, booking)
+		if err != nil {
+			if !errors.Is(err, ErrCancellationClosed) {
+				zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			}
+			return err
+		}
+
+		err = s.Booking.UpdateStatus(ctx, booking.Status, model.StatusBookingCanceled, model.BookingReasonCanceledByUser,
+			bookID, eventID, userID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrBookingNotActive
+			}
+			return err
+		}
+
+		_, err = refundBooking(ctx, s, bs.providers, bookID, percent, model.RefundReasonCanceledByUser)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			return err
+		}
+
+		err = s.PromoCode.Release(ctx, []int{bookID})
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			return err
+		}
+
+		b, err := s.Booking.GetForTG(ctx, bookID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			return err
+		}
+
+		err = enqueueNotifications(ctx, s, []model.NotificationInCreate{buildBookingCanceledMessage(b)})
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			return err
+		}
+
+		err = promoteWaitlist(ctx, s, eventID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.BookingService.CancelBook error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetCancellationQuote shows what CancelBook would refund right now, so the
+// user sees the terms before cancelling.
+func (bs *bookingService) GetCancellationQuote(ctx context.Context, bookID, eventID, userID int) (model.CancellationQuote, error) {
+	booking, err := bs.storage.Booking.GetByID(ctx, bookID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.BookingService.GetCancellationQuote error: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.CancellationQuote{}, ErrBookingNotFound
+		}
+		return model.CancellationQuote{}, err
+	}
+
+	if booking.UserID != userID || booking.EventID != eventID {
+		return model.CancellationQuote{}, ErrBookingNotFound
+	}
+
+	if booking.Status != model.StatusBookingPending && booking.Status != model.StatusBookingConfirmed {
+		return model.CancellationQuote{}, ErrBookingNotActive
+	}
+
+	quote := model.CancellationQuote{
+		BookingID: bookID,
+		Currency:  booking.Currency,
+	}
+
+	percent, policy, err := cancellationTerms(ctx, bs.storage, booking)
+	quote.Policy = formatCancellationPolicy(policy)
+	if errors.Is(err, ErrCancellationClosed) {
+		return quote, nil
+	}
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.BookingService.GetCancellationQuote error: %v", err)
+		return model.CancellationQuote{}, err
+	}
+	quote.Allowed = true
+	quote.RefundPercent = percent
+
+	payment, err := bs.storage.Payment.GetSucceededByBooking(ctx, bookID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		zlog.Logger.Error().Msgf("service.BookingService.GetCancellationQuote error: %v", err)
+		return model.CancellationQuote{}, err
+	}
+	if err == nil {
+		quote.RefundAmount = payment.Amount * int64(percent) / 100
+		quote.Currency = payment.Currency
+	}
+	return quote, nil
+}
+
+// cancellationTerms evaluates the event policy for the booking. Pending
+// bookings hold nothing paid yet and can always be cancelled.
+func cancellationTerms(ctx context.Context, s *repository.Storage, booking model.BookingInRepo) (int, model.CancellationPolicyInRepo, error) {
+	policy, err := s.CancellationPolicy.GetByEvent(ctx, booking.EventID)
+	if err != nil {
+		return 0, model.CancellationPolicyInRepo{}, err
+	}
+
+	if booking.Status != model.StatusBookingConfirmed {
+		return 100, policy, nil
+	}
+
+	event, err := s.Event.GetByID(ctx, booking.EventID)
+	if err != nil {
+		return 0, policy, err
+	}
+
+	percent, err := cancellationRefund(policy, event.EventDate, time.Now())
+	return percent, policy, err
+}
+
+func buildBookingCreatedMessage(b model.BookingGetForTG, status string, expiresAt time.Time) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Бронирование %d создано.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
+	if status == model.StatusBookingPending {
+		builder.WriteString(fmt.Sprintf("Подтвердите или оплатите бронь до %s\n", expiresAt.Format(time.DateTime)))
+	}
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeBookingCreated,
+		Subject:   fmt.Sprintf("Бронирование %d создано", b.ID),
+		Text:      builder.String(),
+	}
+}
+
+func buildBookingConfirmedMessage(b model.BookingGetForTG) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Бронирование %d подтверждено.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeBookingConfirmed,
+		Subject:   fmt.Sprintf("Бронирование %d подтверждено", b.ID),
+		Text:      builder.String(),
+	}
+}
+
+func buildBookingCanceledMessage(b model.BookingGetForTG) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Бронирование %d отменено по вашему запросу.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeBookingCanceled,
+		Subject:   fmt.Sprintf("Бронирование %d отменено", b.ID),
+		Text:      builder.String(),
+	}
 }
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+// setupTestStorage applies the migrations to a throwaway schema of the
+// database from TEST_POSTGRES_DSN (key=value form, e.g. "host=localhost
+// user=postgres password=pass dbname=postgres sslmode=disable"). Tests that
+// need it are skipped when the variable is not set.
+func setupTestStorage(t *testing.T) (*repository.Storage, *sql.DB) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	zlog.Init()
+
+	schema := fmt.Sprintf("eventbooker_test_%d", time.Now().UnixNano())
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		_ = admin.Close()
+	})
+
+	pg, err := dbpg.New(dsn+" search_path="+schema, nil, &dbpg.Options{
+		MaxOpenConns: 20,
+		MaxIdleConns: 20,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pg.Master.Close() })
+
+	migrations, err := filepath.Glob("../../migrations/*.up.sql")
+	require.NoError(t, err)
+	sort.Strings(migrations)
+	for _, m := range migrations {
+		query, err := os.ReadFile(m)
+		require.NoError(t, err)
+		_, err = pg.Master.Exec(string(query))
+		require.NoError(t, err, m)
+	}
+
+	return repository.NewStorage(pg), pg.Master
+}
+
+// insertTicketType adds a tier to an event inserted with raw SQL.
+func insertTicketType(t *testing.T, db *sql.DB, eventID int, name string, capacity int, price int64) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(`INSERT INTO ticket_types (event_id, name, capacity, price, currency, created_at)
+		VALUES ($1, $2, $3, $4, 'RUB', now()) RETURNING ticket_type_id`, eventID, name, capacity, price).Scan(&id)
+	require.NoError(t, err)
+	return id
+}
+
+func TestBook_ConcurrentRequestsNeverOverbook(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	const (
+		totalPlace = 25
+		requests   = 300
+	)
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('load@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Load test', '', $1, 'published', $2, $3, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), totalPlace, int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, totalPlace, 0)
+
+	bs := NewBookingService(storage, nil)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		booked   int
+		rejected int
+		failures []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		quantity := 1 + i%3
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: quantity})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				booked += quantity
+			case errors.Is(err, ErrNoSeatsAvailable):
+				rejected++
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Empty(t, failures)
+
+	occupied, err := storage.Booking.GetOccupiedPlace(ctx, eventID)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, occupied, totalPlace, "occupancy must never exceed total_place")
+	assert.Equal(t, booked, occupied, "every successful Book must be reflected in occupancy")
+	assert.Greater(t, rejected, 0, "the event must have sold out")
+}
+
+func TestExpireBooking_KeepsHistoryAndReportsOnce(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at)
+		VALUES ('expire@test.local', 'x', 'user', 777, now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Expire test', '', $1, 'published', 10, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	ticketTypeID := insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 0)
+
+	bookID, err := storage.Booking.Create(ctx, model.BookingInCreate{
+		UserID:       userID,
+		EventID:      eventID,
+		Quantity:     2,
+		TicketTypeID: ticketTypeID,
+		ExpiresAt:    time.Now().Add(-time.Minute),
+		Currency:     model.DefaultCurrency,
+	}, model.StatusBookingPending)
+	require.NoError(t, err)
+
+	expired, err := storage.Booking.ExpireBooking(ctx)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, bookID, expired[0].ID)
+	assert.Equal(t, int64(777), expired[0].TgChatID)
+
+	again, err := storage.Booking.ExpireBooking(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, again, "an expired booking must be reported only once")
+
+	b, err := storage.Booking.GetByID(ctx, bookID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusBookingExpired, b.Status)
+	assert.Equal(t, model.BookingReasonReservationPassed, b.StatusReason)
+	assert.NotNil(t, b.StatusChangedAt)
+
+	occupied, err := storage.Booking.GetOccupiedPlace(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, occupied)
+}
+
+func TestBook_NotifiesBookingCreated(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at, email_verified_at)
+		VALUES ('created@test.local', 'x', 'user', 555, now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Created test', '', $1, 'published', 10, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 0)
+
+	err = NewBookingService(storage, nil).Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2})
+	require.NoError(t, err)
+
+	var channel, recipient, text string
+	err = db.QueryRow(`SELECT channel, recipient, message FROM notification_outbox WHERE event_type=$1`,
+		model.NotificationTypeBookingCreated).Scan(&channel, &recipient, &text)
+	require.NoError(t, err)
+	assert.Equal(t, model.NotificationChannelTelegram, channel)
+	assert.Equal(t, "555", recipient)
+	assert.Contains(t, text, "Количество мест: 2")
+	assert.Contains(t, text, "Подтвердите или оплатите бронь до")
+}
+
+func TestBook_ExpiredHoldFreesCapacity(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('hold@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Hold test', '', $1, 'published', 2, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	ticketTypeID := insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 2, 0)
+
+	bs := NewBookingService(storage, nil)
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2}))
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID})
+	assert.ErrorIs(t, err, ErrNoSeatsAvailable)
+
+	_, err = db.Exec(`UPDATE booking SET expires_at = now() - interval '1 second' WHERE event_id=$1`, eventID)
+	require.NoError(t, err)
+
+	occupied, err := storage.Booking.GetOccupiedPlace(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, occupied, "a pending booking past expires_at holds nothing")
+	tier, err := storage.TicketType.GetByID(ctx, ticketTypeID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, tier.Occupied)
+
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2}),
+		"seats of an expired hold can be booked before the expiry job runs")
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"EventBooker/internal/model"
+)
+
+// parseCancellationPolicy validates the policy. Empty durations mean zero,
+// so an empty policy allows free cancellation until the event.
+func parseCancellationPolicy(p model.CancellationPolicy) (model.CancellationPolicyInRepo, error) {
+	var res model.CancellationPolicyInRepo
+
+	var err error
+	res.FreeCancelBefore, err = parsePolicyDuration(p.FreeCancelBefore)
+	if err != nil {
+		return model.CancellationPolicyInRepo{}, err
+	}
+
+	res.NoCancelWithin, err = parsePolicyDuration(p.NoCancelWithin)
+	if err != nil {
+		return model.CancellationPolicyInRepo{}, err
+	}
+
+	if res.FreeCancelBefore > 0 && res.FreeCancelBefore < res.NoCancelWithin {
+		return model.CancellationPolicyInRepo{}, ErrInvalidCancellationPolicy
+	}
+
+	// Partial refunds only make sense after a free window ends.
+	if len(p.PartialRefunds) > 0 && res.FreeCancelBefore == 0 {
+		return model.CancellationPolicyInRepo{}, ErrInvalidCancellationPolicy
+	}
+
+	seen := make(map[time.Duration]bool, len(p.PartialRefunds))
+	for _, r := range p.PartialRefunds {
+		before, err := parsePolicyDuration(r.Before)
+		if err != nil || before <= 0 || seen[before] {
+			return model.CancellationPolicyInRepo{}, ErrInvalidCancellationPolicy
+		}
+		if before >= res.FreeCancelBefore || before < res.NoCancelWithin {
+			return model.CancellationPolicyInRepo{}, ErrInvalidCancellationPolicy
+		}
+		if r.RefundPercent < 0 || r.RefundPercent > 100 {
+			return model.CancellationPolicyInRepo{}, ErrInvalidCancellationPolicy
+		}
+		seen[before] = true
+		res.PartialRefunds = append(res.PartialRefunds, model.RefundRuleInRepo{
+			Before:        before,
+			RefundPercent: r.RefundPercent,
+		})
+	}
+
+	sort.Slice(res.PartialRefunds, func(i, j int) bool {
+		return res.PartialRefunds[i].Before > res.PartialRefunds[j].Before
+	})
+	return res, nil
+}
+
+func parsePolicyDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, ErrInvalidCancellationPolicy
+	}
+	return d, nil
+}
+
+func formatCancellationPolicy(p model.CancellationPolicyInRepo) model.CancellationPolicy {
+	res := model.CancellationPolicy{
+		FreeCancelBefore: p.FreeCancelBefore.String(),
+		NoCancelWithin:   p.NoCancelWithin.String(),
+		PartialRefunds:   make([]model.RefundRule, 0, len(p.PartialRefunds)),
+	}
+	for _, r := range p.PartialRefunds {
+		res.PartialRefunds = append(res.PartialRefunds, model.RefundRule{
+			Before:        r.Before.String(),
+			RefundPercent: r.RefundPercent,
+		})
+	}
+	return res
+}
+
+// cancellationRefund returns the percent of the payment refunded when a
+// confirmed booking is cancelled at now. Rules are expected sorted by
+// Before descending, as parseCancellationPolicy and the repository return
+// them.
+func cancellationRefund(p model.CancellationPolicyInRepo, eventDate, now time.Time) (int, error) {
+	left := eventDate.Sub(now)
+	if left < p.NoCancelWithin {
+		return 0, ErrCancellationClosed
+	}
+
+	if p.FreeCancelBefore == 0 || left >= p.FreeCancelBefore {
+		return 100, nil
+	}
+
+	for _, r := range p.PartialRefunds {
+		if left >= r.Before {
+			return r.RefundPercent, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestParseCancellationPolicy(t *testing.T) {
+	rules := func(r ...model.RefundRule) []model.RefundRule { return r }
+
+	tests := []struct {
+		name   string
+		policy model.CancellationPolicy
+		err    error
+	}{
+		{"empty", model.CancellationPolicy{}, nil},
+		{"full", model.CancellationPolicy{FreeCancelBefore: "72h", NoCancelWithin: "2h",
+			PartialRefunds: rules(model.RefundRule{Before: "24h", RefundPercent: 50})}, nil},
+		{"bad duration", model.CancellationPolicy{FreeCancelBefore: "soon"}, ErrInvalidCancellationPolicy},
+		{"negative duration", model.CancellationPolicy{NoCancelWithin: "-1h"}, ErrInvalidCancellationPolicy},
+		{"free window inside cutoff", model.CancellationPolicy{FreeCancelBefore: "1h", NoCancelWithin: "2h"},
+			ErrInvalidCancellationPolicy},
+		{"rules without free window", model.CancellationPolicy{
+			PartialRefunds: rules(model.RefundRule{Before: "24h", RefundPercent: 50})}, ErrInvalidCancellationPolicy},
+		{"rule before free window", model.CancellationPolicy{FreeCancelBefore: "24h",
+			PartialRefunds: rules(model.RefundRule{Before: "48h", RefundPercent: 50})}, ErrInvalidCancellationPolicy},
+		{"rule inside cutoff", model.CancellationPolicy{FreeCancelBefore: "24h", NoCancelWithin: "12h",
+			PartialRefunds: rules(model.RefundRule{Before: "6h", RefundPercent: 50})}, ErrInvalidCancellationPolicy},
+		{"duplicate rule", model.CancellationPolicy{FreeCancelBefore: "72h", PartialRefunds: rules(
+			model.RefundRule{Before: "24h", RefundPercent: 50}, model.RefundRule{Before: "24h", RefundPercent: 20})},
+			ErrInvalidCancellationPolicy},
+		{"percent over 100", model.CancellationPolicy{FreeCancelBefore: "72h",
+			PartialRefunds: rules(model.RefundRule{Before: "24h", RefundPercent: 150})}, ErrInvalidCancellationPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCancellationPolicy(tt.policy)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	p, err := parseCancellationPolicy(model.CancellationPolicy{FreeCancelBefore: "72h", PartialRefunds: rules(
+		model.RefundRule{Before: "24h", RefundPercent: 20}, model.RefundRule{Before: "48h", RefundPercent: 50})})
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, p.PartialRefunds[0].Before, "rules are sorted from the earliest")
+	assert.Equal(t, "72h0m0s", formatCancellationPolicy(p).FreeCancelBefore)
+}
+
+func TestCancellationRefund(t *testing.T) {
+	policy := model.CancellationPolicyInRepo{
+		FreeCancelBefore: 72 * time.Hour,
+		PartialRefunds: []model.RefundRuleInRepo{
+			{Before: 48 * time.Hour, RefundPercent: 50},
+			{Before: 24 * time.Hour, RefundPercent: 20},
+		},
+		NoCancelWithin: 2 * time.Hour,
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		policy  model.CancellationPolicyInRepo
+		left    time.Duration
+		percent int
+		err     error
+	}{
+		{"free window", policy, 100 * time.Hour, 100, nil},
+		{"free window edge", policy, 72 * time.Hour, 100, nil},
+		{"first rule", policy, 60 * time.Hour, 50, nil},
+		{"second rule", policy, 30 * time.Hour, 20, nil},
+		{"no refund", policy, 10 * time.Hour, 0, nil},
+		{"closed", policy, time.Hour, 0, ErrCancellationClosed},
+		{"no policy", model.CancellationPolicyInRepo{}, time.Minute, 100, nil},
+		{"event passed", model.CancellationPolicyInRepo{}, -time.Minute, 0, ErrCancellationClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, err := cancellationRefund(tt.policy, now.Add(tt.left), now)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.percent, percent)
+		})
+	}
+}
+
+func TestCancelBook_RefundsByPolicy(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('refund@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Refund', '', $1, 'published', 10, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(30*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 100000)
+
+	policy, err := parseCancellationPolicy(model.CancellationPolicy{
+		FreeCancelBefore: "72h",
+		PartialRefunds:   []model.RefundRule{{Before: "24h", RefundPercent: 50}},
+		NoCancelWithin:   "2h",
+	})
+	require.NoError(t, err)
+	require.NoError(t, storage.CancellationPolicy.SetForEvent(ctx, eventID, policy))
+
+	ps, fp := newFakePaymentService(storage)
+	bs := NewBookingService(storage, ps.providers)
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2}))
+
+	var bookID int
+	err = db.QueryRow(`SELECT booking_id FROM booking WHERE user_id=$1`, userID).Scan(&bookID)
+	require.NoError(t, err)
+
+	payment, err := ps.Pay(ctx, bookID, eventID, userID)
+	require.NoError(t, err)
+
+	var providerID string
+	err = db.QueryRow(`SELECT provider_payment_id FROM payments WHERE payment_id=$1`, payment.ID).Scan(&providerID)
+	require.NoError(t, err)
+	header, raw := fakeWebhookRequest(fp, `{"payment_id":"`+providerID+`","status":"succeeded"}`)
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw))
+
+	quote, err := bs.GetCancellationQuote(ctx, bookID, eventID, userID)
+	require.NoError(t, err)
+	assert.True(t, quote.Allowed)
+	assert.Equal(t, 50, quote.RefundPercent)
+	assert.Equal(t, int64(100000), quote.RefundAmount)
+
+	// Parallel cancellations of one booking refund and notify once.
+	var (
+		wg       sync.WaitGroup
+		canceled atomic.Int32
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := bs.CancelBook(ctx, bookID, eventID, userID)
+			if err == nil {
+				canceled.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, ErrBookingNotActive)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), canceled.Load())
+
+	var notices int
+	err = db.QueryRow(`SELECT COUNT(*) FROM notification_outbox WHERE user_id=$1 AND event_type=$2`,
+		userID, model.NotificationTypeBookingCanceled).Scan(&notices)
+	require.NoError(t, err)
+	assert.Equal(t, 1, notices)
+
+	var amount int64
+	var reason string
+	err = db.QueryRow(`SELECT amount, reason FROM refunds WHERE booking_id=$1`, bookID).Scan(&amount, &reason)
+	require.NoError(t, err, "exactly one refund row")
+	assert.Equal(t, quote.RefundAmount, amount)
+	assert.Equal(t, model.RefundReasonCanceledByUser, reason)
+
+	var status string
+	err = db.QueryRow(`SELECT status FROM payments WHERE payment_id=$1`, payment.ID).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusPaymentPartiallyRefunded, status)
+
+	_, err = bs.GetCancellationQuote(ctx, bookID, eventID, userID)
+	assert.ErrorIs(t, err, ErrBookingNotActive)
+}
@@ -8,13 +8,111 @@ var (
 	ErrEmptyTitle         = errors.New("event title cannot be empty")
 	ErrInvalidTotalPlace  = errors.New("total places must be positive")
 	ErrInvalidEventDate   = errors.New("invalid event date")
+	ErrEventCanceled      = errors.New("event is canceled")
+	ErrEventHasBookings   = errors.New("event has active bookings, cancel it instead")
+	ErrEventHasPayments   = errors.New("event has payment history, cancel it instead")
+	ErrEventFinished      = errors.New("event is finished")
+	ErrEventNotOnSale     = errors.New("event is not open for booking")
+	ErrEventForbidden     = errors.New("event belongs to another organizer")
+
+	ErrInvalidEventStatus      = errors.New("invalid event status")
+	ErrInvalidStatusTransition = errors.New("event status transition not allowed")
+
+	ErrTotalPlaceBelowOccupied = errors.New("total places cannot be less than occupied places")
+	ErrInvalidReminder         = errors.New("reminders must be positive durations like '24h' or '30m'")
+
+	ErrTicketTypeNotFound    = errors.New("ticket type not found")
+	ErrNoTicketTypes         = errors.New("event needs at least one ticket type")
+	ErrTicketTypeRequired    = errors.New("event has several ticket types, ticket_type_id is required")
+	ErrInvalidTicketType     = errors.New("ticket type name must be 1 to 50 characters")
+	ErrDuplicateTicketType   = errors.New("ticket type names must be unique within the event")
+	ErrInvalidPrice          = errors.New("price cannot be negative")
+	ErrInvalidCurrency       = errors.New("currency must be a three-letter ISO code")
+	ErrTicketTypeHasBookings = errors.New("ticket type has bookings and cannot be removed")
+	ErrTotalPlaceWithTiers   = errors.New("event has several ticket types, change their capacity instead")
 
 	ErrBookingNotFound    = errors.New("booking not found")
 	ErrBookingNotRequired = errors.New("booking not required")
 	ErrNoSeatsAvailable   = errors.New("no seats available")
+	ErrInvalidQuantity    = errors.New("quantity must be positive")
+	ErrBookingNotPending  = errors.New("booking is not pending")
+	ErrBookingExpired     = errors.New("booking has expired")
+	ErrBookingNotActive   = errors.New("booking is already cancelled or expired")
+
+	ErrPaymentRequired         = errors.New("booking has to be paid, confirmation comes from the payment provider")
+	ErrPaymentNotRequired      = errors.New("booking is free, confirm it instead")
+	ErrPaymentUnavailable      = errors.New("payments are not configured")
+	ErrPaymentProviderNotFound = errors.New("unknown payment provider")
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrPaymentExists           = errors.New("booking already has another payment")
+	ErrInvalidWebhook          = errors.New("webhook signature or payload is invalid")
+
+	ErrInvalidCancellationPolicy = errors.New("cancellation policy is invalid: refund rules must lie between no_cancel_within and free_cancel_before")
+	ErrCancellationClosed        = errors.New("booking can no longer be cancelled, the event is too close")
+
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrInvalidPromoCode       = errors.New("promo code must be 3 to 32 characters: letters, digits, '-' or '_'")
+	ErrPromoCodeExists        = errors.New("promo code already exists")
+	ErrInvalidDiscount        = errors.New("discount must be a percent from 1 to 100 or a positive fixed amount with currency")
+	ErrInvalidPromoLimits     = errors.New("usage limits must be positive")
+	ErrInvalidPromoPeriod     = errors.New("valid_until must be after valid_from")
+	ErrPromoCodeInUse         = errors.New("promo code has been used and cannot be deleted")
+	ErrPromoLimitBelowUsed    = errors.New("max_uses cannot be less than the number of uses")
+	ErrPromoCodeInactive      = errors.New("promo code is not valid at this time")
+	ErrPromoCodeExhausted     = errors.New("promo code usage limit reached")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to this ticket")
+
+	ErrVenueNotFound    = errors.New("venue not found")
+	ErrInvalidVenue     = errors.New("venue needs a name of at most 100 characters and at least one seat; sections, rows and seat numbers must be valid")
+	ErrDuplicateSeat    = errors.New("section names and rows within a section must be unique")
+	ErrVenueInUse       = errors.New("venue is used by events, its seat map cannot change")
+	ErrInvalidSeating   = errors.New("every venue section must belong to exactly one ticket type")
+	ErrSeatedCapacity   = errors.New("capacity of an event with assigned seating comes from the seat map")
+	ErrSeatsRequired    = errors.New("event has assigned seating, seat_ids are required")
+	ErrSeatsNotAssigned = errors.New("event has no assigned seating")
+	ErrSeatNotFound     = errors.New("seat not found")
+	ErrSeatUnavailable  = errors.New("seat is already held or sold")
+	ErrSeatsMixedTiers  = errors.New("seats must all belong to one ticket type, book other tiers separately")
+	ErrWaitlistSeated   = errors.New("events with assigned seating have no waitlist")
+
+	ErrSeatsAvailable          = errors.New("seats are available, book directly")
+	ErrAlreadyInWaitlist       = errors.New("already in waitlist")
+	ErrQuantityExceedsCapacity = errors.New("quantity exceeds event capacity")
+
+	ErrInvalidNotificationStatus  = errors.New("invalid notification status")
+	ErrNotificationNotFailed      = errors.New("failed notification not found")
+	ErrInvalidNotificationType    = errors.New("invalid notification event type")
+	ErrInvalidNotificationChannel = errors.New("invalid notification channel")
+
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrExpiredToken         = errors.New("token has expired")
+	ErrSessionRevoked       = errors.New("session has been revoked")
+	ErrInvalidRefreshToken  = errors.New("refresh token is invalid or expired")
+	ErrInvalidResetToken    = errors.New("reset token is invalid or expired")
+	ErrWeakPassword         = errors.New("password must be at least 6 characters")
+	ErrWrongPassword        = errors.New("old password is incorrect")
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrInvalidVerifyToken   = errors.New("verification token is invalid or expired")
+	ErrEmailNotVerified     = errors.New("email is not verified")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrLoginLocked          = errors.New("too many failed login attempts")
+	ErrInvalidRole          = errors.New("role must be one of user, organizer, admin")
+	ErrOwnRole              = errors.New("admins cannot change their own role")
+	ErrPasswordMismatch     = errors.New("password is incorrect")
+	ErrEmailTaken           = errors.New("email is already registered")
+	ErrAdminAccountDelete   = errors.New("admins cannot delete their account, change the role first")
+	ErrInvalidName          = errors.New("name must be at most 100 characters")
+	ErrInvalidPhone         = errors.New("invalid phone number")
+	ErrInvalidLocale        = errors.New("locale must look like 'ru' or 'en-US'")
+
+	ErrInvalidPermission       = errors.New("unknown permission")
+	ErrAdminPermissionRequired = errors.New("the admin role must keep users:manage")
+
+	ErrInvalidLinkCode = errors.New("link code is invalid or expired")
 
-	ErrUserNotFound = errors.New("user not found")
-	ErrUnauthorized = errors.New("unauthorized")
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidExportFormat = errors.New("export format must be json or zip")
+	ErrExportNotFound      = errors.New("export not found")
+	ErrExportNotReady      = errors.New("export is not ready")
 )
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wb-go/wbf/zlog"
@@ -13,51 +15,160 @@ import (
 )
 
 type EventService interface {
-	CreateEvent(ctx context.Context, e model.EventInCreate) error
+	CreateEvent(ctx context.Context, actor model.Actor, e model.EventInCreate) error
 	GetByID(ctx context.Context, id int) (model.EventInResponse, error)
 	GetListEvents(ctx context.Context, req model.EventGetRequest) ([]model.EventInResponse, error)
-	GetCountEvent(ctx context.Context) (int, error)
+	GetCountEvent(ctx context.Context, includeDrafts bool, organizerID int) (int, error)
+	ChangeStatus(ctx context.Context, actor model.Actor, id int, status string) error
+	UpdateEvent(ctx context.Context, actor model.Actor, id int, e model.EventInUpdate) error
+	CancelEvent(ctx context.Context, actor model.Actor, id int) error
+	DeleteEvent(ctx context.Context, actor model.Actor, id int) error
+	GetSeatMap(ctx context.Context, id int) (model.SeatMapInResponse, error)
 }
 
 type eventService struct {
-	storage *repository.Storage
+	storage   *repository.Storage
+	providers map[string]PaymentProvider
 }
 
-func NewEventService(s *repository.Storage) EventService {
-	return &eventService{storage: s}
+func NewEventService(s *repository.Storage, providers map[string]PaymentProvider) EventService {
+	return &eventService{storage: s, providers: providers}
 }
 
-func (es *eventService) CreateEvent(ctx context.Context, e model.EventInCreate) error {
+// CreateEvent stores the event as owned by the actor.
+func (es *eventService) CreateEvent(ctx context.Context, actor model.Actor, e model.EventInCreate) error {
 
 	if err := validateCreateEvent(e); err != nil {
 		return err
 	}
 
-	err := es.storage.Event.Create(ctx, e)
-	if err != nil {
-		zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+	if e.TicketTypes == nil {
+		e.TicketTypes = []model.TicketTypeInput{{
+			Name:     model.DefaultTicketTypeName,
+			Capacity: e.TotalPlace,
+			Currency: model.DefaultCurrency,
+		}}
+	}
+
+	// With a venue the capacity of every tier is the number of its seats.
+	var seating [][]int
+	if e.VenueID != nil {
+		seats, err := es.storage.Venue.GetSeats(ctx, *e.VenueID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+			return err
+		}
+		if len(seats) == 0 {
+			return ErrVenueNotFound
+		}
+		seating, err = assignSeats(e.TicketTypes, seats)
+		if err != nil {
+			return err
+		}
+	} else if hasSections(e.TicketTypes) {
+		return ErrInvalidSeating
+	}
 
+	if err := normalizeTicketTypes(e.TicketTypes); err != nil {
 		return err
 	}
-	return nil
+	e.TotalPlace = sumCapacity(e.TicketTypes)
+
+	reminders := model.DefaultEventReminders
+	if e.Reminders != nil {
+		var err error
+		reminders, err = parseReminders(e.Reminders)
+		if err != nil {
+			return err
+		}
+	}
+
+	var policy model.CancellationPolicyInRepo
+	if e.CancellationPolicy != nil {
+		var err error
+		policy, err = parseCancellationPolicy(*e.CancellationPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	return es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		id, err := s.Event.Create(ctx, e, actor.UserID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+
+			return err
+		}
+
+		for i, t := range e.TicketTypes {
+			ticketTypeID, err := s.TicketType.Create(ctx, id, t)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+				return err
+			}
+
+			if seating != nil {
+				err = s.Seat.AssignToEvent(ctx, id, ticketTypeID, seating[i])
+				if err != nil {
+					zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+					return err
+				}
+			}
+		}
+
+		err = s.Reminder.SetForEvent(ctx, id, reminders)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+			return err
+		}
+
+		if e.CancellationPolicy != nil {
+			err = s.CancellationPolicy.SetForEvent(ctx, id, policy)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (es eventService) GetByID(ctx context.Context, id int) (model.EventInResponse, error) {
 	e, err := es.storage.Event.GetByID(ctx, id)
 	if err != nil {
 		zlog.Logger.Error().Msgf("service.EventService.GetByID error: %v", err)
-		if errors.Is(err, sql.ErrNoRows) {
-			return model.EventInResponse{}, ErrEventNotFound
-		}
 		return model.EventInResponse{}, err
 	}
 
+	if e.ID == 0 || e.Status == model.EventStatusDraft {
+		return model.EventInResponse{}, ErrEventNotFound
+	}
+
 	occupiedPlace, err := es.storage.Booking.GetOccupiedPlace(ctx, id)
 	if err != nil {
-		zlog.Logger.Error().Msgf("service.EventService.CreateEvent error: %v", err)
+		zlog.Logger.Error().Msgf("service.EventService.GetByID error: %v", err)
+		return model.EventInResponse{}, err
+	}
+
+	tiers, err := es.storage.TicketType.GetByEvent(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetByID error: %v", err)
+		return model.EventInResponse{}, err
+	}
+
+	reminders, err := es.storage.Reminder.GetByEvent(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetByID error: %v", err)
 		return model.EventInResponse{}, err
 	}
 
+	policy, err := es.storage.CancellationPolicy.GetByEvent(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetByID error: %v", err)
+		return model.EventInResponse{}, err
+	}
+	cancellationPolicy := formatCancellationPolicy(policy)
+
 	return model.EventInResponse{
 		Title:              e.Title,
 		Description:        e.Description,
@@ -67,6 +178,11 @@ func (es eventService) GetByID(ctx context.Context, id int) (model.EventInRespon
 		EventStatus:        e.Status,
 		BookingConfimation: e.BookingConfimation,
 		ReservationPeriod:  e.ReservationPeriod.String(),
+		Reminders:          formatReminders(reminders),
+		OrganizerID:        e.OrganizerID,
+		VenueID:            e.VenueID,
+		TicketTypes:        toTicketTypeResponses(tiers),
+		CancellationPolicy: &cancellationPolicy,
 	}, nil
 }
 
@@ -77,6 +193,16 @@ func (es eventService) GetListEvents(ctx context.Context, req model.EventGetRequ
 		return nil, err
 	}
 
+	eventIDs := make([]int, 0, len(eventsInRepo))
+	for _, e := range eventsInRepo {
+		eventIDs = append(eventIDs, e.ID)
+	}
+	tiersByEvent, err := es.storage.TicketType.GetByEvents(ctx, eventIDs)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetListEvents error: %v", err)
+		return nil, err
+	}
+
 	eventsInResponse := make([]model.EventInResponse, 0, len(eventsInRepo))
 
 	for _, e := range eventsInRepo {
@@ -95,14 +221,452 @@ func (es eventService) GetListEvents(ctx context.Context, req model.EventGetRequ
 			EventStatus:        e.Status,
 			ReservationPeriod:  e.ReservationPeriod.String(),
 			BookingConfimation: e.BookingConfimation,
+			OrganizerID:        e.OrganizerID,
+			VenueID:            e.VenueID,
 			CreatedAt:          e.CreatedAt,
+			TicketTypes:        toTicketTypeResponses(tiersByEvent[e.ID]),
 		})
 	}
 	return eventsInResponse, nil
 }
 
-func (es eventService) GetCountEvent(ctx context.Context) (int, error) {
-	return es.storage.Event.GetCountEvents(ctx)
+func (es eventService) GetCountEvent(ctx context.Context, includeDrafts bool, organizerID int) (int, error) {
+	return es.storage.Event.GetCountEvents(ctx, includeDrafts, organizerID)
+}
+
+// eventStatusTransitions lists the states every event state may move to.
+var eventStatusTransitions = map[string][]string{
+	model.EventStatusDraft:       {model.EventStatusPublished, model.EventSratusCanceled},
+	model.EventStatusPublished:   {model.EventStatusSalesClosed, model.EventStatusFinished, model.EventSratusCanceled},
+	model.EventStatusSalesClosed: {model.EventStatusPublished, model.EventStatusFinished, model.EventSratusCanceled},
+	model.EventStatusFinished:    {},
+	model.EventSratusCanceled:    {},
+}
+
+func checkEventTransition(from, to string) error {
+	if from == model.EventSratusCanceled {
+		return ErrEventCanceled
+	}
+	for _, allowed := range eventStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+}
+
+func (es eventService) ChangeStatus(ctx context.Context, actor model.Actor, id int, status string) error {
+	if _, ok := eventStatusTransitions[status]; !ok {
+		return ErrInvalidEventStatus
+	}
+
+	// Cancellation also releases bookings and notifies their holders.
+	if status == model.EventSratusCanceled {
+		return es.CancelEvent(ctx, actor, id)
+	}
+
+	return es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		event, err := s.Event.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.ChangeStatus error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if err := checkEventOwner(actor, event); err != nil {
+			return err
+		}
+
+		if err := checkEventTransition(event.Status, status); err != nil {
+			return err
+		}
+
+		if status == model.EventStatusPublished && event.EventDate.Before(time.Now()) {
+			return ErrEventAlreadyPassed
+		}
+
+		err = s.Event.UpdateStatus(ctx, id, status)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.ChangeStatus error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (es eventService) UpdateEvent(ctx context.Context, actor model.Actor, id int, e model.EventInUpdate) error {
+	return es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		event, err := s.Event.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if err := checkEventOwner(actor, event); err != nil {
+			return err
+		}
+
+		if event.Status == model.EventSratusCanceled {
+			return ErrEventCanceled
+		}
+
+		if event.Status == model.EventStatusFinished {
+			return ErrEventFinished
+		}
+
+		occupiedPlace, err := s.Booking.GetOccupiedPlace(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+			return err
+		}
+
+		oldTotalPlace := event.TotalPlace
+		oldEventDate := event.EventDate
+		if err := applyEventUpdate(&event, e); err != nil {
+			return err
+		}
+
+		// Seats cannot move between tiers after the sale started, only the
+		// tier names and prices of a seated event can change.
+		if event.VenueID != nil && e.TotalPlace != nil {
+			return ErrSeatedCapacity
+		}
+
+		tiersChanged := false
+		if e.TicketTypes != nil {
+			tiers := *e.TicketTypes
+			if event.VenueID != nil {
+				err = keepSeatedCapacity(ctx, s, id, tiers)
+				if err != nil {
+					return err
+				}
+			} else if hasSections(tiers) {
+				return ErrInvalidSeating
+			}
+			if err := normalizeTicketTypes(tiers); err != nil {
+				return err
+			}
+			event.TotalPlace, err = syncTicketTypes(ctx, s, id, tiers)
+			if err != nil {
+				return err
+			}
+			tiersChanged = true
+		} else if e.TotalPlace != nil && event.TotalPlace != oldTotalPlace {
+			err = resizeSingleTicketType(ctx, s, id, event.TotalPlace)
+			if err != nil {
+				return err
+			}
+		}
+
+		if event.TotalPlace < occupiedPlace {
+			return ErrTotalPlaceBelowOccupied
+		}
+
+		err = s.Event.Update(ctx, event)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+			return err
+		}
+
+		if e.Reminders != nil {
+			reminders, err := parseReminders(*e.Reminders)
+			if err != nil {
+				return err
+			}
+
+			err = s.Reminder.SetForEvent(ctx, id, reminders)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+				return err
+			}
+		}
+
+		if e.CancellationPolicy != nil {
+			policy, err := parseCancellationPolicy(*e.CancellationPolicy)
+			if err != nil {
+				return err
+			}
+
+			err = s.CancellationPolicy.SetForEvent(ctx, id, policy)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+				return err
+			}
+		}
+
+		if !event.EventDate.Equal(oldEventDate) {
+			err = s.Reminder.ResetDeliveries(ctx, id)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+				return err
+			}
+		}
+
+		if tiersChanged || event.TotalPlace > oldTotalPlace {
+			err = promoteWaitlist(ctx, s, id)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.UpdateEvent error: %v", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (es eventService) CancelEvent(ctx context.Context, actor model.Actor, id int) error {
+	return es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		event, err := s.Event.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if err := checkEventOwner(actor, event); err != nil {
+			return err
+		}
+
+		if err := checkEventTransition(event.Status, model.EventSratusCanceled); err != nil {
+			return err
+		}
+
+		err = s.Event.UpdateStatus(ctx, id, model.EventSratusCanceled)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			return err
+		}
+
+		canceledBooking, err := s.Booking.CancelByEvent(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			return err
+		}
+
+		err = s.Waitlist.CancelByEvent(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			return err
+		}
+
+		err = s.PromoCode.Release(ctx, bookingIDs(canceledBooking))
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			return err
+		}
+
+		// The organizer cancelled, so the policy does not apply and paid
+		// bookings are refunded in full.
+		msgs := make([]model.NotificationInCreate, 0, len(canceledBooking))
+		for _, b := range canceledBooking {
+			_, err = refundBooking(ctx, s, es.providers, b.ID, 100, model.RefundReasonEventCanceled)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+				return err
+			}
+			msgs = append(msgs, buildEventCanceledMessage(b))
+		}
+
+		err = enqueueNotifications(ctx, s, msgs)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.CancelEvent error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (es eventService) DeleteEvent(ctx context.Context, actor model.Actor, id int) error {
+	return es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		event, err := s.Event.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.DeleteEvent error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if err := checkEventOwner(actor, event); err != nil {
+			return err
+		}
+
+		occupiedPlace, err := s.Booking.GetOccupiedPlace(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.DeleteEvent error: %v", err)
+			return err
+		}
+
+		if occupiedPlace > 0 {
+			return ErrEventHasBookings
+		}
+
+		// Deleting the event would delete its bookings, and the payments and
+		// refunds of those are kept.
+		paid, err := s.Payment.ExistsByEvent(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.DeleteEvent error: %v", err)
+			return err
+		}
+
+		if paid {
+			return ErrEventHasPayments
+		}
+
+		err = s.Event.Delete(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.EventService.DeleteEvent error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetSeatMap returns the seats of a published event with assigned seating
+// and their current state.
+func (es eventService) GetSeatMap(ctx context.Context, id int) (model.SeatMapInResponse, error) {
+	e, err := es.storage.Event.GetByID(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetSeatMap error: %v", err)
+		return model.SeatMapInResponse{}, err
+	}
+
+	if e.ID == 0 || e.Status == model.EventStatusDraft {
+		return model.SeatMapInResponse{}, ErrEventNotFound
+	}
+
+	if e.VenueID == nil {
+		return model.SeatMapInResponse{}, ErrSeatsNotAssigned
+	}
+
+	venue, err := es.storage.Venue.GetByID(ctx, *e.VenueID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetSeatMap error: %v", err)
+		return model.SeatMapInResponse{}, err
+	}
+
+	seats, err := es.storage.Seat.GetEventSeats(ctx, id, time.Now())
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.EventService.GetSeatMap error: %v", err)
+		return model.SeatMapInResponse{}, err
+	}
+
+	res := model.SeatMapInResponse{
+		EventID:   id,
+		VenueID:   venue.ID,
+		VenueName: venue.Name,
+		Seats:     make([]model.EventSeatInResponse, 0, len(seats)),
+	}
+	for _, seat := range seats {
+		switch seat.State {
+		case model.SeatStateSold:
+			res.Sold++
+		case model.SeatStateHeld:
+			res.Held++
+		default:
+			res.Free++
+		}
+		res.Seats = append(res.Seats, model.EventSeatInResponse{
+			SeatInResponse: toSeatResponse(seat.SeatInRepo),
+			TicketTypeID:   seat.TicketTypeID,
+			State:          seat.State,
+		})
+	}
+	return res, nil
+}
+
+// checkEventOwner lets actors with events:manage_all manage every event and
+// everyone else only the events they created.
+func checkEventOwner(actor model.Actor, event model.EventInRepo) error {
+	if actor.Can(model.PermissionEventsManageAll) {
+		return nil
+	}
+	if event.OrganizerID != nil && *event.OrganizerID == actor.UserID {
+		return nil
+	}
+	return ErrEventForbidden
+}
+
+func applyEventUpdate(event *model.EventInRepo, e model.EventInUpdate) error {
+	if e.Title != nil {
+		if *e.Title == "" {
+			return ErrEmptyTitle
+		}
+		event.Title = *e.Title
+	}
+
+	if e.Description != nil {
+		event.Description = *e.Description
+	}
+
+	if e.EventDate != nil {
+		if e.EventDate.Before(time.Now()) {
+			return ErrInvalidEventDate
+		}
+		event.EventDate = *e.EventDate
+	}
+
+	if e.TotalPlace != nil {
+		if *e.TotalPlace <= 0 {
+			return ErrInvalidTotalPlace
+		}
+		event.TotalPlace = *e.TotalPlace
+	}
+
+	if e.ReservationPeriod != nil {
+		reservationPeriod, err := time.ParseDuration(*e.ReservationPeriod)
+		if err != nil {
+			return fmt.Errorf("invalid reservation_period format (use '30m', '1h', etc): %w", err)
+		}
+		event.ReservationPeriod = reservationPeriod
+	}
+
+	if e.BookingConfimation != nil {
+		event.BookingConfimation = *e.BookingConfimation
+	}
+
+	return nil
+}
+
+func buildEventCanceledMessage(b model.BookingGetForTG) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString("Событие отменено организатором.\n")
+	builder.WriteString(fmt.Sprintf("Бронирование %d отменено.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeEventCanceled,
+		Subject:   fmt.Sprintf("Событие «%s» отменено", b.TitleEvent),
+		Text:      builder.String(),
+	}
+}
+
+func parseReminders(reminders []string) ([]time.Duration, error) {
+	res := make([]time.Duration, 0, len(reminders))
+	for _, r := range reminders {
+		d, err := time.ParseDuration(r)
+		if err != nil || d <= 0 {
+			return nil, ErrInvalidReminder
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+func formatReminders(reminders []time.Duration) []string {
+	res := make([]string, 0, len(reminders))
+	for _, r := range reminders {
+		res = append(res, r.String())
+	}
+	return res
 }
 
 func validateCreateEvent(e model.EventInCreate) error {
@@ -114,7 +678,7 @@ func validateCreateEvent(e model.EventInCreate) error {
 		return ErrInvalidEventDate
 	}
 
-	if e.TotalPlace <= 0 {
+	if e.TicketTypes == nil && e.VenueID == nil && e.TotalPlace <= 0 {
 		return ErrInvalidTotalPlace
 	}
 
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+// exportBatchSize bounds the exports built per scheduler tick, since every
+// export reads the whole history of a user.
+const exportBatchSize = 5
+
+type ExportService interface {
+	ExportUser(ctx context.Context, userID int, format string) ([]byte, error)
+	RequestExport(ctx context.Context, requestedBy, userID int, format string) (model.DataExportInResponse, error)
+	GetExport(ctx context.Context, id int) (model.DataExportInResponse, error)
+	DownloadExport(ctx context.Context, id int) (model.DataExportInRepo, error)
+}
+
+type exportService struct {
+	storage *repository.Storage
+}
+
+func NewExportService(s *repository.Storage) ExportService {
+	return &exportService{storage: s}
+}
+
+// ExportUser builds the export of the user right away.
+func (es *exportService) ExportUser(ctx context.Context, userID int, format string) ([]byte, error) {
+	format, err := exportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := buildExport(ctx, es.storage, userID, format)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.ExportService.ExportUser error: %v", err)
+		return nil, err
+	}
+	return payload, nil
+}
+
+// RequestExport queues an export of the user. The scheduler builds it, after
+// that the payload can be downloaded by the export id.
+func (es *exportService) RequestExport(ctx context.Context, requestedBy, userID int, format string) (model.DataExportInResponse, error) {
+	format, err := exportFormat(format)
+	if err != nil {
+		return model.DataExportInResponse{}, err
+	}
+
+	var export model.DataExportInRepo
+	err = es.storage.WithTx(ctx, func(s *repository.Storage) error {
+		user, err := s.User.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.ID == 0 {
+			return ErrUserNotFound
+		}
+
+		id, err := s.DataExport.Create(ctx, userID, requestedBy, format)
+		if err != nil {
+			return err
+		}
+		export, err = s.DataExport.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.ExportService.RequestExport error: %v", err)
+		return model.DataExportInResponse{}, err
+	}
+	return toExportResponse(export), nil
+}
+
+func (es *exportService) GetExport(ctx context.Context, id int) (model.DataExportInResponse, error) {
+	export, err := es.getExport(ctx, id)
+	if err != nil {
+		return model.DataExportInResponse{}, err
+	}
+	return toExportResponse(export), nil
+}
+
+func (es *exportService) DownloadExport(ctx context.Context, id int) (model.DataExportInRepo, error) {
+	export, err := es.getExport(ctx, id)
+	if err != nil {
+		return model.DataExportInRepo{}, err
+	}
+	if export.Status != model.StatusExportReady {
+		return model.DataExportInRepo{}, ErrExportNotReady
+	}
+	return export, nil
+}
+
+func (es *exportService) getExport(ctx context.Context, id int) (model.DataExportInRepo, error) {
+	export, err := es.storage.DataExport.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.DataExportInRepo{}, ErrExportNotFound
+		}
+		zlog.Logger.Error().Msgf("service.ExportService.GetExport error: %v", err)
+		return model.DataExportInRepo{}, err
+	}
+	return export, nil
+}
+
+func exportFormat(format string) (string, error) {
+	if format == "" {
+		return model.ExportFormatJSON, nil
+	}
+	if !slices.Contains(model.ExportFormats, format) {
+		return "", ErrInvalidExportFormat
+	}
+	return format, nil
+}
+
+func buildExport(ctx context.Context, s *repository.Storage, userID int, format string) ([]byte, error) {
+	data, err := collectPersonalData(ctx, s, userID)
+	if err != nil {
+		return nil, err
+	}
+	return encodeExport(data, format)
+}
+
+// collectPersonalData reads the whole history of the user without paging.
+func collectPersonalData(ctx context.Context, s *repository.Storage, userID int) (model.PersonalData, error) {
+	user, err := s.User.GetByID(ctx, userID)
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+	if user.ID == 0 {
+		return model.PersonalData{}, ErrUserNotFound
+	}
+
+	bookings, err := s.Booking.GetAllByUser(ctx, userID)
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+
+	notifications, err := s.Outbox.GetByUser(ctx, userID)
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+
+	sessions, err := s.Session.GetByUser(ctx, userID)
+	if err != nil {
+		return model.PersonalData{}, err
+	}
+
+	data := model.PersonalData{
+		GeneratedAt:   time.Now(),
+		User:          toUserResponse(user),
+		Bookings:      make([]model.BookingInResponse, 0, len(bookings)),
+		Notifications: make([]model.NotificationInResponse, 0, len(notifications)),
+		Sessions:      make([]model.SessionInResponse, 0, len(sessions)),
+	}
+	for _, b := range bookings {
+		data.Bookings = append(data.Bookings, toBookingResponse(b))
+	}
+	for _, n := range notifications {
+		data.Notifications = append(data.Notifications, toNotificationResponse(n))
+	}
+	for _, session := range sessions {
+		data.Sessions = append(data.Sessions, model.SessionInResponse{
+			ID:         session.ID,
+			ExpiresAt:  session.ExpiresAt,
+			RevokedAt:  session.RevokedAt,
+			LastUsedAt: session.LastUsedAt,
+			CreatedAt:  session.CreatedAt,
+		})
+	}
+	return data, nil
+}
+
+// encodeExport renders the data as one JSON document or as a ZIP archive
+// with a JSON file per section.
+func encodeExport(data model.PersonalData, format string) ([]byte, error) {
+	if format == model.ExportFormatJSON {
+		return json.MarshalIndent(data, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"user.json", data.User},
+		{"bookings.json", data.Bookings},
+		{"notifications.json", data.Notifications},
+		{"sessions.json", data.Sessions},
+	}
+	for _, f := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: data.GeneratedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		content, err := json.MarshalIndent(f.content, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		_, err = w.Write(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toExportResponse(e model.DataExportInRepo) model.DataExportInResponse {
+	return model.DataExportInResponse{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		RequestedBy: e.RequestedBy,
+		Format:      e.Format,
+		Status:      e.Status,
+		LastError:   e.LastError,
+		CreatedAt:   e.CreatedAt,
+		FinishedAt:  e.FinishedAt,
+	}
+}
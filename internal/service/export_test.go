@@ -0,0 +1,114 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestEncodeExport(t *testing.T) {
+	data := model.PersonalData{
+		GeneratedAt: time.Now(),
+		User:        model.UserInResponse{ID: 5, Email: "user@mail.com"},
+		Bookings:    []model.BookingInResponse{{ID: 1, EventTitle: "Concert"}},
+		Sessions:    []model.SessionInResponse{},
+	}
+
+	payload, err := encodeExport(data, model.ExportFormatJSON)
+	require.NoError(t, err)
+	var decoded model.PersonalData
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, "user@mail.com", decoded.User.Email)
+	assert.Equal(t, "Concert", decoded.Bookings[0].EventTitle)
+
+	payload, err = encodeExport(data, model.ExportFormatZIP)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(archive.File))
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+	assert.Len(t, files, 4)
+	assert.Contains(t, files["user.json"], "user@mail.com")
+	assert.Contains(t, files["bookings.json"], "Concert")
+	assert.Equal(t, "[]", files["sessions.json"])
+}
+
+func TestExportFormat(t *testing.T) {
+	format, err := exportFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, model.ExportFormatJSON, format)
+
+	_, err = exportFormat("xml")
+	assert.ErrorIs(t, err, ErrInvalidExportFormat)
+}
+
+func TestRequestExport_BuiltByScheduler(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var adminID, userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('admin@test.local', 'x', 'admin', now(), now()) RETURNING user_id`).Scan(&adminID)
+	require.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('export@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Export test', '', $1, 'published', 100, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 100, 0)
+
+	bs := NewBookingService(storage, nil)
+	for i := 0; i < 25; i++ {
+		err = bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID})
+		require.NoError(t, err)
+	}
+
+	es := NewExportService(storage)
+	_, err = es.RequestExport(ctx, adminID, userID+100, model.ExportFormatJSON)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	_, err = es.RequestExport(ctx, adminID, userID, "xml")
+	assert.ErrorIs(t, err, ErrInvalidExportFormat)
+
+	export, err := es.RequestExport(ctx, adminID, userID, "")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusExportPending, export.Status)
+	_, err = es.DownloadExport(ctx, export.ID)
+	assert.ErrorIs(t, err, ErrExportNotReady)
+
+	NewSchedulerService(storage, NewWaitlistService(storage)).buildExports(ctx)
+
+	ready, err := es.DownloadExport(ctx, export.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ExportFormatJSON, ready.Format)
+
+	var data model.PersonalData
+	require.NoError(t, json.Unmarshal(ready.Payload, &data))
+	assert.Equal(t, "export@test.local", data.User.Email)
+	assert.Len(t, data.Bookings, 25, "the export is not limited by page size")
+	assert.Equal(t, "Export test", data.Bookings[0].EventTitle)
+	assert.NotNil(t, data.Sessions)
+
+	_, err = es.GetExport(ctx, export.ID+100)
+	assert.ErrorIs(t, err, ErrExportNotFound)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"EventBooker/internal/repository"
+)
+
+const (
+	accountMaxFailures = 5
+	ipMaxFailures      = 20
+
+	loginFailureWindow = 15 * time.Minute
+	loginBaseLockout   = 30 * time.Second
+	loginMaxLockout    = time.Hour
+
+	memoryLimiterPruneSize = 10000
+)
+
+// LoginLimiter tracks failed logins per key (an account or a client IP) and
+// locks the key out once it reaches maxFailures. Every further failure
+// doubles the lockout. An attempt is counted as failed in the same step that
+// checks the lockout, before the password is compared, so parallel attempts
+// cannot get past the limit.
+type LoginLimiter interface {
+	// Acquire refuses the attempt while a lockout runs and returns its end.
+	// Otherwise it counts the attempt as a failure and returns the end of
+	// the lockout this attempt started, or the zero time.
+	Acquire(ctx context.Context, key string, maxFailures int) (until time.Time, allowed bool, err error)
+	// Forgive takes back the failure counted for a successful attempt and
+	// lifts the lockout it started, given as returned by Acquire.
+	Forgive(ctx context.Context, key string, lockedUntil time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginLockedError is returned while a login key is locked out.
+type LoginLockedError struct {
+	Until time.Time
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrLoginLocked, e.Until.Format(time.RFC3339))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
+
+// NewLoginLimiter returns the limiter named in the configuration: "postgres"
+// shares the counters between instances, anything else keeps them in memory.
+func NewLoginLimiter(kind string, s *repository.Storage) LoginLimiter {
+	if kind == "postgres" {
+		return NewPostgresLoginLimiter(s)
+	}
+	return NewMemoryLoginLimiter()
+}
+
+func lockoutDuration(failures, maxFailures int) time.Duration {
+	if failures < maxFailures {
+		return 0
+	}
+	d := loginBaseLockout
+	for i := maxFailures; i < failures && d < loginMaxLockout; i++ {
+		d *= 2
+	}
+	return min(d, loginMaxLockout)
+}
+
+func accountLoginKey(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+type loginAttempt struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+type memoryLoginLimiter struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttempt
+}
+
+func NewMemoryLoginLimiter() LoginLimiter {
+	return &memoryLoginLimiter{attempts: make(map[string]*loginAttempt)}
+}
+
+func (ml *memoryLoginLimiter) Acquire(_ context.Context, key string, maxFailures int) (time.Time, bool, error) {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	now := time.Now()
+	if len(ml.attempts) >= memoryLimiterPruneSize {
+		ml.prune(now)
+	}
+
+	a, ok := ml.attempts[key]
+	if !ok {
+		a = &loginAttempt{}
+		ml.attempts[key] = a
+	}
+	if a.lockedUntil.After(now) {
+		return a.lockedUntil, false, nil
+	}
+	if a.lastFailure.Before(now.Add(-loginFailureWindow)) {
+		a.failures = 0
+	}
+	a.failures++
+	a.lastFailure = now
+
+	lockout := lockoutDuration(a.failures, maxFailures)
+	if lockout == 0 {
+		return time.Time{}, true, nil
+	}
+	a.lockedUntil = now.Add(lockout)
+	return a.lockedUntil, true, nil
+}
+
+func (ml *memoryLoginLimiter) Forgive(_ context.Context, key string, lockedUntil time.Time) error {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	a, ok := ml.attempts[key]
+	if !ok {
+		return nil
+	}
+	a.failures = max(a.failures-1, 0)
+	if !lockedUntil.IsZero() && a.lockedUntil.Equal(lockedUntil) {
+		a.lockedUntil = time.Time{}
+	}
+	return nil
+}
+
+func (ml *memoryLoginLimiter) Reset(_ context.Context, key string) error {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	delete(ml.attempts, key)
+	return nil
+}
+
+// prune drops keys whose failures no longer count, so that a stream of
+// random emails or addresses cannot grow the map without bound.
+func (ml *memoryLoginLimiter) prune(now time.Time) {
+	for key, a := range ml.attempts {
+		if a.lastFailure.Before(now.Add(-loginFailureWindow)) && a.lockedUntil.Before(now) {
+			delete(ml.attempts, key)
+		}
+	}
+}
+
+type postgresLoginLimiter struct {
+	storage *repository.Storage
+}
+
+func NewPostgresLoginLimiter(s *repository.Storage) LoginLimiter {
+	return &postgresLoginLimiter{storage: s}
+}
+
+// Acquire counts the attempt and sets the lockout in one transaction. The
+// upsert locks the row of the key, so concurrent attempts on several
+// instances are counted one after another.
+func (pl *postgresLoginLimiter) Acquire(ctx context.Context, key string, maxFailures int) (time.Time, bool, error) {
+	var (
+		lockedUntil time.Time
+		allowed     bool
+	)
+	err := pl.storage.WithTx(ctx, func(s *repository.Storage) error {
+		// Postgres keeps microseconds; Forgive compares the stored lockout.
+		now := time.Now().Truncate(time.Microsecond)
+		a, err := s.LoginAttempt.RegisterAttempt(ctx, key, now, now.Add(-loginFailureWindow))
+		if err != nil {
+			return err
+		}
+		if a.LockedUntil != nil && a.LockedUntil.After(now) {
+			lockedUntil = *a.LockedUntil
+			return nil
+		}
+
+		allowed = true
+		lockout := lockoutDuration(a.Failures, maxFailures)
+		if lockout == 0 {
+			return nil
+		}
+		lockedUntil = now.Add(lockout)
+		return s.LoginAttempt.Lock(ctx, key, lockedUntil)
+	})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return lockedUntil, allowed, nil
+}
+
+func (pl *postgresLoginLimiter) Forgive(ctx context.Context, key string, lockedUntil time.Time) error {
+	var started *time.Time
+	if !lockedUntil.IsZero() {
+		started = &lockedUntil
+	}
+	return pl.storage.LoginAttempt.Forgive(ctx, key, started)
+}
+
+func (pl *postgresLoginLimiter) Reset(ctx context.Context, key string) error {
+	return pl.storage.LoginAttempt.Delete(ctx, key)
+}
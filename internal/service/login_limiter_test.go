@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{1, 0},
+		{4, 0},
+		{5, loginBaseLockout},
+		{6, 2 * loginBaseLockout},
+		{8, 8 * loginBaseLockout},
+		{100, loginMaxLockout},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, lockoutDuration(tt.failures, 5), "failures=%d", tt.failures)
+	}
+}
+
+func testLoginLimiter(t *testing.T, limiter LoginLimiter) {
+	t.Helper()
+	ctx := context.Background()
+	const key = "account:limit@test.local"
+
+	for i := 1; i < 3; i++ {
+		until, allowed, err := limiter.Acquire(ctx, key, 3)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		assert.True(t, until.IsZero(), "attempt %d must not lock", i)
+	}
+
+	until, allowed, err := limiter.Acquire(ctx, key, 3)
+	require.NoError(t, err)
+	assert.True(t, allowed, "the attempt that reaches the limit is still checked")
+	assert.WithinDuration(t, time.Now().Add(loginBaseLockout), until, time.Second)
+
+	locked, allowed, err := limiter.Acquire(ctx, key, 3)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, until.Unix(), locked.Unix())
+
+	require.NoError(t, limiter.Forgive(ctx, key, until))
+	until, allowed, err = limiter.Acquire(ctx, key, 3)
+	require.NoError(t, err)
+	assert.True(t, allowed, "a forgiven attempt lifts the lockout it started")
+	assert.WithinDuration(t, time.Now().Add(loginBaseLockout), until, time.Second)
+
+	_, allowed, err = limiter.Acquire(ctx, "account:other@test.local", 3)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.NoError(t, limiter.Reset(ctx, key))
+	until, allowed, err = limiter.Acquire(ctx, key, 3)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.True(t, until.IsZero())
+
+	// Parallel attempts are counted one by one, so only maxFailures of them
+	// get to check a password.
+	const parallelKey = "ip:10.0.0.9"
+	var (
+		wg      sync.WaitGroup
+		checked atomic.Int32
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, allowed, err := limiter.Acquire(ctx, parallelKey, 5)
+			assert.NoError(t, err)
+			if allowed {
+				checked.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(5), checked.Load())
+}
+
+func TestMemoryLoginLimiter(t *testing.T) {
+	testLoginLimiter(t, NewMemoryLoginLimiter())
+}
+
+func TestPostgresLoginLimiter(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	testLoginLimiter(t, NewPostgresLoginLimiter(storage))
+}
+
+func TestLogin_LocksOutAfterFailures(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	_, err := us.CreateUser(ctx, model.UserInCreate{Email: "brute@test.local", Password: "secret"})
+	require.NoError(t, err)
+
+	wrong := model.UserLoginRequest{Email: "brute@test.local", Password: "wrong", IP: "10.0.0.1"}
+	for i := 1; i < accountMaxFailures; i++ {
+		_, err = us.Login(ctx, wrong)
+		require.ErrorIs(t, err, ErrUnauthorized)
+	}
+	_, err = us.Login(ctx, wrong)
+	require.ErrorIs(t, err, ErrLoginLocked)
+
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "brute@test.local", Password: "secret", IP: "10.0.0.2"})
+	var locked *LoginLockedError
+	require.ErrorAs(t, err, &locked, "the correct password must not bypass the account lockout")
+	assert.True(t, locked.Until.After(time.Now()))
+
+	user, err := storage.User.GetByEmail(ctx, "brute@test.local")
+	require.NoError(t, err)
+	require.NoError(t, us.UnlockUser(ctx, user.ID))
+
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: " Brute@Test.Local ", Password: "secret", IP: "10.0.0.2"})
+	assert.NoError(t, err, "emails are matched case-insensitively")
+
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "nobody@test.local", Password: "secret", IP: "10.0.0.3"})
+	assert.ErrorIs(t, err, ErrUnauthorized, "unknown emails get the same answer as a wrong password")
+}
@@ -301,6 +301,84 @@ func (_c *MockBookingService_GetByUserID_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// GetCancellationQuote provides a mock function for the type MockBookingService
+func (_mock *MockBookingService) GetCancellationQuote(ctx context.Context, bookID int, eventID int, userID int) (model.CancellationQuote, error) {
+	ret := _mock.Called(ctx, bookID, eventID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCancellationQuote")
+	}
+
+	var r0 model.CancellationQuote
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) (model.CancellationQuote, error)); ok {
+		return returnFunc(ctx, bookID, eventID, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, int) model.CancellationQuote); ok {
+		r0 = returnFunc(ctx, bookID, eventID, userID)
+	} else {
+		r0 = ret.Get(0).(model.CancellationQuote)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = returnFunc(ctx, bookID, eventID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockBookingService_GetCancellationQuote_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCancellationQuote'
+type MockBookingService_GetCancellationQuote_Call struct {
+	*mock.Call
+}
+
+// GetCancellationQuote is a helper method to define mock.On call
+//   - ctx context.Context
+//   - bookID int
+//   - eventID int
+//   - userID int
+func (_e *MockBookingService_Expecter) GetCancellationQuote(ctx interface{}, bookID interface{}, eventID interface{}, userID interface{}) *MockBookingService_GetCancellationQuote_Call {
+	return &MockBookingService_GetCancellationQuote_Call{Call: _e.mock.On("GetCancellationQuote", ctx, bookID, eventID, userID)}
+}
+
+func (_c *MockBookingService_GetCancellationQuote_Call) Run(run func(ctx context.Context, bookID int, eventID int, userID int)) *MockBookingService_GetCancellationQuote_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockBookingService_GetCancellationQuote_Call) Return(cancellationQuote model.CancellationQuote, err error) *MockBookingService_GetCancellationQuote_Call {
+	_c.Call.Return(cancellationQuote, err)
+	return _c
+}
+
+func (_c *MockBookingService_GetCancellationQuote_Call) RunAndReturn(run func(ctx context.Context, bookID int, eventID int, userID int) (model.CancellationQuote, error)) *MockBookingService_GetCancellationQuote_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCountUserBooking provides a mock function for the type MockBookingService
 func (_mock *MockBookingService) GetCountUserBooking(ctx context.Context, userID int) (int, error) {
 	ret := _mock.Called(ctx, userID)
@@ -38,17 +38,149 @@ func (_m *MockEventService) EXPECT() *MockEventService_Expecter {
 	return &MockEventService_Expecter{mock: &_m.Mock}
 }
 
+// CancelEvent provides a mock function for the type MockEventService
+func (_mock *MockEventService) CancelEvent(ctx context.Context, actor model.Actor, id int) error {
+	ret := _mock.Called(ctx, actor, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Actor, int) error); ok {
+		r0 = returnFunc(ctx, actor, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEventService_CancelEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelEvent'
+type MockEventService_CancelEvent_Call struct {
+	*mock.Call
+}
+
+// CancelEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actor model.Actor
+//   - id int
+func (_e *MockEventService_Expecter) CancelEvent(ctx interface{}, actor interface{}, id interface{}) *MockEventService_CancelEvent_Call {
+	return &MockEventService_CancelEvent_Call{Call: _e.mock.On("CancelEvent", ctx, actor, id)}
+}
+
+func (_c *MockEventService_CancelEvent_Call) Run(run func(ctx context.Context, actor model.Actor, id int)) *MockEventService_CancelEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Actor
+		if args[1] != nil {
+			arg1 = args[1].(model.Actor)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEventService_CancelEvent_Call) Return(err error) *MockEventService_CancelEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEventService_CancelEvent_Call) RunAndReturn(run func(ctx context.Context, actor model.Actor, id int) error) *MockEventService_CancelEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangeStatus provides a mock function for the type MockEventService
+func (_mock *MockEventService) ChangeStatus(ctx context.Context, actor model.Actor, id int, status string) error {
+	ret := _mock.Called(ctx, actor, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeStatus")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Actor, int, string) error); ok {
+		r0 = returnFunc(ctx, actor, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEventService_ChangeStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeStatus'
+type MockEventService_ChangeStatus_Call struct {
+	*mock.Call
+}
+
+// ChangeStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actor model.Actor
+//   - id int
+//   - status string
+func (_e *MockEventService_Expecter) ChangeStatus(ctx interface{}, actor interface{}, id interface{}, status interface{}) *MockEventService_ChangeStatus_Call {
+	return &MockEventService_ChangeStatus_Call{Call: _e.mock.On("ChangeStatus", ctx, actor, id, status)}
+}
+
+func (_c *MockEventService_ChangeStatus_Call) Run(run func(ctx context.Context, actor model.Actor, id int, status string)) *MockEventService_ChangeStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Actor
+		if args[1] != nil {
+			arg1 = args[1].(model.Actor)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEventService_ChangeStatus_Call) Return(err error) *MockEventService_ChangeStatus_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEventService_ChangeStatus_Call) RunAndReturn(run func(ctx context.Context, actor model.Actor, id int, status string) error) *MockEventService_ChangeStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateEvent provides a mock function for the type MockEventService
-func (_mock *MockEventService) CreateEvent(ctx context.Context, e model.EventInCreate) error {
-	ret := _mock.Called(ctx, e)
+func (_mock *MockEventService) CreateEvent(ctx context.Context, actor model.Actor, e model.EventInCreate) error {
+	ret := _mock.Called(ctx, actor, e)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateEvent")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.EventInCreate) error); ok {
-		r0 = returnFunc(ctx, e)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Actor, model.EventInCreate) error); ok {
+		r0 = returnFunc(ctx, actor, e)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,24 +194,30 @@ type MockEventService_CreateEvent_Call struct {
 
 // CreateEvent is a helper method to define mock.On call
 //   - ctx context.Context
+//   - actor model.Actor
 //   - e model.EventInCreate
-func (_e *MockEventService_Expecter) CreateEvent(ctx interface{}, e interface{}) *MockEventService_CreateEvent_Call {
-	return &MockEventService_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, e)}
+func (_e *MockEventService_Expecter) CreateEvent(ctx interface{}, actor interface{}, e interface{}) *MockEventService_CreateEvent_Call {
+	return &MockEventService_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, actor, e)}
 }
 
-func (_c *MockEventService_CreateEvent_Call) Run(run func(ctx context.Context, e model.EventInCreate)) *MockEventService_CreateEvent_Call {
+func (_c *MockEventService_CreateEvent_Call) Run(run func(ctx context.Context, actor model.Actor, e model.EventInCreate)) *MockEventService_CreateEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.EventInCreate
+		var arg1 model.Actor
 		if args[1] != nil {
-			arg1 = args[1].(model.EventInCreate)
+			arg1 = args[1].(model.Actor)
+		}
+		var arg2 model.EventInCreate
+		if args[2] != nil {
+			arg2 = args[2].(model.EventInCreate)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -90,7 +228,70 @@ func (_c *MockEventService_CreateEvent_Call) Return(err error) *MockEventService
 	return _c
 }
 
-func (_c *MockEventService_CreateEvent_Call) RunAndReturn(run func(ctx context.Context, e model.EventInCreate) error) *MockEventService_CreateEvent_Call {
+func (_c *MockEventService_CreateEvent_Call) RunAndReturn(run func(ctx context.Context, actor model.Actor, e model.EventInCreate) error) *MockEventService_CreateEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteEvent provides a mock function for the type MockEventService
+func (_mock *MockEventService) DeleteEvent(ctx context.Context, actor model.Actor, id int) error {
+	ret := _mock.Called(ctx, actor, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Actor, int) error); ok {
+		r0 = returnFunc(ctx, actor, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEventService_DeleteEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEvent'
+type MockEventService_DeleteEvent_Call struct {
+	*mock.Call
+}
+
+// DeleteEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actor model.Actor
+//   - id int
+func (_e *MockEventService_Expecter) DeleteEvent(ctx interface{}, actor interface{}, id interface{}) *MockEventService_DeleteEvent_Call {
+	return &MockEventService_DeleteEvent_Call{Call: _e.mock.On("DeleteEvent", ctx, actor, id)}
+}
+
+func (_c *MockEventService_DeleteEvent_Call) Run(run func(ctx context.Context, actor model.Actor, id int)) *MockEventService_DeleteEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Actor
+		if args[1] != nil {
+			arg1 = args[1].(model.Actor)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEventService_DeleteEvent_Call) Return(err error) *MockEventService_DeleteEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEventService_DeleteEvent_Call) RunAndReturn(run func(ctx context.Context, actor model.Actor, id int) error) *MockEventService_DeleteEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -162,8 +363,8 @@ func (_c *MockEventService_GetByID_Call) RunAndReturn(run func(ctx context.Conte
 }
 
 // GetCountEvent provides a mock function for the type MockEventService
-func (_mock *MockEventService) GetCountEvent(ctx context.Context) (int, error) {
-	ret := _mock.Called(ctx)
+func (_mock *MockEventService) GetCountEvent(ctx context.Context, includeDrafts bool, organizerID int) (int, error) {
+	ret := _mock.Called(ctx, includeDrafts, organizerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCountEvent")
@@ -171,16 +372,16 @@ func (_mock *MockEventService) GetCountEvent(ctx context.Context) (int, error) {
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
-		return returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool, int) (int, error)); ok {
+		return returnFunc(ctx, includeDrafts, organizerID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
-		r0 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bool, int) int); ok {
+		r0 = returnFunc(ctx, includeDrafts, organizerID)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, bool, int) error); ok {
+		r1 = returnFunc(ctx, includeDrafts, organizerID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -194,18 +395,30 @@ type MockEventService_GetCountEvent_Call struct {
 
 // GetCountEvent is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockEventService_Expecter) GetCountEvent(ctx interface{}) *MockEventService_GetCountEvent_Call {
-	return &MockEventService_GetCountEvent_Call{Call: _e.mock.On("GetCountEvent", ctx)}
+//   - includeDrafts bool
+//   - organizerID int
+func (_e *MockEventService_Expecter) GetCountEvent(ctx interface{}, includeDrafts interface{}, organizerID interface{}) *MockEventService_GetCountEvent_Call {
+	return &MockEventService_GetCountEvent_Call{Call: _e.mock.On("GetCountEvent", ctx, includeDrafts, organizerID)}
 }
 
-func (_c *MockEventService_GetCountEvent_Call) Run(run func(ctx context.Context)) *MockEventService_GetCountEvent_Call {
+func (_c *MockEventService_GetCountEvent_Call) Run(run func(ctx context.Context, includeDrafts bool, organizerID int)) *MockEventService_GetCountEvent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -216,7 +429,7 @@ func (_c *MockEventService_GetCountEvent_Call) Return(n int, err error) *MockEve
 	return _c
 }
 
-func (_c *MockEventService_GetCountEvent_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockEventService_GetCountEvent_Call {
+func (_c *MockEventService_GetCountEvent_Call) RunAndReturn(run func(ctx context.Context, includeDrafts bool, organizerID int) (int, error)) *MockEventService_GetCountEvent_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -288,3 +501,138 @@ func (_c *MockEventService_GetListEvents_Call) RunAndReturn(run func(ctx context
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetSeatMap provides a mock function for the type MockEventService
+func (_mock *MockEventService) GetSeatMap(ctx context.Context, id int) (model.SeatMapInResponse, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeatMap")
+	}
+
+	var r0 model.SeatMapInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (model.SeatMapInResponse, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) model.SeatMapInResponse); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.SeatMapInResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockEventService_GetSeatMap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSeatMap'
+type MockEventService_GetSeatMap_Call struct {
+	*mock.Call
+}
+
+// GetSeatMap is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockEventService_Expecter) GetSeatMap(ctx interface{}, id interface{}) *MockEventService_GetSeatMap_Call {
+	return &MockEventService_GetSeatMap_Call{Call: _e.mock.On("GetSeatMap", ctx, id)}
+}
+
+func (_c *MockEventService_GetSeatMap_Call) Run(run func(ctx context.Context, id int)) *MockEventService_GetSeatMap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEventService_GetSeatMap_Call) Return(seatMapInResponse model.SeatMapInResponse, err error) *MockEventService_GetSeatMap_Call {
+	_c.Call.Return(seatMapInResponse, err)
+	return _c
+}
+
+func (_c *MockEventService_GetSeatMap_Call) RunAndReturn(run func(ctx context.Context, id int) (model.SeatMapInResponse, error)) *MockEventService_GetSeatMap_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEvent provides a mock function for the type MockEventService
+func (_mock *MockEventService) UpdateEvent(ctx context.Context, actor model.Actor, id int, e model.EventInUpdate) error {
+	ret := _mock.Called(ctx, actor, id, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.Actor, int, model.EventInUpdate) error); ok {
+		r0 = returnFunc(ctx, actor, id, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockEventService_UpdateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEvent'
+type MockEventService_UpdateEvent_Call struct {
+	*mock.Call
+}
+
+// UpdateEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actor model.Actor
+//   - id int
+//   - e model.EventInUpdate
+func (_e *MockEventService_Expecter) UpdateEvent(ctx interface{}, actor interface{}, id interface{}, e interface{}) *MockEventService_UpdateEvent_Call {
+	return &MockEventService_UpdateEvent_Call{Call: _e.mock.On("UpdateEvent", ctx, actor, id, e)}
+}
+
+func (_c *MockEventService_UpdateEvent_Call) Run(run func(ctx context.Context, actor model.Actor, id int, e model.EventInUpdate)) *MockEventService_UpdateEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.Actor
+		if args[1] != nil {
+			arg1 = args[1].(model.Actor)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 model.EventInUpdate
+		if args[3] != nil {
+			arg3 = args[3].(model.EventInUpdate)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockEventService_UpdateEvent_Call) Return(err error) *MockEventService_UpdateEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockEventService_UpdateEvent_Call) RunAndReturn(run func(ctx context.Context, actor model.Actor, id int, e model.EventInUpdate) error) *MockEventService_UpdateEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
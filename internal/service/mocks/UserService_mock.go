@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"EventBooker/internal/model"
+	"EventBooker/internal/service"
 	"context"
 
 	mock "github.com/stretchr/testify/mock"
@@ -38,23 +39,298 @@ func (_m *MockUserService) EXPECT() *MockUserService_Expecter {
 	return &MockUserService_Expecter{mock: &_m.Mock}
 }
 
+// Authenticate provides a mock function for the type MockUserService
+func (_mock *MockUserService) Authenticate(ctx context.Context, token string) (*service.Claims, error) {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *service.Claims
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*service.Claims, error)); ok {
+		return returnFunc(ctx, token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *service.Claims); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Claims)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_Authenticate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authenticate'
+type MockUserService_Authenticate_Call struct {
+	*mock.Call
+}
+
+// Authenticate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockUserService_Expecter) Authenticate(ctx interface{}, token interface{}) *MockUserService_Authenticate_Call {
+	return &MockUserService_Authenticate_Call{Call: _e.mock.On("Authenticate", ctx, token)}
+}
+
+func (_c *MockUserService_Authenticate_Call) Run(run func(ctx context.Context, token string)) *MockUserService_Authenticate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_Authenticate_Call) Return(claims *service.Claims, err error) *MockUserService_Authenticate_Call {
+	_c.Call.Return(claims, err)
+	return _c
+}
+
+func (_c *MockUserService_Authenticate_Call) RunAndReturn(run func(ctx context.Context, token string) (*service.Claims, error)) *MockUserService_Authenticate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangePassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) ChangePassword(ctx context.Context, userID int, req model.PasswordChangeRequest) (model.AuthTokens, error) {
+	ret := _mock.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 model.AuthTokens
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, model.PasswordChangeRequest) (model.AuthTokens, error)); ok {
+		return returnFunc(ctx, userID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, model.PasswordChangeRequest) model.AuthTokens); ok {
+		r0 = returnFunc(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(model.AuthTokens)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, model.PasswordChangeRequest) error); ok {
+		r1 = returnFunc(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockUserService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - req model.PasswordChangeRequest
+func (_e *MockUserService_Expecter) ChangePassword(ctx interface{}, userID interface{}, req interface{}) *MockUserService_ChangePassword_Call {
+	return &MockUserService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, userID, req)}
+}
+
+func (_c *MockUserService_ChangePassword_Call) Run(run func(ctx context.Context, userID int, req model.PasswordChangeRequest)) *MockUserService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 model.PasswordChangeRequest
+		if args[2] != nil {
+			arg2 = args[2].(model.PasswordChangeRequest)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_ChangePassword_Call) Return(authTokens model.AuthTokens, err error) *MockUserService_ChangePassword_Call {
+	_c.Call.Return(authTokens, err)
+	return _c
+}
+
+func (_c *MockUserService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, userID int, req model.PasswordChangeRequest) (model.AuthTokens, error)) *MockUserService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangeRole provides a mock function for the type MockUserService
+func (_mock *MockUserService) ChangeRole(ctx context.Context, actorID int, userID int, role string) error {
+	ret := _mock.Called(ctx, actorID, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int, string) error); ok {
+		r0 = returnFunc(ctx, actorID, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_ChangeRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeRole'
+type MockUserService_ChangeRole_Call struct {
+	*mock.Call
+}
+
+// ChangeRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID int
+//   - userID int
+//   - role string
+func (_e *MockUserService_Expecter) ChangeRole(ctx interface{}, actorID interface{}, userID interface{}, role interface{}) *MockUserService_ChangeRole_Call {
+	return &MockUserService_ChangeRole_Call{Call: _e.mock.On("ChangeRole", ctx, actorID, userID, role)}
+}
+
+func (_c *MockUserService_ChangeRole_Call) Run(run func(ctx context.Context, actorID int, userID int, role string)) *MockUserService_ChangeRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_ChangeRole_Call) Return(err error) *MockUserService_ChangeRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_ChangeRole_Call) RunAndReturn(run func(ctx context.Context, actorID int, userID int, role string) error) *MockUserService_ChangeRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTgLinkCode provides a mock function for the type MockUserService
+func (_mock *MockUserService) CreateTgLinkCode(ctx context.Context, userID int) (model.TgLinkCodeInResponse, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTgLinkCode")
+	}
+
+	var r0 model.TgLinkCodeInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (model.TgLinkCodeInResponse, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) model.TgLinkCodeInResponse); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.TgLinkCodeInResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_CreateTgLinkCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTgLinkCode'
+type MockUserService_CreateTgLinkCode_Call struct {
+	*mock.Call
+}
+
+// CreateTgLinkCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) CreateTgLinkCode(ctx interface{}, userID interface{}) *MockUserService_CreateTgLinkCode_Call {
+	return &MockUserService_CreateTgLinkCode_Call{Call: _e.mock.On("CreateTgLinkCode", ctx, userID)}
+}
+
+func (_c *MockUserService_CreateTgLinkCode_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_CreateTgLinkCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_CreateTgLinkCode_Call) Return(tgLinkCodeInResponse model.TgLinkCodeInResponse, err error) *MockUserService_CreateTgLinkCode_Call {
+	_c.Call.Return(tgLinkCodeInResponse, err)
+	return _c
+}
+
+func (_c *MockUserService_CreateTgLinkCode_Call) RunAndReturn(run func(ctx context.Context, userID int) (model.TgLinkCodeInResponse, error)) *MockUserService_CreateTgLinkCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockUserService
-func (_mock *MockUserService) CreateUser(ctx context.Context, u model.UserInCreate) (string, error) {
+func (_mock *MockUserService) CreateUser(ctx context.Context, u model.UserInCreate) (model.AuthTokens, error) {
 	ret := _mock.Called(ctx, u)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
 	}
 
-	var r0 string
+	var r0 model.AuthTokens
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserInCreate) (string, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserInCreate) (model.AuthTokens, error)); ok {
 		return returnFunc(ctx, u)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserInCreate) string); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserInCreate) model.AuthTokens); ok {
 		r0 = returnFunc(ctx, u)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(model.AuthTokens)
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, model.UserInCreate) error); ok {
 		r1 = returnFunc(ctx, u)
@@ -94,191 +370,1258 @@ func (_c *MockUserService_CreateUser_Call) Run(run func(ctx context.Context, u m
 	return _c
 }
 
-func (_c *MockUserService_CreateUser_Call) Return(s string, err error) *MockUserService_CreateUser_Call {
-	_c.Call.Return(s, err)
+func (_c *MockUserService_CreateUser_Call) Return(authTokens model.AuthTokens, err error) *MockUserService_CreateUser_Call {
+	_c.Call.Return(authTokens, err)
 	return _c
 }
 
-func (_c *MockUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Context, u model.UserInCreate) (string, error)) *MockUserService_CreateUser_Call {
+func (_c *MockUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Context, u model.UserInCreate) (model.AuthTokens, error)) *MockUserService_CreateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCountUsers provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetCountUsers(ctx context.Context) (int, error) {
-	ret := _mock.Called(ctx)
+// DeleteAccount provides a mock function for the type MockUserService
+func (_mock *MockUserService) DeleteAccount(ctx context.Context, userID int, password string) error {
+	ret := _mock.Called(ctx, userID, password)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCountUsers")
+		panic("no return value specified for DeleteAccount")
 	}
 
-	var r0 int
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
-		return returnFunc(ctx)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
-		r0 = returnFunc(ctx)
-	} else {
-		r0 = ret.Get(0).(int)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = returnFunc(ctx)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = returnFunc(ctx, userID, password)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockUserService_GetCountUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountUsers'
-type MockUserService_GetCountUsers_Call struct {
+// MockUserService_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type MockUserService_DeleteAccount_Call struct {
 	*mock.Call
 }
 
-// GetCountUsers is a helper method to define mock.On call
+// DeleteAccount is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockUserService_Expecter) GetCountUsers(ctx interface{}) *MockUserService_GetCountUsers_Call {
-	return &MockUserService_GetCountUsers_Call{Call: _e.mock.On("GetCountUsers", ctx)}
+//   - userID int
+//   - password string
+func (_e *MockUserService_Expecter) DeleteAccount(ctx interface{}, userID interface{}, password interface{}) *MockUserService_DeleteAccount_Call {
+	return &MockUserService_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", ctx, userID, password)}
 }
 
-func (_c *MockUserService_GetCountUsers_Call) Run(run func(ctx context.Context)) *MockUserService_GetCountUsers_Call {
+func (_c *MockUserService_DeleteAccount_Call) Run(run func(ctx context.Context, userID int, password string)) *MockUserService_DeleteAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetCountUsers_Call) Return(n int, err error) *MockUserService_GetCountUsers_Call {
-	_c.Call.Return(n, err)
+func (_c *MockUserService_DeleteAccount_Call) Return(err error) *MockUserService_DeleteAccount_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_GetCountUsers_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockUserService_GetCountUsers_Call {
+func (_c *MockUserService_DeleteAccount_Call) RunAndReturn(run func(ctx context.Context, userID int, password string) error) *MockUserService_DeleteAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetListUsers provides a mock function for the type MockUserService
-func (_mock *MockUserService) GetListUsers(ctx context.Context, req model.UserGetRequest) ([]model.UserInResponse, error) {
-	ret := _mock.Called(ctx, req)
+// EnsureAdmin provides a mock function for the type MockUserService
+func (_mock *MockUserService) EnsureAdmin(ctx context.Context, email string, password string) (bool, error) {
+	ret := _mock.Called(ctx, email, password)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetListUsers")
+		panic("no return value specified for EnsureAdmin")
 	}
 
-	var r0 []model.UserInResponse
+	var r0 bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserGetRequest) ([]model.UserInResponse, error)); ok {
-		return returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return returnFunc(ctx, email, password)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserGetRequest) []model.UserInResponse); ok {
-		r0 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = returnFunc(ctx, email, password)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.UserInResponse)
-		}
+		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, model.UserGetRequest) error); ok {
-		r1 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, email, password)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_GetListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetListUsers'
-type MockUserService_GetListUsers_Call struct {
+// MockUserService_EnsureAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnsureAdmin'
+type MockUserService_EnsureAdmin_Call struct {
 	*mock.Call
 }
 
-// GetListUsers is a helper method to define mock.On call
+// EnsureAdmin is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req model.UserGetRequest
-func (_e *MockUserService_Expecter) GetListUsers(ctx interface{}, req interface{}) *MockUserService_GetListUsers_Call {
-	return &MockUserService_GetListUsers_Call{Call: _e.mock.On("GetListUsers", ctx, req)}
+//   - email string
+//   - password string
+func (_e *MockUserService_Expecter) EnsureAdmin(ctx interface{}, email interface{}, password interface{}) *MockUserService_EnsureAdmin_Call {
+	return &MockUserService_EnsureAdmin_Call{Call: _e.mock.On("EnsureAdmin", ctx, email, password)}
 }
 
-func (_c *MockUserService_GetListUsers_Call) Run(run func(ctx context.Context, req model.UserGetRequest)) *MockUserService_GetListUsers_Call {
+func (_c *MockUserService_EnsureAdmin_Call) Run(run func(ctx context.Context, email string, password string)) *MockUserService_EnsureAdmin_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.UserGetRequest
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(model.UserGetRequest)
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockUserService_GetListUsers_Call) Return(userInResponses []model.UserInResponse, err error) *MockUserService_GetListUsers_Call {
-	_c.Call.Return(userInResponses, err)
+func (_c *MockUserService_EnsureAdmin_Call) Return(b bool, err error) *MockUserService_EnsureAdmin_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockUserService_GetListUsers_Call) RunAndReturn(run func(ctx context.Context, req model.UserGetRequest) ([]model.UserInResponse, error)) *MockUserService_GetListUsers_Call {
+func (_c *MockUserService_EnsureAdmin_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (bool, error)) *MockUserService_EnsureAdmin_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function for the type MockUserService
-func (_mock *MockUserService) Login(ctx context.Context, req model.UserLoginRequest) (string, error) {
-	ret := _mock.Called(ctx, req)
+// GetByTgChatID provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetByTgChatID(ctx context.Context, chatID int64) (model.UserInResponse, error) {
+	ret := _mock.Called(ctx, chatID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for GetByTgChatID")
 	}
 
-	var r0 string
+	var r0 model.UserInResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserLoginRequest) (string, error)); ok {
-		return returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) (model.UserInResponse, error)); ok {
+		return returnFunc(ctx, chatID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserLoginRequest) string); ok {
-		r0 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) model.UserInResponse); ok {
+		r0 = returnFunc(ctx, chatID)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(model.UserInResponse)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, model.UserLoginRequest) error); ok {
-		r1 = returnFunc(ctx, req)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, chatID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockUserService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type MockUserService_Login_Call struct {
+// MockUserService_GetByTgChatID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByTgChatID'
+type MockUserService_GetByTgChatID_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// GetByTgChatID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - req model.UserLoginRequest
-func (_e *MockUserService_Expecter) Login(ctx interface{}, req interface{}) *MockUserService_Login_Call {
-	return &MockUserService_Login_Call{Call: _e.mock.On("Login", ctx, req)}
+//   - chatID int64
+func (_e *MockUserService_Expecter) GetByTgChatID(ctx interface{}, chatID interface{}) *MockUserService_GetByTgChatID_Call {
+	return &MockUserService_GetByTgChatID_Call{Call: _e.mock.On("GetByTgChatID", ctx, chatID)}
 }
 
-func (_c *MockUserService_Login_Call) Run(run func(ctx context.Context, req model.UserLoginRequest)) *MockUserService_Login_Call {
+func (_c *MockUserService_GetByTgChatID_Call) Run(run func(ctx context.Context, chatID int64)) *MockUserService_GetByTgChatID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 model.UserLoginRequest
+		var arg1 int64
 		if args[1] != nil {
-			arg1 = args[1].(model.UserLoginRequest)
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetByTgChatID_Call) Return(userInResponse model.UserInResponse, err error) *MockUserService_GetByTgChatID_Call {
+	_c.Call.Return(userInResponse, err)
+	return _c
+}
+
+func (_c *MockUserService_GetByTgChatID_Call) RunAndReturn(run func(ctx context.Context, chatID int64) (model.UserInResponse, error)) *MockUserService_GetByTgChatID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCountUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetCountUsers(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCountUsers")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetCountUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCountUsers'
+type MockUserService_GetCountUsers_Call struct {
+	*mock.Call
+}
+
+// GetCountUsers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUserService_Expecter) GetCountUsers(ctx interface{}) *MockUserService_GetCountUsers_Call {
+	return &MockUserService_GetCountUsers_Call{Call: _e.mock.On("GetCountUsers", ctx)}
+}
+
+func (_c *MockUserService_GetCountUsers_Call) Run(run func(ctx context.Context)) *MockUserService_GetCountUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetCountUsers_Call) Return(n int, err error) *MockUserService_GetCountUsers_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserService_GetCountUsers_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockUserService_GetCountUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetListUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetListUsers(ctx context.Context, req model.UserGetRequest) ([]model.UserInResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListUsers")
+	}
+
+	var r0 []model.UserInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserGetRequest) ([]model.UserInResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserGetRequest) []model.UserInResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UserInResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.UserGetRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetListUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetListUsers'
+type MockUserService_GetListUsers_Call struct {
+	*mock.Call
+}
+
+// GetListUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req model.UserGetRequest
+func (_e *MockUserService_Expecter) GetListUsers(ctx interface{}, req interface{}) *MockUserService_GetListUsers_Call {
+	return &MockUserService_GetListUsers_Call{Call: _e.mock.On("GetListUsers", ctx, req)}
+}
+
+func (_c *MockUserService_GetListUsers_Call) Run(run func(ctx context.Context, req model.UserGetRequest)) *MockUserService_GetListUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.UserGetRequest
+		if args[1] != nil {
+			arg1 = args[1].(model.UserGetRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetListUsers_Call) Return(userInResponses []model.UserInResponse, err error) *MockUserService_GetListUsers_Call {
+	_c.Call.Return(userInResponses, err)
+	return _c
+}
+
+func (_c *MockUserService_GetListUsers_Call) RunAndReturn(run func(ctx context.Context, req model.UserGetRequest) ([]model.UserInResponse, error)) *MockUserService_GetListUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProfile provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetProfile(ctx context.Context, userID int) (model.UserInResponse, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 model.UserInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (model.UserInResponse, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) model.UserInResponse); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.UserInResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProfile'
+type MockUserService_GetProfile_Call struct {
+	*mock.Call
+}
+
+// GetProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) GetProfile(ctx interface{}, userID interface{}) *MockUserService_GetProfile_Call {
+	return &MockUserService_GetProfile_Call{Call: _e.mock.On("GetProfile", ctx, userID)}
+}
+
+func (_c *MockUserService_GetProfile_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_GetProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetProfile_Call) Return(userInResponse model.UserInResponse, err error) *MockUserService_GetProfile_Call {
+	_c.Call.Return(userInResponse, err)
+	return _c
+}
+
+func (_c *MockUserService_GetProfile_Call) RunAndReturn(run func(ctx context.Context, userID int) (model.UserInResponse, error)) *MockUserService_GetProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRolePermissions provides a mock function for the type MockUserService
+func (_mock *MockUserService) GetRolePermissions(ctx context.Context) ([]model.RolePermissions, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRolePermissions")
+	}
+
+	var r0 []model.RolePermissions
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]model.RolePermissions, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []model.RolePermissions); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.RolePermissions)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_GetRolePermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRolePermissions'
+type MockUserService_GetRolePermissions_Call struct {
+	*mock.Call
+}
+
+// GetRolePermissions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUserService_Expecter) GetRolePermissions(ctx interface{}) *MockUserService_GetRolePermissions_Call {
+	return &MockUserService_GetRolePermissions_Call{Call: _e.mock.On("GetRolePermissions", ctx)}
+}
+
+func (_c *MockUserService_GetRolePermissions_Call) Run(run func(ctx context.Context)) *MockUserService_GetRolePermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetRolePermissions_Call) Return(rolePermissionss []model.RolePermissions, err error) *MockUserService_GetRolePermissions_Call {
+	_c.Call.Return(rolePermissionss, err)
+	return _c
+}
+
+func (_c *MockUserService_GetRolePermissions_Call) RunAndReturn(run func(ctx context.Context) ([]model.RolePermissions, error)) *MockUserService_GetRolePermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkTelegram provides a mock function for the type MockUserService
+func (_mock *MockUserService) LinkTelegram(ctx context.Context, code string, chatID int64) (model.UserInResponse, error) {
+	ret := _mock.Called(ctx, code, chatID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkTelegram")
+	}
+
+	var r0 model.UserInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (model.UserInResponse, error)); ok {
+		return returnFunc(ctx, code, chatID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) model.UserInResponse); ok {
+		r0 = returnFunc(ctx, code, chatID)
+	} else {
+		r0 = ret.Get(0).(model.UserInResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, code, chatID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_LinkTelegram_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkTelegram'
+type MockUserService_LinkTelegram_Call struct {
+	*mock.Call
+}
+
+// LinkTelegram is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - chatID int64
+func (_e *MockUserService_Expecter) LinkTelegram(ctx interface{}, code interface{}, chatID interface{}) *MockUserService_LinkTelegram_Call {
+	return &MockUserService_LinkTelegram_Call{Call: _e.mock.On("LinkTelegram", ctx, code, chatID)}
+}
+
+func (_c *MockUserService_LinkTelegram_Call) Run(run func(ctx context.Context, code string, chatID int64)) *MockUserService_LinkTelegram_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_LinkTelegram_Call) Return(userInResponse model.UserInResponse, err error) *MockUserService_LinkTelegram_Call {
+	_c.Call.Return(userInResponse, err)
+	return _c
+}
+
+func (_c *MockUserService_LinkTelegram_Call) RunAndReturn(run func(ctx context.Context, code string, chatID int64) (model.UserInResponse, error)) *MockUserService_LinkTelegram_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type MockUserService
+func (_mock *MockUserService) Login(ctx context.Context, req model.UserLoginRequest) (model.AuthTokens, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 model.AuthTokens
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserLoginRequest) (model.AuthTokens, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.UserLoginRequest) model.AuthTokens); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.AuthTokens)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.UserLoginRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type MockUserService_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req model.UserLoginRequest
+func (_e *MockUserService_Expecter) Login(ctx interface{}, req interface{}) *MockUserService_Login_Call {
+	return &MockUserService_Login_Call{Call: _e.mock.On("Login", ctx, req)}
+}
+
+func (_c *MockUserService_Login_Call) Run(run func(ctx context.Context, req model.UserLoginRequest)) *MockUserService_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.UserLoginRequest
+		if args[1] != nil {
+			arg1 = args[1].(model.UserLoginRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_Login_Call) Return(authTokens model.AuthTokens, err error) *MockUserService_Login_Call {
+	_c.Call.Return(authTokens, err)
+	return _c
+}
+
+func (_c *MockUserService_Login_Call) RunAndReturn(run func(ctx context.Context, req model.UserLoginRequest) (model.AuthTokens, error)) *MockUserService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function for the type MockUserService
+func (_mock *MockUserService) Logout(ctx context.Context, refreshToken string) error {
+	ret := _mock.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type MockUserService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *MockUserService_Expecter) Logout(ctx interface{}, refreshToken interface{}) *MockUserService_Logout_Call {
+	return &MockUserService_Logout_Call{Call: _e.mock.On("Logout", ctx, refreshToken)}
+}
+
+func (_c *MockUserService_Logout_Call) Run(run func(ctx context.Context, refreshToken string)) *MockUserService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_Logout_Call) Return(err error) *MockUserService_Logout_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_Logout_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) error) *MockUserService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Refresh provides a mock function for the type MockUserService
+func (_mock *MockUserService) Refresh(ctx context.Context, refreshToken string) (model.AuthTokens, error) {
+	ret := _mock.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 model.AuthTokens
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (model.AuthTokens, error)); ok {
+		return returnFunc(ctx, refreshToken)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) model.AuthTokens); ok {
+		r0 = returnFunc(ctx, refreshToken)
+	} else {
+		r0 = ret.Get(0).(model.AuthTokens)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_Refresh_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Refresh'
+type MockUserService_Refresh_Call struct {
+	*mock.Call
+}
+
+// Refresh is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *MockUserService_Expecter) Refresh(ctx interface{}, refreshToken interface{}) *MockUserService_Refresh_Call {
+	return &MockUserService_Refresh_Call{Call: _e.mock.On("Refresh", ctx, refreshToken)}
+}
+
+func (_c *MockUserService_Refresh_Call) Run(run func(ctx context.Context, refreshToken string)) *MockUserService_Refresh_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_Refresh_Call) Return(authTokens model.AuthTokens, err error) *MockUserService_Refresh_Call {
+	_c.Call.Return(authTokens, err)
+	return _c
+}
+
+func (_c *MockUserService_Refresh_Call) RunAndReturn(run func(ctx context.Context, refreshToken string) (model.AuthTokens, error)) *MockUserService_Refresh_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequestPasswordReset provides a mock function for the type MockUserService
+func (_mock *MockUserService) RequestPasswordReset(ctx context.Context, email string) error {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_RequestPasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequestPasswordReset'
+type MockUserService_RequestPasswordReset_Call struct {
+	*mock.Call
+}
+
+// RequestPasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *MockUserService_Expecter) RequestPasswordReset(ctx interface{}, email interface{}) *MockUserService_RequestPasswordReset_Call {
+	return &MockUserService_RequestPasswordReset_Call{Call: _e.mock.On("RequestPasswordReset", ctx, email)}
+}
+
+func (_c *MockUserService_RequestPasswordReset_Call) Run(run func(ctx context.Context, email string)) *MockUserService_RequestPasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_RequestPasswordReset_Call) Return(err error) *MockUserService_RequestPasswordReset_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_RequestPasswordReset_Call) RunAndReturn(run func(ctx context.Context, email string) error) *MockUserService_RequestPasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResendVerification provides a mock function for the type MockUserService
+func (_mock *MockUserService) ResendVerification(ctx context.Context, userID int) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_ResendVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendVerification'
+type MockUserService_ResendVerification_Call struct {
+	*mock.Call
+}
+
+// ResendVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) ResendVerification(ctx interface{}, userID interface{}) *MockUserService_ResendVerification_Call {
+	return &MockUserService_ResendVerification_Call{Call: _e.mock.On("ResendVerification", ctx, userID)}
+}
+
+func (_c *MockUserService_ResendVerification_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_ResendVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_ResendVerification_Call) Return(err error) *MockUserService_ResendVerification_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_ResendVerification_Call) RunAndReturn(run func(ctx context.Context, userID int) error) *MockUserService_ResendVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) ResetPassword(ctx context.Context, req model.PasswordResetRequest) error {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.PasswordResetRequest) error); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockUserService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req model.PasswordResetRequest
+func (_e *MockUserService_Expecter) ResetPassword(ctx interface{}, req interface{}) *MockUserService_ResetPassword_Call {
+	return &MockUserService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, req)}
+}
+
+func (_c *MockUserService_ResetPassword_Call) Run(run func(ctx context.Context, req model.PasswordResetRequest)) *MockUserService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.PasswordResetRequest
+		if args[1] != nil {
+			arg1 = args[1].(model.PasswordResetRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_ResetPassword_Call) Return(err error) *MockUserService_ResetPassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, req model.PasswordResetRequest) error) *MockUserService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSessions provides a mock function for the type MockUserService
+func (_mock *MockUserService) RevokeSessions(ctx context.Context, userID int) (int64, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSessions")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int64, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int64); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_RevokeSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSessions'
+type MockUserService_RevokeSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) RevokeSessions(ctx interface{}, userID interface{}) *MockUserService_RevokeSessions_Call {
+	return &MockUserService_RevokeSessions_Call{Call: _e.mock.On("RevokeSessions", ctx, userID)}
+}
+
+func (_c *MockUserService_RevokeSessions_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_RevokeSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_RevokeSessions_Call) Return(n int64, err error) *MockUserService_RevokeSessions_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockUserService_RevokeSessions_Call) RunAndReturn(run func(ctx context.Context, userID int) (int64, error)) *MockUserService_RevokeSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRolePermissions provides a mock function for the type MockUserService
+func (_mock *MockUserService) SetRolePermissions(ctx context.Context, role string, permissions []string) error {
+	ret := _mock.Called(ctx, role, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRolePermissions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = returnFunc(ctx, role, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_SetRolePermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRolePermissions'
+type MockUserService_SetRolePermissions_Call struct {
+	*mock.Call
+}
+
+// SetRolePermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role string
+//   - permissions []string
+func (_e *MockUserService_Expecter) SetRolePermissions(ctx interface{}, role interface{}, permissions interface{}) *MockUserService_SetRolePermissions_Call {
+	return &MockUserService_SetRolePermissions_Call{Call: _e.mock.On("SetRolePermissions", ctx, role, permissions)}
+}
+
+func (_c *MockUserService_SetRolePermissions_Call) Run(run func(ctx context.Context, role string, permissions []string)) *MockUserService_SetRolePermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_SetRolePermissions_Call) Return(err error) *MockUserService_SetRolePermissions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_SetRolePermissions_Call) RunAndReturn(run func(ctx context.Context, role string, permissions []string) error) *MockUserService_SetRolePermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlinkTelegram provides a mock function for the type MockUserService
+func (_mock *MockUserService) UnlinkTelegram(ctx context.Context, userID int) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlinkTelegram")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_UnlinkTelegram_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlinkTelegram'
+type MockUserService_UnlinkTelegram_Call struct {
+	*mock.Call
+}
+
+// UnlinkTelegram is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) UnlinkTelegram(ctx interface{}, userID interface{}) *MockUserService_UnlinkTelegram_Call {
+	return &MockUserService_UnlinkTelegram_Call{Call: _e.mock.On("UnlinkTelegram", ctx, userID)}
+}
+
+func (_c *MockUserService_UnlinkTelegram_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_UnlinkTelegram_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_UnlinkTelegram_Call) Return(err error) *MockUserService_UnlinkTelegram_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_UnlinkTelegram_Call) RunAndReturn(run func(ctx context.Context, userID int) error) *MockUserService_UnlinkTelegram_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockUser provides a mock function for the type MockUserService
+func (_mock *MockUserService) UnlockUser(ctx context.Context, userID int) error {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_UnlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockUser'
+type MockUserService_UnlockUser_Call struct {
+	*mock.Call
+}
+
+// UnlockUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+func (_e *MockUserService_Expecter) UnlockUser(ctx interface{}, userID interface{}) *MockUserService_UnlockUser_Call {
+	return &MockUserService_UnlockUser_Call{Call: _e.mock.On("UnlockUser", ctx, userID)}
+}
+
+func (_c *MockUserService_UnlockUser_Call) Run(run func(ctx context.Context, userID int)) *MockUserService_UnlockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_UnlockUser_Call) Return(err error) *MockUserService_UnlockUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_UnlockUser_Call) RunAndReturn(run func(ctx context.Context, userID int) error) *MockUserService_UnlockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function for the type MockUserService
+func (_mock *MockUserService) UpdateProfile(ctx context.Context, userID int, req model.ProfileUpdate) (model.UserInResponse, error) {
+	ret := _mock.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 model.UserInResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, model.ProfileUpdate) (model.UserInResponse, error)); ok {
+		return returnFunc(ctx, userID, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, model.ProfileUpdate) model.UserInResponse); ok {
+		r0 = returnFunc(ctx, userID, req)
+	} else {
+		r0 = ret.Get(0).(model.UserInResponse)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, model.ProfileUpdate) error); ok {
+		r1 = returnFunc(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type MockUserService_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int
+//   - req model.ProfileUpdate
+func (_e *MockUserService_Expecter) UpdateProfile(ctx interface{}, userID interface{}, req interface{}) *MockUserService_UpdateProfile_Call {
+	return &MockUserService_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, userID, req)}
+}
+
+func (_c *MockUserService_UpdateProfile_Call) Run(run func(ctx context.Context, userID int, req model.ProfileUpdate)) *MockUserService_UpdateProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 model.ProfileUpdate
+		if args[2] != nil {
+			arg2 = args[2].(model.ProfileUpdate)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserService_UpdateProfile_Call) Return(userInResponse model.UserInResponse, err error) *MockUserService_UpdateProfile_Call {
+	_c.Call.Return(userInResponse, err)
+	return _c
+}
+
+func (_c *MockUserService_UpdateProfile_Call) RunAndReturn(run func(ctx context.Context, userID int, req model.ProfileUpdate) (model.UserInResponse, error)) *MockUserService_UpdateProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function for the type MockUserService
+func (_mock *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	ret := _mock.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type MockUserService_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockUserService_Expecter) VerifyEmail(ctx interface{}, token interface{}) *MockUserService_VerifyEmail_Call {
+	return &MockUserService_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, token)}
+}
+
+func (_c *MockUserService_VerifyEmail_Call) Run(run func(ctx context.Context, token string)) *MockUserService_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -288,12 +1631,12 @@ func (_c *MockUserService_Login_Call) Run(run func(ctx context.Context, req mode
 	return _c
 }
 
-func (_c *MockUserService_Login_Call) Return(s string, err error) *MockUserService_Login_Call {
-	_c.Call.Return(s, err)
+func (_c *MockUserService_VerifyEmail_Call) Return(err error) *MockUserService_VerifyEmail_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserService_Login_Call) RunAndReturn(run func(ctx context.Context, req model.UserLoginRequest) (string, error)) *MockUserService_Login_Call {
+func (_c *MockUserService_VerifyEmail_Call) RunAndReturn(run func(ctx context.Context, token string) error) *MockUserService_VerifyEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+type NotificationService interface {
+	GetList(ctx context.Context, req model.NotificationGetRequest) ([]model.NotificationInResponse, error)
+	GetCount(ctx context.Context, status string) (int, error)
+	Resend(ctx context.Context, id int) error
+	GetPreferences(ctx context.Context, userID int) ([]model.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID int, prefs []model.NotificationPreference) error
+}
+
+type notificationService struct {
+	storage *repository.Storage
+}
+
+func NewNotificationService(s *repository.Storage) NotificationService {
+	return &notificationService{storage: s}
+}
+
+func (ns *notificationService) GetList(ctx context.Context, req model.NotificationGetRequest) ([]model.NotificationInResponse, error) {
+	if err := validateNotificationStatus(req.Status); err != nil {
+		return nil, err
+	}
+
+	notificationInRepo, err := ns.storage.Outbox.GetList(ctx, req)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.NotificationService.GetList error: %v", err)
+		return nil, err
+	}
+
+	notificationInResponse := make([]model.NotificationInResponse, 0, len(notificationInRepo))
+	for _, n := range notificationInRepo {
+		notificationInResponse = append(notificationInResponse, toNotificationResponse(n))
+	}
+	return notificationInResponse, nil
+}
+
+func toNotificationResponse(n model.NotificationInRepo) model.NotificationInResponse {
+	return model.NotificationInResponse{
+		ID:            n.ID,
+		UserID:        n.UserID,
+		EventType:     n.EventType,
+		Channel:       n.Channel,
+		Recipient:     n.Recipient,
+		Subject:       n.Subject,
+		Text:          n.Text,
+		Status:        n.Status,
+		Attempts:      n.Attempts,
+		LastError:     n.LastError,
+		NextAttemptAt: n.NextAttemptAt,
+		CreatedAt:     n.CreatedAt,
+		SentAt:        n.SentAt,
+	}
+}
+
+func (ns *notificationService) GetCount(ctx context.Context, status string) (int, error) {
+	if err := validateNotificationStatus(status); err != nil {
+		return 0, err
+	}
+	return ns.storage.Outbox.GetCount(ctx, status)
+}
+
+func (ns *notificationService) Resend(ctx context.Context, id int) error {
+	err := ns.storage.Outbox.Resend(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.NotificationService.Resend error: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotificationNotFailed
+		}
+		return err
+	}
+	return nil
+}
+
+func (ns *notificationService) GetPreferences(ctx context.Context, userID int) ([]model.NotificationPreference, error) {
+	u, err := ns.storage.User.GetByID(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.NotificationService.GetPreferences error: %v", err)
+		return nil, err
+	}
+	if u.ID == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	prefs, err := ns.storage.NotificationPreference.GetByUser(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.NotificationService.GetPreferences error: %v", err)
+		return nil, err
+	}
+
+	res := make([]model.NotificationPreference, 0, len(model.NotificationTypes))
+	for _, t := range model.NotificationTypes {
+		res = append(res, model.NotificationPreference{
+			EventType: t,
+			Channels:  channelsFor(u, prefs, t),
+		})
+	}
+	return res, nil
+}
+
+// UpdatePreferences replaces the channels of every event type in prefs.
+// Event types that are not mentioned keep their current settings.
+func (ns *notificationService) UpdatePreferences(ctx context.Context, userID int, prefs []model.NotificationPreference) error {
+	for _, p := range prefs {
+		if err := validateNotificationPreference(p); err != nil {
+			return err
+		}
+	}
+
+	return ns.storage.WithTx(ctx, func(s *repository.Storage) error {
+		for _, p := range prefs {
+			for _, channel := range model.NotificationChannels {
+				err := s.NotificationPreference.Upsert(ctx, userID, p.EventType, channel, slices.Contains(p.Channels, channel))
+				if err != nil {
+					zlog.Logger.Error().Msgf("service.NotificationService.UpdatePreferences error: %v", err)
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func validateNotificationPreference(p model.NotificationPreference) error {
+	if !slices.Contains(model.NotificationTypes, p.EventType) {
+		return ErrInvalidNotificationType
+	}
+	for _, c := range p.Channels {
+		if !slices.Contains(model.NotificationChannels, c) {
+			return ErrInvalidNotificationChannel
+		}
+	}
+	return nil
+}
+
+func validateNotificationStatus(status string) error {
+	switch status {
+	case "", model.StatusNotificationPending, model.StatusNotificationSent, model.StatusNotificationFailed:
+		return nil
+	}
+	return ErrInvalidNotificationStatus
+}
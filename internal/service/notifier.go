@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EventBooker/internal/model"
+)
+
+// Notifier delivers a message over a single channel. Recipient is whatever
+// the channel addresses users by: a chat id for Telegram, an address for
+// email.
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, recipient, subject, text string) error
+}
+
+func (tg *TelegramBot) Channel() string {
+	return model.NotificationChannelTelegram
+}
+
+func (tg *TelegramBot) Notify(_ context.Context, recipient, _, text string) error {
+	chatID, err := strconv.ParseInt(recipient, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram chat id %q: %w", recipient, err)
+	}
+	return tg.Send(chatID, text)
+}
+
+// emailSendTimeout bounds one SMTP session, from dial to QUIT, so a stalled
+// server cannot hold the outbox batch past its lease.
+const emailSendTimeout = 30 * time.Second
+
+type EmailNotifier struct {
+	host    string
+	addr    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+// NewEmailNotifier sends mail through the SMTP server at host:port. Auth is
+// skipped when username is empty, which is what local relays and test stubs
+// expect.
+func NewEmailNotifier(host, port, username, password, from string) *EmailNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &EmailNotifier{host: host, addr: net.JoinHostPort(host, port), from: from, auth: auth,
+		timeout: emailSendTimeout}
+}
+
+func (en *EmailNotifier) Channel() string {
+	return model.NotificationChannelEmail
+}
+
+// Notify runs the same exchange as smtp.SendMail, but over a connection that
+// is closed when ctx is done or the send timeout passes.
+func (en *EmailNotifier) Notify(ctx context.Context, recipient, subject, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, en.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", en.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, en.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: en.host}); err != nil {
+			return err
+		}
+	}
+	if en.auth != nil {
+		if err := c.Auth(en.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(en.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(recipient); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildEmail(en.from, recipient, subject, text)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func buildEmail(from, to, subject, text string) []byte {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("From: %s\r\n", from))
+	builder.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	builder.WriteString("Content-Transfer-Encoding: base64\r\n")
+	builder.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(text))
+	for len(body) > 76 {
+		builder.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	builder.WriteString(body + "\r\n")
+	return []byte(builder.String())
+}
+
+type SentNotification struct {
+	Recipient string
+	Subject   string
+	Text      string
+}
+
+// FakeNotifier records messages in memory instead of sending them. Set Err
+// to make every Notify call fail.
+type FakeNotifier struct {
+	channel string
+	mu      sync.Mutex
+	sent    []SentNotification
+	Err     error
+}
+
+func NewFakeNotifier(channel string) *FakeNotifier {
+	return &FakeNotifier{channel: channel}
+}
+
+func (fn *FakeNotifier) Channel() string {
+	return fn.channel
+}
+
+func (fn *FakeNotifier) Notify(_ context.Context, recipient, subject, text string) error {
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+	if fn.Err != nil {
+		return fn.Err
+	}
+	fn.sent = append(fn.sent, SentNotification{Recipient: recipient, Subject: subject, Text: text})
+	return nil
+}
+
+func (fn *FakeNotifier) Sent() []SentNotification {
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+	return append([]SentNotification(nil), fn.sent...)
+}
+
+// defaultChannel is used for event types the user has not configured:
+// Telegram when a chat is linked, email otherwise.
+func defaultChannel(u model.UserInRepo) string {
+	if u.TgChatID != nil && *u.TgChatID != 0 {
+		return model.NotificationChannelTelegram
+	}
+	return model.NotificationChannelEmail
+}
+
+// channelsFor returns the channels the user chose for eventType, or the
+// default channel if there is no stored choice.
+func channelsFor(u model.UserInRepo, prefs []model.NotificationPreferenceInRepo, eventType string) []string {
+	enabled := make(map[string]bool)
+	for _, p := range prefs {
+		if p.EventType == eventType {
+			enabled[p.Channel] = p.Enabled
+		}
+	}
+	if len(enabled) == 0 {
+		return []string{defaultChannel(u)}
+	}
+
+	channels := []string{}
+	for _, c := range model.NotificationChannels {
+		if enabled[c] {
+			channels = append(channels, c)
+		}
+	}
+	return channels
+}
+
+// recipientFor returns the address of the user on channel, or false if the
+// user cannot be reached there.
+func recipientFor(u model.UserInRepo, channel string) (string, bool) {
+	switch channel {
+	case model.NotificationChannelTelegram:
+		if u.TgChatID == nil || *u.TgChatID == 0 {
+			return "", false
+		}
+		return strconv.Itoa(*u.TgChatID), true
+	case model.NotificationChannelEmail:
+		return u.Email, u.Email != ""
+	}
+	return "", false
+}
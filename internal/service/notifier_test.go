@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStub accepts a single SMTP session on a local port and reports
+// the envelope and data it received.
+func startSMTPStub(t *testing.T) (string, string, <-chan smtpMessage) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		var msg smtpMessage
+		reply("220 stub ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 stub")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 ok")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 ok")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				msg.data = data.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- msg
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	return host, port, received
+}
+
+func TestEmailNotifier_SendsThroughSMTP(t *testing.T) {
+	host, port, received := startSMTPStub(t)
+
+	notifier := NewEmailNotifier(host, port, "", "", "noreply@eventbooker.local")
+	err := notifier.Notify(context.Background(), "user@test.local", "Бронирование 5 отменено", "Событие: Концерт\n")
+	require.NoError(t, err)
+
+	msg := <-received
+	assert.Equal(t, "noreply@eventbooker.local", msg.from)
+	assert.Equal(t, []string{"user@test.local"}, msg.to)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(msg.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Бронирование 5 отменено", subject)
+
+	body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, parsed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "Событие: Концерт\n", string(body))
+}
+
+func TestEmailNotifier_ServerUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	notifier := NewEmailNotifier(host, port, "", "", "noreply@eventbooker.local")
+	err = notifier.Notify(context.Background(), "user@test.local", "subject", "text")
+	assert.Error(t, err)
+}
+
+func TestEmailNotifier_StalledServerTimesOut(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	// Accept the session but never send the greeting.
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	t.Cleanup(func() {
+		select {
+		case conn := <-accepted:
+			_ = conn.Close()
+		default:
+		}
+	})
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	notifier := NewEmailNotifier(host, port, "", "", "noreply@eventbooker.local")
+	notifier.timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	err = notifier.Notify(context.Background(), "user@test.local", "subject", "text")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	notifier.timeout = emailSendTimeout
+	assert.Error(t, notifier.Notify(ctx, "user@test.local", "subject", "text"))
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+const (
+	outboxBatchSize   = 20
+	outboxMaxAttempts = 5
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+	outboxLease       = 5 * time.Minute
+	// outboxSendWindow is how long a batch may spend sending. It is shorter
+	// than outboxLease so no message is still being sent when its lease runs
+	// out and another dispatcher claims it again.
+	outboxSendWindow = 4 * time.Minute
+)
+
+// OutboxDispatcher drains notification_outbox. Failed sends are retried with
+// exponential backoff; after outboxMaxAttempts the row is left in the failed
+// status until an admin resends it.
+type OutboxDispatcher struct {
+	storage   *repository.Storage
+	notifiers map[string]Notifier
+	channels  []string
+}
+
+// NewOutboxDispatcher only drains channels that have a notifier; messages
+// for the others stay pending until one is configured.
+func NewOutboxDispatcher(storage *repository.Storage, notifiers ...Notifier) *OutboxDispatcher {
+	d := &OutboxDispatcher{storage: storage, notifiers: make(map[string]Notifier, len(notifiers))}
+	for _, n := range notifiers {
+		d.notifiers[n.Channel()] = n
+		d.channels = append(d.channels, n.Channel())
+	}
+	return d
+}
+
+func (d *OutboxDispatcher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.Dispatch(ctx)
+			if err != nil {
+				zlog.Logger.Error().Msgf("service.OutboxDispatcher.Start error: %v", err)
+			}
+		}
+	}
+}
+
+// Dispatch sends one batch of due notifications. The batch is claimed with a
+// lease first, so concurrent dispatchers never pick the same message and no
+// row locks are held while the notifiers talk to the network. Results are
+// recorded afterwards in one transaction.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) error {
+	due, err := d.storage.Outbox.ClaimDue(ctx, outboxBatchSize, d.channels, time.Now().Add(outboxLease))
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendWindow)
+	defer cancel()
+
+	sendErrs := make([]error, len(due))
+	for i, n := range due {
+		if err := sendCtx.Err(); err != nil {
+			sendErrs[i] = err
+			continue
+		}
+		sendErrs[i] = d.notifiers[n.Channel].Notify(sendCtx, n.Recipient, n.Subject, n.Text)
+	}
+
+	return d.storage.WithTx(ctx, func(s *repository.Storage) error {
+		for i, n := range due {
+			var err error
+			sendErr := sendErrs[i]
+			if sendErr == nil {
+				err = s.Outbox.MarkSent(ctx, n.ID)
+			} else {
+				attempts := n.Attempts + 1
+				if attempts >= outboxMaxAttempts {
+					zlog.Logger.Warn().Msgf("notification %d moved to failed after %d attempts: %v", n.ID, attempts, sendErr)
+					err = s.Outbox.MarkFailed(ctx, n.ID, attempts, sendErr.Error())
+				} else {
+					err = s.Outbox.MarkRetry(ctx, n.ID, attempts, time.Now().Add(outboxBackoff(attempts)), sendErr.Error())
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff << (attempts - 1)
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
+
+// enqueueNotifications writes one outbox row per channel the user wants for
+// the message event type. Channels the user cannot be reached on are skipped.
+// Call it with a tx-scoped storage so the messages commit together with the
+// state change they describe.
+func enqueueNotifications(ctx context.Context, s *repository.Storage, msgs []model.NotificationInCreate) error {
+	for _, m := range msgs {
+		u, err := s.User.GetByID(ctx, m.UserID)
+		if err != nil {
+			return err
+		}
+		if u.ID == 0 || u.DeletedAt != nil {
+			continue
+		}
+
+		prefs, err := s.NotificationPreference.GetByUser(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+
+		channels := channelsFor(u, prefs, m.EventType)
+		if m.Channel != "" {
+			channels = []string{m.Channel}
+		}
+
+		for _, channel := range channels {
+			recipient, ok := recipientFor(u, channel)
+			if !ok {
+				continue
+			}
+			err = s.Outbox.Create(ctx, m, channel, recipient)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
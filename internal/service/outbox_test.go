@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+func TestOutboxDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at)
+		VALUES ('outbox@test.local', 'x', 'user', 42, now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	err = storage.Outbox.Create(ctx, model.NotificationInCreate{UserID: userID, Text: "hello"},
+		model.NotificationChannelTelegram, "42")
+	require.NoError(t, err)
+
+	telegram := NewFakeNotifier(model.NotificationChannelTelegram)
+	telegram.Err = errors.New("telegram is down")
+	dispatcher := NewOutboxDispatcher(storage, telegram)
+
+	for attempt := 1; attempt < outboxMaxAttempts; attempt++ {
+		require.NoError(t, dispatcher.Dispatch(ctx))
+
+		var status string
+		var attempts int
+		var nextAttemptAt time.Time
+		err = db.QueryRow(`SELECT status, attempts, next_attempt_at FROM notification_outbox`).
+			Scan(&status, &attempts, &nextAttemptAt)
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusNotificationPending, status)
+		assert.Equal(t, attempt, attempts)
+		assert.True(t, nextAttemptAt.After(time.Now()), "retry must be backed off")
+
+		require.NoError(t, dispatcher.Dispatch(ctx))
+		err = db.QueryRow(`SELECT attempts FROM notification_outbox`).Scan(&attempts)
+		require.NoError(t, err)
+		assert.Equal(t, attempt, attempts, "message must not be retried before its backoff")
+
+		_, err = db.Exec(`UPDATE notification_outbox SET next_attempt_at = now() - interval '1 second'`)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, dispatcher.Dispatch(ctx))
+	failed, err := storage.Outbox.GetList(ctx, model.NotificationGetRequest{
+		Status:   model.StatusNotificationFailed,
+		Mode:     "next",
+		PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, outboxMaxAttempts, failed[0].Attempts)
+	assert.Equal(t, "telegram is down", failed[0].LastError)
+
+	telegram.Err = nil
+	require.NoError(t, NewNotificationService(storage).Resend(ctx, failed[0].ID))
+	require.NoError(t, dispatcher.Dispatch(ctx))
+	require.Len(t, telegram.Sent(), 1)
+	assert.Equal(t, "42", telegram.Sent()[0].Recipient)
+
+	count, err := storage.Outbox.GetCount(ctx, model.StatusNotificationSent)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	err = NewNotificationService(storage).Resend(ctx, failed[0].ID)
+	assert.ErrorIs(t, err, ErrNotificationNotFailed)
+}
+
+func TestOutboxDispatcher_SkipsClaimedMessages(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at)
+		VALUES ('lease@test.local', 'x', 'user', 43, now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	err = storage.Outbox.Create(ctx, model.NotificationInCreate{UserID: userID, Text: "hello"},
+		model.NotificationChannelTelegram, "43")
+	require.NoError(t, err)
+
+	claimed, err := storage.Outbox.ClaimDue(ctx, outboxBatchSize,
+		[]string{model.NotificationChannelTelegram}, time.Now().Add(outboxLease))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	telegram := NewFakeNotifier(model.NotificationChannelTelegram)
+	dispatcher := NewOutboxDispatcher(storage, telegram)
+	require.NoError(t, dispatcher.Dispatch(ctx))
+	assert.Empty(t, telegram.Sent(), "a claimed message must not be sent twice")
+
+	_, err = db.Exec(`UPDATE notification_outbox SET next_attempt_at = now() - interval '1 second'`)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Dispatch(ctx))
+	require.Len(t, telegram.Sent(), 1, "an expired claim must be picked up again")
+}
+
+func TestEnqueueNotifications_RoutesByUserChannel(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var tgUserID, mailUserID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at)
+		VALUES ('tg@test.local', 'x', 'user', 101, now()) RETURNING user_id`).Scan(&tgUserID)
+	require.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO users (email, password, role, created_at)
+		VALUES ('mail@test.local', 'x', 'user', now()) RETURNING user_id`).Scan(&mailUserID)
+	require.NoError(t, err)
+
+	err = storage.WithTx(ctx, func(s *repository.Storage) error {
+		return enqueueNotifications(ctx, s, []model.NotificationInCreate{
+			{UserID: tgUserID, Subject: "s", Text: "to telegram"},
+			{UserID: mailUserID, Subject: "s", Text: "to email"},
+		})
+	})
+	require.NoError(t, err)
+
+	telegram := NewFakeNotifier(model.NotificationChannelTelegram)
+	email := NewFakeNotifier(model.NotificationChannelEmail)
+	require.NoError(t, NewOutboxDispatcher(storage, telegram, email).Dispatch(ctx))
+
+	require.Len(t, telegram.Sent(), 1)
+	assert.Equal(t, "101", telegram.Sent()[0].Recipient)
+	require.Len(t, email.Sent(), 1)
+	assert.Equal(t, "mail@test.local", email.Sent()[0].Recipient)
+}
+
+func TestEnqueueNotifications_CommittedWithTx(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at)
+		VALUES ('commit@test.local', 'x', 'user', now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	err = storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := enqueueNotifications(ctx, s, []model.NotificationInCreate{
+			{UserID: userID, Text: "first"},
+			{UserID: userID + 1000, Text: "unknown user"},
+		})
+		if err != nil {
+			return err
+		}
+
+		// The user lookups above must not leave the connection busy for the
+		// statements that follow them in the same transaction.
+		_, err = s.User.GetByEmail(ctx, "commit@test.local")
+		if err != nil {
+			return err
+		}
+		return enqueueNotifications(ctx, s, []model.NotificationInCreate{
+			{UserID: userID, Text: "second"},
+		})
+	})
+	require.NoError(t, err)
+
+	count, err := storage.Outbox.GetCount(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestEnqueueNotifications_RolledBackWithTx(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at)
+		VALUES ('rollback@test.local', 'x', 'user', now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	errAbort := errors.New("abort")
+	err = storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := enqueueNotifications(ctx, s, []model.NotificationInCreate{
+			{UserID: userID, Text: "rolled back"},
+		})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	count, err := storage.Outbox.GetCount(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestEnqueueNotifications_RespectsPreferences(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at)
+		VALUES ('prefs@test.local', 'x', 'user', 202, now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	ns := NewNotificationService(storage)
+	err = ns.UpdatePreferences(ctx, userID, []model.NotificationPreference{
+		{EventType: model.NotificationTypeBookingExpired, Channels: []string{}},
+		{EventType: model.NotificationTypeEventCanceled, Channels: []string{
+			model.NotificationChannelTelegram, model.NotificationChannelEmail,
+		}},
+	})
+	require.NoError(t, err)
+
+	err = storage.WithTx(ctx, func(s *repository.Storage) error {
+		return enqueueNotifications(ctx, s, []model.NotificationInCreate{
+			{UserID: userID, EventType: model.NotificationTypeBookingExpired, Text: "muted"},
+			{UserID: userID, EventType: model.NotificationTypeEventCanceled, Text: "both"},
+			{UserID: userID, EventType: model.NotificationTypeBookingConfirmed, Text: "default"},
+		})
+	})
+	require.NoError(t, err)
+
+	telegram := NewFakeNotifier(model.NotificationChannelTelegram)
+	email := NewFakeNotifier(model.NotificationChannelEmail)
+	require.NoError(t, NewOutboxDispatcher(storage, telegram, email).Dispatch(ctx))
+
+	var telegramTexts []string
+	for _, n := range telegram.Sent() {
+		telegramTexts = append(telegramTexts, n.Text)
+	}
+	assert.ElementsMatch(t, []string{"both", "default"}, telegramTexts)
+	require.Len(t, email.Sent(), 1)
+	assert.Equal(t, "both", email.Sent()[0].Text)
+
+	prefs, err := ns.GetPreferences(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, prefs, len(model.NotificationTypes))
+	for _, p := range prefs {
+		switch p.EventType {
+		case model.NotificationTypeBookingExpired:
+			assert.Empty(t, p.Channels)
+		case model.NotificationTypeBookingConfirmed:
+			assert.Equal(t, []string{model.NotificationChannelTelegram}, p.Channels)
+		}
+	}
+
+	err = ns.UpdatePreferences(ctx, userID, []model.NotificationPreference{{EventType: "birthday"}})
+	assert.ErrorIs(t, err, ErrInvalidNotificationType)
+}
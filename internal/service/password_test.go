@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestResetPassword_SingleUseAndRevokesSessions(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	session, err := us.CreateUser(ctx, model.UserInCreate{Email: "reset@test.local", Password: "secret"})
+	require.NoError(t, err)
+
+	require.NoError(t, us.RequestPasswordReset(ctx, "nobody@test.local"), "unknown emails must not be reported")
+	var tokens int
+	err = db.QueryRow(`SELECT COUNT(*) FROM password_reset_tokens`).Scan(&tokens)
+	require.NoError(t, err)
+	assert.Equal(t, 0, tokens)
+
+	require.NoError(t, us.RequestPasswordReset(ctx, "reset@test.local"))
+
+	var text string
+	err = db.QueryRow(`SELECT message FROM notification_outbox WHERE event_type=$1`,
+		model.NotificationTypePasswordReset).Scan(&text)
+	require.NoError(t, err)
+	match := regexp.MustCompile(`Код для сброса: (\S+)`).FindStringSubmatch(text)
+	require.Len(t, match, 2)
+	token := match[1]
+
+	err = us.ResetPassword(ctx, model.PasswordResetRequest{Token: token, NewPassword: "123"})
+	assert.ErrorIs(t, err, ErrWeakPassword)
+
+	require.NoError(t, us.ResetPassword(ctx, model.PasswordResetRequest{Token: token, NewPassword: "newsecret"}))
+
+	err = us.ResetPassword(ctx, model.PasswordResetRequest{Token: token, NewPassword: "another"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	_, err = us.Authenticate(ctx, session.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "reset@test.local", Password: "secret"})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "reset@test.local", Password: "newsecret"})
+	assert.NoError(t, err)
+}
+
+func TestChangePassword_KeepsOnlyNewSession(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	old, err := us.CreateUser(ctx, model.UserInCreate{Email: "change@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, old.Token)
+	require.NoError(t, err)
+
+	_, err = us.ChangePassword(ctx, claims.UserID, model.PasswordChangeRequest{OldPassword: "wrong", NewPassword: "newsecret"})
+	assert.ErrorIs(t, err, ErrWrongPassword)
+
+	fresh, err := us.ChangePassword(ctx, claims.UserID, model.PasswordChangeRequest{OldPassword: "secret", NewPassword: "newsecret"})
+	require.NoError(t, err)
+
+	_, err = us.Authenticate(ctx, old.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+	_, err = us.Authenticate(ctx, fresh.Token)
+	assert.NoError(t, err)
+}
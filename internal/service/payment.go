@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/config"
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+type PaymentService interface {
+	Pay(ctx context.Context, bookID, eventID, userID int) (model.PaymentInResponse, error)
+	HandleWebhook(ctx context.Context, provider string, header http.Header, body []byte) error
+}
+
+type paymentService struct {
+	storage   *repository.Storage
+	providers map[string]PaymentProvider
+	provider  string
+}
+
+// NewPaymentService sets up the configured provider. The other services get
+// its providers map, since cancellations have to refund through it.
+func NewPaymentService(s *repository.Storage) *paymentService {
+	c, _ := config.NewConfig()
+	ps := &paymentService{storage: s, providers: map[string]PaymentProvider{}}
+
+	switch c.Payment.Provider {
+	case "":
+		zlog.Logger.Warn().Msg("payment provider is not configured, paid bookings cannot be confirmed")
+	case model.PaymentProviderFake:
+		if c.Payment.WebhookSecret == "" {
+			zlog.Logger.Warn().Msg("payment provider fake needs PAYMENT_WEBHOOK_SECRET, payments are disabled")
+			break
+		}
+		ps.register(NewFakePaymentProvider(c.Payment.WebhookSecret))
+	default:
+		zlog.Logger.Warn().Msgf("unknown payment provider %q, payments are disabled", c.Payment.Provider)
+	}
+	return ps
+}
+
+// register adds a provider for webhooks and refunds. The first registered
+// provider is used for new payments.
+func (ps *paymentService) register(p PaymentProvider) {
+	if ps.provider == "" {
+		ps.provider = p.Name()
+	}
+	ps.providers[p.Name()] = p
+}
+
+// Pay starts a payment for a pending paid booking. The booking row is locked
+// for the whole call, so parallel requests cannot start two provider
+// payments. A payment that is still waiting for its result is returned again
+// instead of starting a second one; a booking that already has another
+// payment is rejected.
+func (ps *paymentService) Pay(ctx context.Context, bookID, eventID, userID int) (model.PaymentInResponse, error) {
+	provider, ok := ps.providers[ps.provider]
+	if !ok {
+		return model.PaymentInResponse{}, ErrPaymentUnavailable
+	}
+
+	var payment model.PaymentInRepo
+	err := ps.storage.WithTx(ctx, func(s *repository.Storage) error {
+		booking, err := s.Booking.GetByIDForUpdate(ctx, bookID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrBookingNotFound
+			}
+			return err
+		}
+
+		if booking.UserID != userID || booking.EventID != eventID {
+			return ErrBookingNotFound
+		}
+
+		if booking.Status != model.StatusBookingPending {
+			return ErrBookingNotPending
+		}
+
+		if booking.ExpiresAt.Before(time.Now()) {
+			return ErrBookingExpired
+		}
+
+		if booking.TotalPrice() == 0 {
+			return ErrPaymentNotRequired
+		}
+
+		_, err = s.Payment.GetSucceededByBooking(ctx, bookID)
+		if err == nil {
+			return ErrPaymentExists
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		payment, err = s.Payment.GetPendingByBooking(ctx, bookID)
+		if err == nil {
+			if payment.Provider != provider.Name() {
+				return ErrPaymentExists
+			}
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		amount := booking.TotalPrice()
+		intent, err := provider.CreatePayment(ctx, model.PaymentIntentRequest{
+			BookingID:   bookID,
+			Amount:      amount,
+			Currency:    booking.Currency,
+			Description: fmt.Sprintf("Бронирование %d", bookID),
+		})
+		if err != nil {
+			return err
+		}
+
+		payment, err = s.Payment.Create(ctx, model.PaymentInCreate{
+			BookingID:         bookID,
+			Provider:          provider.Name(),
+			ProviderPaymentID: intent.ProviderPaymentID,
+			Amount:            amount,
+			Currency:          booking.Currency,
+			ConfirmationURL:   intent.ConfirmationURL,
+		})
+		return err
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.PaymentService.Pay error: %v", err)
+		return model.PaymentInResponse{}, err
+	}
+	return toPaymentResponse(payment), nil
+}
+
+// HandleWebhook applies a verified payment result. Providers retry
+// deliveries, so a payment that already has a result is left as is.
+func (ps *paymentService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	provider, ok := ps.providers[providerName]
+	if !ok {
+		return ErrPaymentProviderNotFound
+	}
+
+	hook, err := provider.VerifyWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	err = ps.storage.WithTx(ctx, func(s *repository.Storage) error {
+		payment, err := s.Payment.GetByProviderIDForUpdate(ctx, providerName, hook.ProviderPaymentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPaymentNotFound
+			}
+			return err
+		}
+
+		if payment.Status != model.StatusPaymentPending {
+			return nil
+		}
+
+		if hook.Status == model.StatusPaymentSucceeded {
+			return paymentSucceeded(ctx, s, ps.providers, payment)
+		}
+		return s.Payment.UpdateStatus(ctx, payment.ID, model.StatusPaymentFailed)
+	})
+	if err != nil && !errors.Is(err, ErrPaymentNotFound) {
+		zlog.Logger.Error().Msgf("service.PaymentService.HandleWebhook error: %v", err)
+	}
+	return err
+}
+
+// paymentSucceeded confirms the booking. Money for a booking that expired or
+// was cancelled before the callback, or was already paid by another payment,
+// is returned right away. A booking past expires_at counts as expired even
+// before the expiry job gets to it, its seats may already be sold again.
+func paymentSucceeded(ctx context.Context, s *repository.Storage, providers map[string]PaymentProvider,
+	payment model.PaymentInRepo) error {
+	booking, err := s.Booking.GetByIDForUpdate(ctx, payment.BookingID)
+	if err != nil {
+		return err
+	}
+
+	if booking.Status != model.StatusBookingPending || booking.ExpiresAt.Before(time.Now()) {
+		_, err = refundPayment(ctx, s, providers, payment, 100, model.RefundReasonNotPending)
+		return err
+	}
+
+	err = s.Payment.UpdateStatus(ctx, payment.ID, model.StatusPaymentSucceeded)
+	if err != nil {
+		return err
+	}
+
+	err = s.Booking.UpdateStatus(ctx, booking.Status, model.StatusBookingConfirmed, model.BookingReasonPaymentReceived,
+		booking.ID, booking.EventID, booking.UserID)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.Booking.GetForTG(ctx, booking.ID)
+	if err != nil {
+		return err
+	}
+
+	return enqueueNotifications(ctx, s, []model.NotificationInCreate{buildBookingConfirmedMessage(b)})
+}
+
+// refundBooking returns percent of the payment the booking was confirmed
+// with. Bookings that were never paid have nothing to refund. Returns the
+// refunded amount.
+func refundBooking(ctx context.Context, s *repository.Storage, providers map[string]PaymentProvider,
+	bookingID, percent int, reason string) (int64, error) {
+	payment, err := s.Payment.GetSucceededByBookingForUpdate(ctx, bookingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return refundPayment(ctx, s, providers, payment, percent, reason)
+}
+
+// refundPayment records the refund and then sends it to the provider. The
+// provider is called last, so a failed refund rolls back the cancellation
+// that caused it, and a transaction that fails to commit after the provider
+// call is retried with the same idempotency key instead of paying twice.
+func refundPayment(ctx context.Context, s *repository.Storage, providers map[string]PaymentProvider,
+	payment model.PaymentInRepo, percent int, reason string) (int64, error) {
+	amount := payment.Amount * int64(percent) / 100
+	if amount == 0 {
+		return 0, nil
+	}
+
+	provider, ok := providers[payment.Provider]
+	if !ok {
+		return 0, ErrPaymentUnavailable
+	}
+
+	_, err := s.Refund.Create(ctx, model.RefundInCreate{
+		PaymentID:     payment.ID,
+		BookingID:     payment.BookingID,
+		Amount:        amount,
+		Currency:      payment.Currency,
+		RefundPercent: percent,
+		Reason:        reason,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	status := model.StatusPaymentRefunded
+	if amount < payment.Amount {
+		status = model.StatusPaymentPartiallyRefunded
+	}
+	err = s.Payment.UpdateStatus(ctx, payment.ID, status)
+	if err != nil {
+		return 0, err
+	}
+
+	err = provider.Refund(ctx, payment.ProviderPaymentID, amount, refundIdempotencyKey(payment.ID))
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// refundIdempotencyKey is derived from the payment, since a payment is
+// refunded at most once.
+func refundIdempotencyKey(paymentID int) string {
+	return fmt.Sprintf("refund-%d", paymentID)
+}
+
+func toPaymentResponse(p model.PaymentInRepo) model.PaymentInResponse {
+	return model.PaymentInResponse{
+		ID:                p.ID,
+		BookingID:         p.BookingID,
+		Provider:          p.Provider,
+		ProviderPaymentID: p.ProviderPaymentID,
+		Amount:            p.Amount,
+		Currency:          p.Currency,
+		Status:            p.Status,
+		ConfirmationURL:   p.ConfirmationURL,
+		CreatedAt:         p.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"sync"
+
+	"EventBooker/internal/model"
+)
+
+// PaymentProvider is an external payment system. Webhooks are the only
+// trusted source of payment results, so VerifyWebhook must reject callbacks
+// it cannot authenticate with ErrInvalidWebhook. Refund must return the money
+// at most once per idempotency key, so a retried refund is harmless.
+type PaymentProvider interface {
+	Name() string
+	CreatePayment(ctx context.Context, req model.PaymentIntentRequest) (model.PaymentIntent, error)
+	VerifyWebhook(header http.Header, body []byte) (model.PaymentWebhook, error)
+	Refund(ctx context.Context, providerPaymentID string, amount int64, idempotencyKey string) error
+}
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of the webhook body.
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakePaymentProvider accepts every payment without charging anything. The
+// result is reported by a webhook signed with the shared secret, which
+// stands in for the callback of a real provider in local development and
+// tests.
+type FakePaymentProvider struct {
+	secret  []byte
+	mu      sync.Mutex
+	refunds map[string]int64
+}
+
+type fakeWebhook struct {
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+}
+
+func NewFakePaymentProvider(secret string) *FakePaymentProvider {
+	return &FakePaymentProvider{secret: []byte(secret), refunds: map[string]int64{}}
+}
+
+func (fp *FakePaymentProvider) Name() string {
+	return model.PaymentProviderFake
+}
+
+func (fp *FakePaymentProvider) CreatePayment(_ context.Context, _ model.PaymentIntentRequest) (model.PaymentIntent, error) {
+	id, err := generateSecret()
+	if err != nil {
+		return model.PaymentIntent{}, err
+	}
+	return model.PaymentIntent{ProviderPaymentID: "fake_" + id}, nil
+}
+
+func (fp *FakePaymentProvider) VerifyWebhook(header http.Header, body []byte) (model.PaymentWebhook, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, fp.sign(body)) {
+		return model.PaymentWebhook{}, ErrInvalidWebhook
+	}
+
+	var hook fakeWebhook
+	if err := json.Unmarshal(body, &hook); err != nil || hook.PaymentID == "" {
+		return model.PaymentWebhook{}, ErrInvalidWebhook
+	}
+	if hook.Status != model.StatusPaymentSucceeded && hook.Status != model.StatusPaymentFailed {
+		return model.PaymentWebhook{}, ErrInvalidWebhook
+	}
+	return model.PaymentWebhook{ProviderPaymentID: hook.PaymentID, Status: hook.Status}, nil
+}
+
+func (fp *FakePaymentProvider) Refund(_ context.Context, _ string, amount int64, idempotencyKey string) error {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	if _, ok := fp.refunds[idempotencyKey]; !ok {
+		fp.refunds[idempotencyKey] = amount
+	}
+	return nil
+}
+
+// Refunds returns the refunded amounts by idempotency key.
+func (fp *FakePaymentProvider) Refunds() map[string]int64 {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	return maps.Clone(fp.refunds)
+}
+
+// Signature returns the header value for a webhook body.
+func (fp *FakePaymentProvider) Signature(body []byte) string {
+	return hex.EncodeToString(fp.sign(body))
+}
+
+func (fp *FakePaymentProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, fp.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+func fakeWebhookRequest(fp *FakePaymentProvider, body string) (http.Header, []byte) {
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, fp.Signature([]byte(body)))
+	return header, []byte(body)
+}
+
+// newFakePaymentService returns a payment service that takes payments with
+// the fake provider.
+func newFakePaymentService(storage *repository.Storage) (*paymentService, *FakePaymentProvider) {
+	fp := NewFakePaymentProvider("webhook-secret")
+	ps := &paymentService{storage: storage, providers: map[string]PaymentProvider{}}
+	ps.register(fp)
+	return ps, fp
+}
+
+func TestFakePaymentProvider_VerifyWebhook(t *testing.T) {
+	fp := NewFakePaymentProvider("webhook-secret")
+
+	header, body := fakeWebhookRequest(fp, `{"payment_id":"fake_1","status":"succeeded"}`)
+	hook, err := fp.VerifyWebhook(header, body)
+	require.NoError(t, err)
+	assert.Equal(t, model.PaymentWebhook{ProviderPaymentID: "fake_1", Status: model.StatusPaymentSucceeded}, hook)
+
+	_, err = fp.VerifyWebhook(header, []byte(`{"payment_id":"fake_2","status":"succeeded"}`))
+	assert.ErrorIs(t, err, ErrInvalidWebhook, "the signature covers the body")
+
+	forged, body := fakeWebhookRequest(NewFakePaymentProvider("other"), `{"payment_id":"fake_1","status":"succeeded"}`)
+	_, err = fp.VerifyWebhook(forged, body)
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+
+	_, err = fp.VerifyWebhook(http.Header{}, body)
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+
+	header, body = fakeWebhookRequest(fp, `{"payment_id":"fake_1","status":"refunded"}`)
+	_, err = fp.VerifyWebhook(header, body)
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+}
+
+func TestPayment_WebhookConfirmsBooking(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('payer@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Paid', '', $1, 'published', 10, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 150000)
+
+	ps, fp := newFakePaymentService(storage)
+	bs := NewBookingService(storage, ps.providers)
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2}))
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID}))
+
+	var paidID, lateID int
+	err = db.QueryRow(`SELECT MIN(booking_id), MAX(booking_id) FROM booking WHERE user_id=$1`, userID).
+		Scan(&paidID, &lateID)
+	require.NoError(t, err)
+
+	paid, err := storage.Booking.GetByID(ctx, paidID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusBookingPending, paid.Status, "paid bookings wait for the payment")
+	assert.ErrorIs(t, bs.Confirm(ctx, paidID, eventID, userID), ErrPaymentRequired)
+
+	payment, err := ps.Pay(ctx, paidID, eventID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300000), payment.Amount)
+	assert.Equal(t, model.StatusPaymentPending, payment.Status)
+
+	again, err := ps.Pay(ctx, paidID, eventID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, payment.ID, again.ID, "a pending payment is reused")
+
+	var providerID string
+	err = db.QueryRow(`SELECT provider_payment_id FROM payments WHERE payment_id=$1`, payment.ID).Scan(&providerID)
+	require.NoError(t, err)
+
+	body := `{"payment_id":"` + providerID + `","status":"succeeded"}`
+	forged, _ := fakeWebhookRequest(NewFakePaymentProvider("guess"), body)
+	assert.ErrorIs(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, forged, []byte(body)), ErrInvalidWebhook)
+
+	header, raw := fakeWebhookRequest(fp, body)
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw))
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw), "repeated deliveries are ignored")
+
+	paid, err = storage.Booking.GetByID(ctx, paidID)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusBookingConfirmed, paid.Status)
+	assert.Equal(t, model.BookingReasonPaymentReceived, paid.StatusReason)
+
+	late, err := ps.Pay(ctx, lateID, eventID, userID)
+	require.NoError(t, err)
+	err = db.QueryRow(`SELECT provider_payment_id FROM payments WHERE payment_id=$1`, late.ID).Scan(&providerID)
+	require.NoError(t, err)
+	require.NoError(t, bs.CancelBook(ctx, lateID, eventID, userID))
+
+	header, raw = fakeWebhookRequest(fp, `{"payment_id":"`+providerID+`","status":"succeeded"}`)
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw))
+
+	var status string
+	err = db.QueryRow(`SELECT status FROM payments WHERE payment_id=$1`, late.ID).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusPaymentRefunded, status, "money for a cancelled booking goes back")
+	assert.Equal(t, map[string]int64{refundIdempotencyKey(late.ID): 150000}, fp.Refunds())
+}
+
+func TestPay_ParallelRequestsStartOnePayment(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('twice@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Paid twice', '', $1, 'published', 10, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 150000)
+
+	ps, fp := newFakePaymentService(storage)
+	bs := NewBookingService(storage, ps.providers)
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID}))
+
+	var bookingID int
+	err = db.QueryRow(`SELECT booking_id FROM booking WHERE user_id=$1`, userID).Scan(&bookingID)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	ids := make([]int, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			payment, err := ps.Pay(ctx, bookingID, eventID, userID)
+			assert.NoError(t, err)
+			ids[i] = payment.ID
+		}()
+	}
+	wg.Wait()
+
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM payments WHERE booking_id=$1`, bookingID).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "parallel requests share one provider payment")
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+
+	var providerID string
+	err = db.QueryRow(`SELECT provider_payment_id FROM payments WHERE payment_id=$1`, ids[0]).Scan(&providerID)
+	require.NoError(t, err)
+	header, raw := fakeWebhookRequest(fp, `{"payment_id":"`+providerID+`","status":"succeeded"}`)
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw))
+	require.NoError(t, bs.CancelBook(ctx, bookingID, eventID, userID))
+
+	admin := model.Actor{UserID: userID, Permissions: []string{model.PermissionEventsManageAll}}
+	es := NewEventService(storage, ps.providers)
+	assert.ErrorIs(t, es.DeleteEvent(ctx, admin, eventID), ErrEventHasPayments,
+		"deleting the event would erase its payment history")
+}
+
+func TestFakePaymentProvider_RefundIsIdempotent(t *testing.T) {
+	fp := NewFakePaymentProvider("webhook-secret")
+	ctx := context.Background()
+
+	require.NoError(t, fp.Refund(ctx, "fake_1", 1000, refundIdempotencyKey(1)))
+	require.NoError(t, fp.Refund(ctx, "fake_1", 1000, refundIdempotencyKey(1)))
+	require.NoError(t, fp.Refund(ctx, "fake_2", 500, refundIdempotencyKey(2)))
+
+	assert.Equal(t, map[string]int64{"refund-1": 1000, "refund-2": 500}, fp.Refunds())
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+const maxNameLength = 100
+
+var (
+	phonePattern  = regexp.MustCompile(`^\+?[0-9 ()-]{5,30}$`)
+	localePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+)
+
+func (us *userService) GetProfile(ctx context.Context, userID int) (model.UserInResponse, error) {
+	user, err := us.storage.User.GetByID(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.GetProfile error: %v", err)
+		return model.UserInResponse{}, err
+	}
+	if user.ID == 0 || user.DeletedAt != nil {
+		return model.UserInResponse{}, ErrUserNotFound
+	}
+	return toUserResponse(user), nil
+}
+
+// UpdateProfile applies the present fields. A new email has to be verified
+// again, so the verification token is sent to it in the same transaction.
+func (us *userService) UpdateProfile(ctx context.Context, userID int, req model.ProfileUpdate) (model.UserInResponse, error) {
+	var user model.UserInRepo
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		var err error
+		user, err = s.User.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.ID == 0 || user.DeletedAt != nil {
+			return ErrUserNotFound
+		}
+
+		if err := applyProfileUpdate(&user, req); err != nil {
+			return err
+		}
+
+		err = s.User.UpdateProfile(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		if req.Email == nil || normalizeEmail(*req.Email) == normalizeEmail(user.Email) {
+			return nil
+		}
+		return us.changeEmail(ctx, s, &user, normalizeEmail(*req.Email), req.CurrentPassword)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.UpdateProfile error: %v", err)
+		return model.UserInResponse{}, err
+	}
+	return toUserResponse(user), nil
+}
+
+func (us *userService) changeEmail(ctx context.Context, s *repository.Storage, user *model.UserInRepo, email, password string) error {
+	if !validEmail(email) {
+		return ErrInvalidEmail
+	}
+	if !checkPassword(password, user.Password) {
+		return ErrPasswordMismatch
+	}
+
+	_, err := s.User.GetByEmail(ctx, email)
+	if err == nil {
+		return ErrEmailTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	err = s.User.ChangeEmail(ctx, user.ID, email)
+	if err != nil {
+		return err
+	}
+	user.Email = email
+	user.EmailVerifiedAt = nil
+
+	return us.sendVerification(ctx, s, *user)
+}
+
+// DeleteAccount anonymizes the account instead of removing it, so booking
+// history stays consistent. Active bookings and waitlist entries are
+// cancelled, paid bookings are refunded in full and the released seats go to
+// the waitlists. Admins have to hand over the role first, so the last admin
+// cannot disappear.
+func (us *userService) DeleteAccount(ctx context.Context, userID int, password string) error {
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		user, err := s.User.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.ID == 0 || user.DeletedAt != nil {
+			return ErrUserNotFound
+		}
+		if user.Role == model.RoleAdmin {
+			return ErrAdminAccountDelete
+		}
+		if !checkPassword(password, user.Password) {
+			return ErrPasswordMismatch
+		}
+
+		cancelled, err := s.Booking.CancelByUser(ctx, userID, model.BookingReasonAccountDeleted)
+		if err != nil {
+			return err
+		}
+
+		err = s.PromoCode.Release(ctx, bookingIDs(cancelled))
+		if err != nil {
+			return err
+		}
+
+		var eventIDs []int
+		for _, b := range cancelled {
+			_, err = refundBooking(ctx, s, us.providers, b.ID, 100, model.RefundReasonAccountDeleted)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(eventIDs, b.EventID) {
+				eventIDs = append(eventIDs, b.EventID)
+			}
+		}
+
+		err = s.Waitlist.CancelByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Session.RevokeAllByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		for _, deleteByUser := range []func(context.Context, int) error{
+			s.TgLinkCode.DeleteByUser,
+			s.PasswordReset.DeleteByUser,
+			s.EmailVerification.DeleteByUser,
+			s.Outbox.DeleteByUser,
+			s.DataExport.DeleteByUser,
+		} {
+			err = deleteByUser(ctx, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.User.Anonymize(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		for _, eventID := range eventIDs {
+			err = promoteWaitlist(ctx, s, eventID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.DeleteAccount error: %v", err)
+		return err
+	}
+	return nil
+}
+
+func applyProfileUpdate(user *model.UserInRepo, req model.ProfileUpdate) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if utf8.RuneCountInString(name) > maxNameLength {
+			return ErrInvalidName
+		}
+		user.Name = optionalString(name)
+	}
+
+	if req.Phone != nil {
+		phone := strings.TrimSpace(*req.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return ErrInvalidPhone
+		}
+		user.Phone = optionalString(phone)
+	}
+
+	if req.Locale != nil {
+		locale := strings.TrimSpace(*req.Locale)
+		if locale != "" && !localePattern.MatchString(locale) {
+			return ErrInvalidLocale
+		}
+		user.Locale = optionalString(locale)
+	}
+
+	return nil
+}
+
+// optionalString stores an empty value as NULL.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
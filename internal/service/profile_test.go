@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestApplyProfileUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		req  model.ProfileUpdate
+		err  error
+	}{
+		{"name", model.ProfileUpdate{Name: str("  Ivan  ")}, nil},
+		{"long name", model.ProfileUpdate{Name: str(strings.Repeat("я", maxNameLength+1))}, ErrInvalidName},
+		{"phone", model.ProfileUpdate{Phone: str("+7 (900) 123-45-67")}, nil},
+		{"bad phone", model.ProfileUpdate{Phone: str("call me")}, ErrInvalidPhone},
+		{"locale", model.ProfileUpdate{Locale: str("en-US")}, nil},
+		{"bad locale", model.ProfileUpdate{Locale: str("english")}, ErrInvalidLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user model.UserInRepo
+			assert.ErrorIs(t, applyProfileUpdate(&user, tt.req), tt.err)
+		})
+	}
+
+	user := model.UserInRepo{Name: str("Ivan"), Phone: str("+79001234567")}
+	require.NoError(t, applyProfileUpdate(&user, model.ProfileUpdate{Name: str("")}))
+	assert.Nil(t, user.Name, "an empty string clears the field")
+	assert.Equal(t, "+79001234567", *user.Phone, "absent fields stay untouched")
+}
+
+func TestUpdateProfile_EmailChangeNeedsVerification(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "profile@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, tokens.Token)
+	require.NoError(t, err)
+	_, err = us.CreateUser(ctx, model.UserInCreate{Email: "taken@test.local", Password: "secret"})
+	require.NoError(t, err)
+
+	email := "taken@test.local"
+	_, err = us.UpdateProfile(ctx, claims.UserID, model.ProfileUpdate{Email: &email, CurrentPassword: "wrong"})
+	assert.ErrorIs(t, err, ErrPasswordMismatch)
+	_, err = us.UpdateProfile(ctx, claims.UserID, model.ProfileUpdate{Email: &email, CurrentPassword: "secret"})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	email, name := "renamed@test.local", "Ivan"
+	profile, err := us.UpdateProfile(ctx, claims.UserID, model.ProfileUpdate{
+		Email:           &email,
+		CurrentPassword: "secret",
+		Name:            &name,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, email, profile.Email)
+	assert.Equal(t, name, *profile.Name)
+	assert.False(t, profile.EmailVerified)
+
+	var recipient string
+	err = db.QueryRow(`SELECT recipient FROM notification_outbox WHERE event_type=$1
+		ORDER BY notification_id DESC LIMIT 1`, model.NotificationTypeEmailVerification).Scan(&recipient)
+	require.NoError(t, err)
+	assert.Equal(t, email, recipient, "the new address must get a verification code")
+
+	profile, err = us.GetProfile(ctx, claims.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, email, profile.Email)
+}
+
+func TestDeleteAccount_AnonymizesAndKeepsBookings(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	hash, err := hashPassword("secret")
+	require.NoError(t, err)
+	var userID int
+	err = db.QueryRow(`INSERT INTO users (email, password, role, tg_chatid, created_at, email_verified_at)
+		VALUES ('gone@test.local', $1, 'user', 555, now(), now()) RETURNING user_id`, hash).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Delete test', '', $1, 'published', 10, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 0)
+	require.NoError(t, NewBookingService(storage, nil).Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, Quantity: 2}))
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	assert.ErrorIs(t, us.DeleteAccount(ctx, userID, "wrong"), ErrPasswordMismatch)
+	require.NoError(t, us.DeleteAccount(ctx, userID, "secret"))
+
+	var status, reason string
+	err = db.QueryRow(`SELECT status, status_reason FROM booking WHERE user_id=$1`, userID).Scan(&status, &reason)
+	require.NoError(t, err, "booking history survives the deletion")
+	assert.Equal(t, model.StatusBookingCanceled, status)
+	assert.Equal(t, model.BookingReasonAccountDeleted, reason)
+
+	user, err := storage.User.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.NotNil(t, user.DeletedAt)
+	assert.NotContains(t, user.Email, "gone@")
+	assert.Nil(t, user.TgChatID)
+
+	_, err = us.GetProfile(ctx, userID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "gone@test.local", Password: "secret"})
+	assert.Error(t, err)
+	assert.ErrorIs(t, us.DeleteAccount(ctx, userID, "secret"), ErrUserNotFound)
+}
+
+func TestDeleteAccount_RefundsPaidBookings(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	hash, err := hashPassword("secret")
+	require.NoError(t, err)
+	var userID int
+	err = db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('paid-gone@test.local', $1, 'user', now(), now()) RETURNING user_id`, hash).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Paid delete test', '', $1, 'published', 10, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 50000)
+
+	ps, fp := newFakePaymentService(storage)
+	require.NoError(t, NewBookingService(storage, ps.providers).Book(ctx, model.BookingInCreate{
+		UserID: userID, EventID: eventID, Quantity: 2,
+	}))
+	var bookID int
+	err = db.QueryRow(`SELECT booking_id FROM booking WHERE user_id=$1`, userID).Scan(&bookID)
+	require.NoError(t, err)
+
+	payment, err := ps.Pay(ctx, bookID, eventID, userID)
+	require.NoError(t, err)
+	header, raw := fakeWebhookRequest(fp, `{"payment_id":"`+payment.ProviderPaymentID+`","status":"succeeded"}`)
+	require.NoError(t, ps.HandleWebhook(ctx, model.PaymentProviderFake, header, raw))
+
+	us := &userService{storage: storage, providers: ps.providers, limiter: NewMemoryLoginLimiter()}
+	require.NoError(t, us.DeleteAccount(ctx, userID, "secret"))
+
+	var amount int64
+	var reason string
+	err = db.QueryRow(`SELECT amount, reason FROM refunds WHERE booking_id=$1`, bookID).Scan(&amount, &reason)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100000), amount)
+	assert.Equal(t, model.RefundReasonAccountDeleted, reason)
+	assert.Equal(t, map[string]int64{refundIdempotencyKey(payment.ID): 100000}, fp.Refunds())
+
+	var status string
+	err = db.QueryRow(`SELECT status FROM payments WHERE payment_id=$1`, payment.ID).Scan(&status)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusPaymentRefunded, status)
+}
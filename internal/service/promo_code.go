@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+type PromoCodeService interface {
+	Create(ctx context.Context, p model.PromoCodeInput) (model.PromoCodeInResponse, error)
+	GetByID(ctx context.Context, id int) (model.PromoCodeInResponse, error)
+	GetList(ctx context.Context) ([]model.PromoCodeInResponse, error)
+	Update(ctx context.Context, id int, p model.PromoCodeInput) (model.PromoCodeInResponse, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type promoCodeService struct {
+	storage *repository.Storage
+}
+
+func NewPromoCodeService(s *repository.Storage) PromoCodeService {
+	return &promoCodeService{storage: s}
+}
+
+func (ps *promoCodeService) Create(ctx context.Context, p model.PromoCodeInput) (model.PromoCodeInResponse, error) {
+	if err := normalizePromoCode(&p); err != nil {
+		return model.PromoCodeInResponse{}, err
+	}
+
+	var promo model.PromoCodeInRepo
+	err := ps.storage.WithTx(ctx, func(s *repository.Storage) error {
+		_, err := s.PromoCode.GetByCode(ctx, p.Code)
+		if err == nil {
+			return ErrPromoCodeExists
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		err = checkPromoTargets(ctx, s, p)
+		if err != nil {
+			return err
+		}
+
+		id, err := s.PromoCode.Create(ctx, p)
+		if err != nil {
+			return err
+		}
+		promo, err = s.PromoCode.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		if !isPromoValidationError(err) {
+			zlog.Logger.Error().Msgf("service.PromoCodeService.Create error: %v", err)
+		}
+		return model.PromoCodeInResponse{}, err
+	}
+	return toPromoCodeResponse(promo), nil
+}
+
+func (ps *promoCodeService) GetByID(ctx context.Context, id int) (model.PromoCodeInResponse, error) {
+	promo, err := ps.storage.PromoCode.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.PromoCodeInResponse{}, ErrPromoCodeNotFound
+		}
+		zlog.Logger.Error().Msgf("service.PromoCodeService.GetByID error: %v", err)
+		return model.PromoCodeInResponse{}, err
+	}
+	return toPromoCodeResponse(promo), nil
+}
+
+func (ps *promoCodeService) GetList(ctx context.Context) ([]model.PromoCodeInResponse, error) {
+	codes, err := ps.storage.PromoCode.GetList(ctx)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.PromoCodeService.GetList error: %v", err)
+		return nil, err
+	}
+
+	res := make([]model.PromoCodeInResponse, 0, len(codes))
+	for _, p := range codes {
+		res = append(res, toPromoCodeResponse(p))
+	}
+	return res, nil
+}
+
+// Update replaces the settings of the code. Redemptions already made keep
+// their discount. The code row is locked as in redeemPromoCode, so max_uses
+// is checked against every redemption that can still commit.
+func (ps *promoCodeService) Update(ctx context.Context, id int, p model.PromoCodeInput) (model.PromoCodeInResponse, error) {
+	if err := normalizePromoCode(&p); err != nil {
+		return model.PromoCodeInResponse{}, err
+	}
+
+	var promo model.PromoCodeInRepo
+	err := ps.storage.WithTx(ctx, func(s *repository.Storage) error {
+		current, err := s.PromoCode.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPromoCodeNotFound
+			}
+			return err
+		}
+
+		if p.Code != current.Code {
+			_, err = s.PromoCode.GetByCode(ctx, p.Code)
+			if err == nil {
+				return ErrPromoCodeExists
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+
+		if p.MaxUses != nil && *p.MaxUses < current.UsedCount {
+			return ErrPromoLimitBelowUsed
+		}
+
+		err = checkPromoTargets(ctx, s, p)
+		if err != nil {
+			return err
+		}
+
+		err = s.PromoCode.Update(ctx, id, p)
+		if err != nil {
+			return err
+		}
+		promo, err = s.PromoCode.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		if !isPromoValidationError(err) {
+			zlog.Logger.Error().Msgf("service.PromoCodeService.Update error: %v", err)
+		}
+		return model.PromoCodeInResponse{}, err
+	}
+	return toPromoCodeResponse(promo), nil
+}
+
+// Delete removes a code nobody has used yet. Used codes stay for the booking
+// history, they can be switched off with valid_until instead.
+func (ps *promoCodeService) Delete(ctx context.Context, id int) error {
+	return ps.storage.WithTx(ctx, func(s *repository.Storage) error {
+		promo, err := s.PromoCode.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPromoCodeNotFound
+			}
+			zlog.Logger.Error().Msgf("service.PromoCodeService.Delete error: %v", err)
+			return err
+		}
+
+		if promo.UsedCount > 0 {
+			return ErrPromoCodeInUse
+		}
+
+		err = s.PromoCode.Delete(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.PromoCodeService.Delete error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// normalizePromoCode validates the code settings in place. Codes are
+// case-insensitive and stored upper-cased.
+func normalizePromoCode(p *model.PromoCodeInput) error {
+	p.Code = normalizeCode(p.Code)
+	if !promoCodePattern.MatchString(p.Code) {
+		return ErrInvalidPromoCode
+	}
+
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	switch p.DiscountType {
+	case model.PromoDiscountPercent:
+		if p.DiscountValue < 1 || p.DiscountValue > 100 {
+			return ErrInvalidDiscount
+		}
+		p.Currency = ""
+	case model.PromoDiscountFixed:
+		if p.DiscountValue <= 0 {
+			return ErrInvalidDiscount
+		}
+		if p.Currency == "" {
+			p.Currency = model.DefaultCurrency
+		}
+		if !currencyPattern.MatchString(p.Currency) {
+			return ErrInvalidCurrency
+		}
+	default:
+		return ErrInvalidDiscount
+	}
+
+	if (p.MaxUses != nil && *p.MaxUses <= 0) || (p.MaxUsesPerUser != nil && *p.MaxUsesPerUser <= 0) {
+		return ErrInvalidPromoLimits
+	}
+
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidUntil.After(*p.ValidFrom) {
+		return ErrInvalidPromoPeriod
+	}
+
+	if p.EventIDs == nil {
+		p.EventIDs = []int{}
+	}
+	if p.TicketTypeIDs == nil {
+		p.TicketTypeIDs = []int{}
+	}
+	slices.Sort(p.EventIDs)
+	p.EventIDs = slices.Compact(p.EventIDs)
+	slices.Sort(p.TicketTypeIDs)
+	p.TicketTypeIDs = slices.Compact(p.TicketTypeIDs)
+	return nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func checkPromoTargets(ctx context.Context, s *repository.Storage, p model.PromoCodeInput) error {
+	for _, eventID := range p.EventIDs {
+		event, err := s.Event.GetByID(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if event.ID == 0 {
+			return ErrEventNotFound
+		}
+	}
+
+	for _, ticketTypeID := range p.TicketTypeIDs {
+		_, err := s.TicketType.GetByID(ctx, ticketTypeID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTicketTypeNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPromoValidationError(err error) bool {
+	return errors.Is(err, ErrPromoCodeNotFound) || errors.Is(err, ErrPromoCodeExists) ||
+		errors.Is(err, ErrPromoLimitBelowUsed) || errors.Is(err, ErrEventNotFound) ||
+		errors.Is(err, ErrTicketTypeNotFound)
+}
+
+func isPromoRedeemError(err error) bool {
+	return errors.Is(err, ErrPromoCodeNotFound) || errors.Is(err, ErrPromoCodeInactive) ||
+		errors.Is(err, ErrPromoCodeExhausted) || errors.Is(err, ErrPromoCodeNotApplicable)
+}
+
+// redeemPromoCode checks the code for the booking and returns it with the
+// discount. The code row stays locked until the booking transaction ends, so
+// concurrent bookings cannot go over the usage limits.
+func redeemPromoCode(ctx context.Context, s *repository.Storage, code string, b model.BookingInCreate, now time.Time) (model.PromoCodeInRepo, int64, error) {
+	promo, err := s.PromoCode.GetByCodeForUpdate(ctx, normalizeCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.PromoCodeInRepo{}, 0, ErrPromoCodeNotFound
+		}
+		return model.PromoCodeInRepo{}, 0, err
+	}
+
+	if (promo.ValidFrom != nil && now.Before(*promo.ValidFrom)) ||
+		(promo.ValidUntil != nil && !now.Before(*promo.ValidUntil)) {
+		return model.PromoCodeInRepo{}, 0, ErrPromoCodeInactive
+	}
+
+	if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+		return model.PromoCodeInRepo{}, 0, ErrPromoCodeExhausted
+	}
+
+	if promo.MaxUsesPerUser != nil {
+		used, err := s.PromoCode.CountUserRedemptions(ctx, promo.ID, b.UserID)
+		if err != nil {
+			return model.PromoCodeInRepo{}, 0, err
+		}
+		if used >= *promo.MaxUsesPerUser {
+			return model.PromoCodeInRepo{}, 0, ErrPromoCodeExhausted
+		}
+	}
+
+	discount, err := promoDiscount(promo, b)
+	if err != nil {
+		return model.PromoCodeInRepo{}, 0, err
+	}
+	return promo, discount, nil
+}
+
+// promoDiscount returns the amount the code takes off the booking total.
+// A fixed discount applies once per booking and never exceeds the total.
+func promoDiscount(promo model.PromoCodeInRepo, b model.BookingInCreate) (int64, error) {
+	if len(promo.EventIDs) > 0 && !slices.Contains(promo.EventIDs, b.EventID) {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if len(promo.TicketTypeIDs) > 0 && !slices.Contains(promo.TicketTypeIDs, b.TicketTypeID) {
+		return 0, ErrPromoCodeNotApplicable
+	}
+
+	total := b.UnitPrice * int64(b.Quantity)
+	if total == 0 {
+		return 0, ErrPromoCodeNotApplicable
+	}
+
+	if promo.DiscountType == model.PromoDiscountPercent {
+		discount := total * promo.DiscountValue / 100
+		if discount == 0 {
+			return 0, ErrPromoCodeNotApplicable
+		}
+		return discount, nil
+	}
+
+	if promo.Currency != b.Currency {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	return min(promo.DiscountValue, total), nil
+}
+
+func toPromoCodeResponse(p model.PromoCodeInRepo) model.PromoCodeInResponse {
+	return model.PromoCodeInResponse{
+		ID:             p.ID,
+		Code:           p.Code,
+		DiscountType:   p.DiscountType,
+		DiscountValue:  p.DiscountValue,
+		Currency:       p.Currency,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		ValidFrom:      p.ValidFrom,
+		ValidUntil:     p.ValidUntil,
+		UsedCount:      p.UsedCount,
+		EventIDs:       p.EventIDs,
+		TicketTypeIDs:  p.TicketTypeIDs,
+		CreatedAt:      p.CreatedAt,
+	}
+}
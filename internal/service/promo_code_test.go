@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestNormalizePromoCode(t *testing.T) {
+	zero := 0
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		promo model.PromoCodeInput
+		err   error
+	}{
+		{"percent", model.PromoCodeInput{Code: "summer-10", DiscountType: "percent", DiscountValue: 10}, nil},
+		{"fixed", model.PromoCodeInput{Code: "MINUS500", DiscountType: "fixed", DiscountValue: 50000}, nil},
+		{"short code", model.PromoCodeInput{Code: "ab", DiscountType: "percent", DiscountValue: 10}, ErrInvalidPromoCode},
+		{"code with spaces", model.PromoCodeInput{Code: "two words", DiscountType: "percent", DiscountValue: 10},
+			ErrInvalidPromoCode},
+		{"percent over 100", model.PromoCodeInput{Code: "MAX", DiscountType: "percent", DiscountValue: 101},
+			ErrInvalidDiscount},
+		{"zero fixed", model.PromoCodeInput{Code: "ZERO", DiscountType: "fixed"}, ErrInvalidDiscount},
+		{"unknown type", model.PromoCodeInput{Code: "FREE", DiscountType: "gift", DiscountValue: 1}, ErrInvalidDiscount},
+		{"bad currency", model.PromoCodeInput{Code: "USD5", DiscountType: "fixed", DiscountValue: 500, Currency: "dollars"},
+			ErrInvalidCurrency},
+		{"zero limit", model.PromoCodeInput{Code: "ONCE", DiscountType: "percent", DiscountValue: 10, MaxUses: &zero},
+			ErrInvalidPromoLimits},
+		{"period reversed", model.PromoCodeInput{Code: "LATE", DiscountType: "percent", DiscountValue: 10,
+			ValidFrom: &now, ValidUntil: &earlier}, ErrInvalidPromoPeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, normalizePromoCode(&tt.promo), tt.err)
+		})
+	}
+
+	p := model.PromoCodeInput{Code: " spring ", DiscountType: "fixed", DiscountValue: 100, Currency: "eur",
+		EventIDs: []int{3, 1, 3}}
+	require.NoError(t, normalizePromoCode(&p))
+	assert.Equal(t, "SPRING", p.Code)
+	assert.Equal(t, "EUR", p.Currency)
+	assert.Equal(t, []int{1, 3}, p.EventIDs)
+	assert.Equal(t, []int{}, p.TicketTypeIDs)
+}
+
+func TestPromoDiscount(t *testing.T) {
+	booking := model.BookingInCreate{EventID: 1, TicketTypeID: 10, Quantity: 2, UnitPrice: 100000, Currency: "RUB"}
+	percent := func(v int64) model.PromoCodeInRepo {
+		return model.PromoCodeInRepo{DiscountType: model.PromoDiscountPercent, DiscountValue: v}
+	}
+	fixed := func(v int64, currency string) model.PromoCodeInRepo {
+		return model.PromoCodeInRepo{DiscountType: model.PromoDiscountFixed, DiscountValue: v, Currency: currency}
+	}
+	onlyEvent := percent(10)
+	onlyEvent.EventIDs = []int{2}
+	onlyTier := percent(10)
+	onlyTier.TicketTypeIDs = []int{10, 11}
+	free := booking
+	free.UnitPrice = 0
+
+	tests := []struct {
+		name     string
+		promo    model.PromoCodeInRepo
+		booking  model.BookingInCreate
+		discount int64
+		err      error
+	}{
+		{"percent of total", percent(15), booking, 30000, nil},
+		{"full percent", percent(100), booking, 200000, nil},
+		{"fixed", fixed(50000, "RUB"), booking, 50000, nil},
+		{"fixed above total", fixed(500000, "RUB"), booking, 200000, nil},
+		{"fixed in other currency", fixed(500, "USD"), booking, 0, ErrPromoCodeNotApplicable},
+		{"other event", onlyEvent, booking, 0, ErrPromoCodeNotApplicable},
+		{"listed tier", onlyTier, booking, 20000, nil},
+		{"free ticket", percent(10), free, 0, ErrPromoCodeNotApplicable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discount, err := promoDiscount(tt.promo, tt.booking)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.discount, discount)
+		})
+	}
+}
+
+func TestBook_PromoCodeLimitsUnderConcurrency(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	const (
+		maxUses  = 5
+		requests = 40
+	)
+
+	var eventID int
+	err := db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Promo', '', $1, 'published', 100, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 100, 100000)
+
+	userIDs := make([]int, requests)
+	for i := range userIDs {
+		err = db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+			VALUES ($1, 'x', 'user', now(), now()) RETURNING user_id`, fmt.Sprintf("promo%d@test.local", i)).
+			Scan(&userIDs[i])
+		require.NoError(t, err)
+	}
+
+	limit, perUser := maxUses, 1
+	ps := NewPromoCodeService(storage)
+	promo, err := ps.Create(ctx, model.PromoCodeInput{Code: "launch", DiscountType: model.PromoDiscountPercent,
+		DiscountValue: 20, MaxUses: &limit, MaxUsesPerUser: &perUser, EventIDs: []int{eventID}})
+	require.NoError(t, err)
+
+	bs := NewBookingService(storage, nil)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		redeemed  int
+		exhausted int
+		failures  []error
+	)
+	start := make(chan struct{})
+	for _, userID := range userIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, PromoCode: "LAUNCH"})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				redeemed++
+			case errors.Is(err, ErrPromoCodeExhausted):
+				exhausted++
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Empty(t, failures)
+	assert.Equal(t, maxUses, redeemed)
+	assert.Equal(t, requests-maxUses, exhausted)
+
+	promo, err = ps.GetByID(ctx, promo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, maxUses, promo.UsedCount)
+
+	var discounted int
+	err = db.QueryRow(`SELECT COUNT(*) FROM booking WHERE event_id=$1 AND discount=20000`, eventID).Scan(&discounted)
+	require.NoError(t, err)
+	assert.Equal(t, maxUses, discounted, "failed redemptions leave no booking behind")
+
+	assert.ErrorIs(t, ps.Delete(ctx, promo.ID), ErrPromoCodeInUse)
+
+	limit = maxUses + 1
+	_, err = ps.Update(ctx, promo.ID, model.PromoCodeInput{Code: "LAUNCH", DiscountType: model.PromoDiscountPercent,
+		DiscountValue: 20, MaxUses: &limit, MaxUsesPerUser: &perUser})
+	require.NoError(t, err)
+
+	var userID int
+	err = db.QueryRow(`SELECT user_id FROM promo_redemptions WHERE promo_code_id=$1 LIMIT 1`, promo.ID).Scan(&userID)
+	require.NoError(t, err)
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, PromoCode: "launch"})
+	assert.ErrorIs(t, err, ErrPromoCodeExhausted, "one use per user")
+}
+
+func TestPromoCode_ReleasedWhenBookingEnds(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('release@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Release', '', $1, 'published', 10, $2, false, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 100000)
+
+	limit := 1
+	ps := NewPromoCodeService(storage)
+	promo, err := ps.Create(ctx, model.PromoCodeInput{Code: "once", DiscountType: model.PromoDiscountPercent,
+		DiscountValue: 10, MaxUses: &limit, MaxUsesPerUser: &limit})
+	require.NoError(t, err)
+
+	bs := NewBookingService(storage, nil)
+	book := func() int {
+		t.Helper()
+		require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, PromoCode: "once"}))
+		var bookID int
+		err := db.QueryRow(`SELECT MAX(booking_id) FROM booking WHERE user_id=$1`, userID).Scan(&bookID)
+		require.NoError(t, err)
+		return bookID
+	}
+	usedCount := func() int {
+		t.Helper()
+		p, err := ps.GetByID(ctx, promo.ID)
+		require.NoError(t, err)
+		return p.UsedCount
+	}
+
+	bookID := book()
+	assert.Equal(t, 1, usedCount())
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, PromoCode: "once"})
+	assert.ErrorIs(t, err, ErrPromoCodeExhausted)
+
+	require.NoError(t, bs.CancelBook(ctx, bookID, eventID, userID))
+	assert.Equal(t, 0, usedCount(), "a cancelled booking gives the use back")
+
+	bookID = book()
+	_, err = db.Exec(`UPDATE booking SET expires_at = now() - interval '1 second' WHERE booking_id=$1`, bookID)
+	require.NoError(t, err)
+	scheduler := NewSchedulerService(storage, NewWaitlistService(storage))
+	_, err = scheduler.expireBooking(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, usedCount(), "an expired booking gives the use back")
+
+	book()
+	admin := model.Actor{UserID: userID, Permissions: []string{model.PermissionEventsManageAll}}
+	require.NoError(t, NewEventService(storage, nil).CancelEvent(ctx, admin, eventID))
+	assert.Equal(t, 0, usedCount(), "a cancelled event gives the uses back")
+
+	var redemptions int
+	err = db.QueryRow(`SELECT COUNT(*) FROM promo_redemptions`).Scan(&redemptions)
+	require.NoError(t, err)
+	assert.Equal(t, 0, redemptions)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestCheckEventOwner(t *testing.T) {
+	owner := 7
+	owned := model.EventInRepo{OrganizerID: &owner}
+	legacy := model.EventInRepo{}
+	manager := model.Actor{UserID: 1, Permissions: []string{model.PermissionEventsWrite, model.PermissionEventsManageAll}}
+	organizer := model.Actor{UserID: 7, Permissions: []string{model.PermissionEventsWrite}}
+	other := model.Actor{UserID: 8, Permissions: []string{model.PermissionEventsWrite}}
+
+	assert.NoError(t, checkEventOwner(manager, owned))
+	assert.NoError(t, checkEventOwner(manager, legacy))
+	assert.NoError(t, checkEventOwner(organizer, owned))
+	assert.ErrorIs(t, checkEventOwner(other, owned), ErrEventForbidden)
+	assert.ErrorIs(t, checkEventOwner(organizer, legacy), ErrEventForbidden)
+}
+
+func TestSetRolePermissions_Validation(t *testing.T) {
+	us := &userService{}
+	ctx := context.Background()
+
+	assert.ErrorIs(t, us.SetRolePermissions(ctx, "owner", nil), ErrInvalidRole)
+	assert.ErrorIs(t, us.SetRolePermissions(ctx, model.RoleOrganizer, []string{"events:delete"}), ErrInvalidPermission)
+	assert.ErrorIs(t, us.SetRolePermissions(ctx, model.RoleAdmin, []string{model.PermissionUsersRead}),
+		ErrAdminPermissionRequired)
+}
+
+func TestOrganizerManagesOnlyOwnEvents(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var ownerID, otherID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('owner@test.local', 'x', 'organizer', now(), now()) RETURNING user_id`).Scan(&ownerID)
+	require.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('other@test.local', 'x', 'organizer', now(), now()) RETURNING user_id`).Scan(&otherID)
+	require.NoError(t, err)
+
+	owner := model.Actor{UserID: ownerID, Role: model.RoleOrganizer, Permissions: []string{model.PermissionEventsWrite}}
+	other := model.Actor{UserID: otherID, Role: model.RoleOrganizer, Permissions: []string{model.PermissionEventsWrite}}
+
+	es := NewEventService(storage, nil)
+	err = es.CreateEvent(ctx, owner, model.EventInCreate{
+		Title:             "Owned",
+		EventDate:         time.Now().Add(48 * time.Hour),
+		TotalPlace:        10,
+		ReservationPeriod: "10m",
+		Draft:             true,
+	})
+	require.NoError(t, err)
+
+	events, err := es.GetListEvents(ctx, model.EventGetRequest{
+		LastCreatedAt: time.Now().Add(time.Hour),
+		LastID:        1 << 30,
+		Mode:          "prev",
+		PageSize:      10,
+		IncludeDrafts: true,
+		OrganizerID:   otherID,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, events, "an organizer must not list events of others")
+
+	var eventID int
+	err = db.QueryRow(`SELECT event_id FROM events WHERE title='Owned'`).Scan(&eventID)
+	require.NoError(t, err)
+
+	title := "Taken over"
+	err = es.UpdateEvent(ctx, other, eventID, model.EventInUpdate{Title: &title})
+	assert.ErrorIs(t, err, ErrEventForbidden)
+	err = es.DeleteEvent(ctx, other, eventID)
+	assert.ErrorIs(t, err, ErrEventForbidden)
+
+	err = es.ChangeStatus(ctx, owner, eventID, model.EventStatusPublished)
+	require.NoError(t, err)
+	manager := model.Actor{UserID: otherID, Permissions: []string{model.PermissionEventsManageAll}}
+	err = es.CancelEvent(ctx, manager, eventID)
+	require.NoError(t, err, "events:manage_all covers every event")
+}
+
+func TestChangeRole_RevokesSessions(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	created, err := us.EnsureAdmin(ctx, "root@test.local", "secret")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "promoted@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, tokens.Token)
+	require.NoError(t, err)
+
+	admin, err := storage.User.GetByEmail(ctx, "root@test.local")
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, admin.Role)
+	assert.NotNil(t, admin.EmailVerifiedAt)
+
+	assert.ErrorIs(t, us.ChangeRole(ctx, admin.ID, admin.ID, model.RoleUser), ErrOwnRole)
+	assert.ErrorIs(t, us.ChangeRole(ctx, admin.ID, claims.UserID, "owner"), ErrInvalidRole)
+	assert.ErrorIs(t, us.ChangeRole(ctx, admin.ID, claims.UserID+100, model.RoleUser), ErrUserNotFound)
+
+	require.NoError(t, us.ChangeRole(ctx, admin.ID, claims.UserID, model.RoleOrganizer))
+	_, err = us.Authenticate(ctx, tokens.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked, "tokens issued with the old role must stop working")
+
+	relogin, err := us.Login(ctx, model.UserLoginRequest{Email: "promoted@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err = us.Authenticate(ctx, relogin.Token)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleOrganizer, claims.Role)
+	assert.Equal(t, []string{model.PermissionEventsWrite}, claims.Permissions)
+
+	created, err = us.EnsureAdmin(ctx, "promoted@test.local", "")
+	require.NoError(t, err)
+	assert.False(t, created)
+	user, err := storage.User.GetByEmail(ctx, "promoted@test.local")
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleAdmin, user.Role)
+}
+
+func TestEnsureAdmin_RepeatedRunsAndDemotion(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	_, err := us.EnsureAdmin(ctx, "weak@test.local", "123")
+	assert.ErrorIs(t, err, ErrWeakPassword)
+	weak, err := storage.User.GetByEmail(ctx, "weak@test.local")
+	require.NoError(t, err)
+	assert.Zero(t, weak.ID, "a failed bootstrap must not leave an account behind")
+
+	created, err := us.EnsureAdmin(ctx, "first@test.local", "secret")
+	require.NoError(t, err)
+	assert.True(t, created)
+	created, err = us.EnsureAdmin(ctx, "first@test.local", "")
+	require.NoError(t, err)
+	assert.False(t, created, "a repeated run must keep the existing admin")
+
+	created, err = us.EnsureAdmin(ctx, "second@test.local", "secret")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	first, err := storage.User.GetByEmail(ctx, "first@test.local")
+	require.NoError(t, err)
+	second, err := storage.User.GetByEmail(ctx, "second@test.local")
+	require.NoError(t, err)
+
+	require.NoError(t, us.ChangeRole(ctx, first.ID, second.ID, model.RoleUser))
+	second, err = storage.User.GetByID(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleUser, second.Role)
+}
+
+func TestSetRolePermissions_AppliesOnRefresh(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "reader@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, tokens.Token)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Permissions)
+
+	err = us.SetRolePermissions(ctx, model.RoleUser, []string{model.PermissionUsersRead, model.PermissionUsersRead})
+	require.NoError(t, err)
+
+	roles, err := us.GetRolePermissions(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, len(model.Roles))
+	assert.Equal(t, model.RoleUser, roles[0].Role)
+	assert.Equal(t, []string{model.PermissionUsersRead}, roles[0].Permissions)
+
+	refreshed, err := us.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	claims, err = us.Authenticate(ctx, refreshed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.PermissionUsersRead}, claims.Permissions)
+}
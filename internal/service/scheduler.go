@@ -13,14 +13,15 @@ import (
 )
 
 type SchedulerService struct {
-	bookingRepo repository.BookingRepository
+	storage  *repository.Storage
+	waitlist WaitlistService
 }
 
-func NewSchedulerService(bookingRepo repository.BookingRepository) *SchedulerService {
-	return &SchedulerService{bookingRepo: bookingRepo}
+func NewSchedulerService(storage *repository.Storage, waitlist WaitlistService) *SchedulerService {
+	return &SchedulerService{storage: storage, waitlist: waitlist}
 }
 
-func (s *SchedulerService) Start(ctx context.Context, interval time.Duration, msgCh chan<- RetryMessage) {
+func (s *SchedulerService) Start(ctx context.Context, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
@@ -28,39 +29,162 @@ func (s *SchedulerService) Start(ctx context.Context, interval time.Duration, ms
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			expiredBooking, err := s.bookingRepo.GetExpiredBooking(ctx)
+			expiredBooking, err := s.expireBooking(ctx)
 			if err != nil {
 				zlog.Logger.Error().Msgf("serviceSchedulerService.Start error: %v", err)
 			}
-
-			for _, b := range expiredBooking {
-				select {
-				case <-ctx.Done():
-					return
-				case msgCh <- buildMessage(b):
-				}
+			if len(expiredBooking) > 0 {
+				zlog.Logger.Info().Msgf("Expired %d bookings", len(expiredBooking))
 			}
 
-			zlog.Logger.Info().Msgf("Start delete expired booking")
-			s.deleteExpiredBooking(ctx)
+			s.promoteWaitlist(ctx, expiredBooking)
+			s.sendReminders(ctx)
+			s.finishPastEvents(ctx)
+			s.buildExports(ctx)
 		}
 	}
 }
 
-func (s *SchedulerService) deleteExpiredBooking(ctx context.Context) {
-	err := s.bookingRepo.DeleteExpiredBooking(ctx)
+// expireBooking expires overdue bookings and queues the notifications in the
+// same transaction, so a booking is never expired without its owner hearing
+// about it.
+func (s *SchedulerService) expireBooking(ctx context.Context) ([]model.BookingGetForTG, error) {
+	var expiredBooking []model.BookingGetForTG
+	err := s.storage.WithTx(ctx, func(st *repository.Storage) error {
+		var err error
+		expiredBooking, err = st.Booking.ExpireBooking(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = st.PromoCode.Release(ctx, bookingIDs(expiredBooking))
+		if err != nil {
+			return err
+		}
+
+		msgs := make([]model.NotificationInCreate, 0, len(expiredBooking))
+		for _, b := range expiredBooking {
+			msgs = append(msgs, buildMessage(b))
+		}
+		return enqueueNotifications(ctx, st, msgs)
+	})
 	if err != nil {
-		zlog.Logger.Error().Msgf("serviceSchedulerService.deleteExpiredBooking error: %v", err)
+		return nil, err
 	}
+	return expiredBooking, nil
 }
 
-func buildMessage(b model.BookingGetForTG) RetryMessage {
+func (s *SchedulerService) promoteWaitlist(ctx context.Context, expiredBooking []model.BookingGetForTG) {
+	promoted := make(map[int]struct{}, len(expiredBooking))
+	for _, b := range expiredBooking {
+		if _, ok := promoted[b.EventID]; ok {
+			continue
+		}
+		promoted[b.EventID] = struct{}{}
+
+		err := s.waitlist.Promote(ctx, b.EventID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("serviceSchedulerService.promoteWaitlist error: %v", err)
+		}
+	}
+}
+
+// sendReminders claims due reminders and queues their notifications in one
+// transaction, so a reminder is recorded as sent only together with its
+// outbox message.
+func (s *SchedulerService) sendReminders(ctx context.Context) {
+	var count int
+	err := s.storage.WithTx(ctx, func(st *repository.Storage) error {
+		due, err := st.Reminder.ClaimDue(ctx)
+		if err != nil {
+			return err
+		}
+		count = len(due)
+
+		msgs := make([]model.NotificationInCreate, 0, len(due))
+		for _, r := range due {
+			msgs = append(msgs, buildReminderMessage(r))
+		}
+		return enqueueNotifications(ctx, st, msgs)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("serviceSchedulerService.sendReminders error: %v", err)
+		return
+	}
+	if count > 0 {
+		zlog.Logger.Info().Msgf("Sent %d event reminders", count)
+	}
+}
+
+func (s *SchedulerService) finishPastEvents(ctx context.Context) {
+	count, err := s.storage.Event.FinishPastEvents(ctx)
+	if err != nil {
+		zlog.Logger.Error().Msgf("serviceSchedulerService.finishPastEvents error: %v", err)
+		return
+	}
+	if count > 0 {
+		zlog.Logger.Info().Msgf("Finished %d past events", count)
+	}
+}
+
+// buildExports builds the claimed exports from outside the claiming
+// transaction, so a failed read marks only its export as failed.
+func (s *SchedulerService) buildExports(ctx context.Context) {
+	var count int
+	err := s.storage.WithTx(ctx, func(st *repository.Storage) error {
+		pending, err := st.DataExport.ClaimPending(ctx, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range pending {
+			payload, err := buildExport(ctx, s.storage, e.UserID, e.Format)
+			if err != nil {
+				zlog.Logger.Error().Msgf("serviceSchedulerService.buildExports export %d error: %v", e.ID, err)
+				err = st.DataExport.MarkFailed(ctx, e.ID, err.Error())
+			} else {
+				err = st.DataExport.MarkReady(ctx, e.ID, payload)
+			}
+			if err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("serviceSchedulerService.buildExports error: %v", err)
+		return
+	}
+	if count > 0 {
+		zlog.Logger.Info().Msgf("Built %d data exports", count)
+	}
+}
+
+func buildMessage(b model.BookingGetForTG) model.NotificationInCreate {
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Бронирование %d отменено.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Бронирование %d отменено: истёк срок подтверждения.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
 	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
 	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
-	return RetryMessage{
-		ChatID: b.TgChatID,
-		Text:   builder.String(),
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeBookingExpired,
+		Subject:   fmt.Sprintf("Бронирование %d отменено", b.ID),
+		Text:      builder.String(),
+	}
+}
+
+func buildReminderMessage(r model.ReminderDue) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Напоминание: до события осталось %s.\n", time.Until(r.EventDate).Round(time.Minute)))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", r.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", r.EventDate))
+	builder.WriteString(fmt.Sprintf("Бронирование %d, количество мест: %d\n", r.BookingID, r.Quantity))
+	return model.NotificationInCreate{
+		UserID:    r.UserID,
+		EventType: model.NotificationTypeEventReminder,
+		Subject:   fmt.Sprintf("Напоминание о событии «%s»", r.TitleEvent),
+		Text:      builder.String(),
 	}
 }
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestSendReminders_NeverDuplicates(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('remind@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	es := NewEventService(storage, nil)
+	admin := model.Actor{UserID: userID, Permissions: []string{model.PermissionEventsManageAll}}
+	err = es.CreateEvent(ctx, admin, model.EventInCreate{
+		Title:             "Reminder test",
+		EventDate:         time.Now().Add(time.Hour),
+		TotalPlace:        10,
+		ReservationPeriod: "10m",
+		Reminders:         []string{"2h", "30m"},
+	})
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`SELECT event_id FROM events WHERE title='Reminder test'`).Scan(&eventID)
+	require.NoError(t, err)
+
+	err = NewBookingService(storage, nil).Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID})
+	require.NoError(t, err)
+
+	scheduler := NewSchedulerService(storage, NewWaitlistService(storage))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.sendReminders(ctx)
+		}()
+	}
+	wg.Wait()
+	scheduler.sendReminders(ctx)
+
+	var count int
+	err = db.QueryRow(`SELECT COUNT(*) FROM notification_outbox WHERE event_type=$1`,
+		model.NotificationTypeEventReminder).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "only the due 2h reminder must be queued, exactly once")
+
+	newDate := time.Now().Add(20 * time.Minute)
+	err = es.UpdateEvent(ctx, admin, eventID, model.EventInUpdate{EventDate: &newDate})
+	require.NoError(t, err)
+
+	scheduler.sendReminders(ctx)
+	scheduler.sendReminders(ctx)
+
+	err = db.QueryRow(`SELECT COUNT(*) FROM notification_outbox WHERE event_type=$1`,
+		model.NotificationTypeEventReminder).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "a rescheduled event must remind once more, not once per reminder")
+
+	var recorded int
+	err = db.QueryRow(`SELECT COUNT(*) FROM reminder_deliveries`).Scan(&recorded)
+	require.NoError(t, err)
+	assert.Equal(t, 2, recorded)
+}
@@ -5,15 +5,28 @@ import (
 )
 
 type Services struct {
-	Event   EventService
-	Booking BookingService
-	User    UserService
+	Event        EventService
+	Booking      BookingService
+	User         UserService
+	Waitlist     WaitlistService
+	Notification NotificationService
+	Export       ExportService
+	Payment      PaymentService
+	PromoCode    PromoCodeService
+	Venue        VenueService
 }
 
 func NewServices(s *repository.Storage) *Services {
+	payment := NewPaymentService(s)
 	return &Services{
-		Event:   NewEventService(s),
-		Booking: NewBookingService(s),
-		User:    NewUserService(s),
+		Event:        NewEventService(s, payment.providers),
+		Booking:      NewBookingService(s, payment.providers),
+		User:         NewUserService(s, payment.providers),
+		Waitlist:     NewWaitlistService(s),
+		Notification: NewNotificationService(s),
+		Export:       NewExportService(s),
+		Payment:      payment,
+		PromoCode:    NewPromoCodeService(s),
+		Venue:        NewVenueService(s),
 	}
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestRefresh_RotatesAndRevokesOnReuse(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	first, err := us.CreateUser(ctx, model.UserInCreate{Email: "session@test.local", Password: "secret"})
+	require.NoError(t, err)
+
+	claims, err := us.Authenticate(ctx, first.Token)
+	require.NoError(t, err)
+	assert.NotZero(t, claims.SessionID)
+
+	second, err := us.Refresh(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	_, err = us.Refresh(ctx, first.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = us.Refresh(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken, "reusing a rotated token must revoke the session")
+
+	_, err = us.Authenticate(ctx, second.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+func TestLogoutAndRevokeSessions(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	first, err := us.CreateUser(ctx, model.UserInCreate{Email: "logout@test.local", Password: "secret"})
+	require.NoError(t, err)
+	second, err := us.Login(ctx, model.UserLoginRequest{Email: "logout@test.local", Password: "secret"})
+	require.NoError(t, err)
+
+	require.NoError(t, us.Logout(ctx, first.RefreshToken))
+	_, err = us.Authenticate(ctx, first.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+	assert.ErrorIs(t, us.Logout(ctx, first.RefreshToken), ErrInvalidRefreshToken)
+
+	claims, err := us.Authenticate(ctx, second.Token)
+	require.NoError(t, err)
+	count, err := us.RevokeSessions(ctx, claims.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	_, err = us.Authenticate(ctx, second.Token)
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+	_, err = us.Refresh(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+)
+
+const (
+	botPageSize     = 5
+	botBookingsSize = 10
+	botDateLayout   = "02.01.2006 15:04"
+)
+
+// Callback data prefixes. Telegram limits callback data to 64 bytes, so the
+// payload is kept short: "ev:n:<created_at unix micro>:<event id>" pages the
+// event list, "bk:<event id>[:<ticket type id>]" books one seat,
+// "cf:<booking id>:<event id>" and "cn:<booking id>:<event id>" confirm and
+// cancel a booking.
+const (
+	callbackEventsNext = "ev:n"
+	callbackEventsPrev = "ev:p"
+	callbackBook       = "bk"
+	callbackConfirm    = "cf"
+	callbackCancel     = "cn"
+)
+
+const botHelp = `Команды:
+/events — список событий
+/book <id события> [количество] [id типа билета] — забронировать места
+/mybookings — мои брони
+/unlink — отвязать чат от аккаунта`
+
+const botLinkHelp = `Чтобы привязать чат к аккаунту EventBooker, получите код в личном кабинете и отправьте его боту командой /start <код> или откройте ссылку из кабинета.`
+
+// HandleUpdate processes a single update from the Bot API. Commands and
+// buttons call the same services as the HTTP API on behalf of the account
+// linked to the chat.
+func (tg *TelegramBot) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
+	switch {
+	case update.CallbackQuery != nil:
+		tg.handleCallback(ctx, update.CallbackQuery)
+	case update.Message != nil && update.Message.IsCommand():
+		tg.handleCommand(ctx, update.Message)
+	}
+}
+
+func (tg *TelegramBot) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+
+	switch msg.Command() {
+	case "start":
+		tg.handleStart(ctx, chatID, strings.TrimSpace(msg.CommandArguments()))
+	case "help":
+		tg.reply(chatID, botHelp, nil)
+	case "events":
+		text, markup, err := tg.renderEvents(ctx, "next", time.Time{}, 0)
+		if err != nil {
+			tg.reply(chatID, botErrorText(err), nil)
+			return
+		}
+		if text == "" {
+			text = "Событий пока нет."
+		}
+		tg.reply(chatID, text, markup)
+	case "book":
+		tg.handleBookCommand(ctx, chatID, msg.CommandArguments())
+	case "mybookings":
+		tg.handleMyBookings(ctx, chatID)
+	case "unlink":
+		tg.handleUnlink(ctx, chatID)
+	default:
+		tg.reply(chatID, "Неизвестная команда\n\n"+botHelp, nil)
+	}
+}
+
+// handleStart links the chat to an account when the command carries a link
+// code, which is how a t.me deep link arrives.
+func (tg *TelegramBot) handleStart(ctx context.Context, chatID int64, code string) {
+	if code == "" {
+		tg.reply(chatID, botLinkHelp+"\n\n"+botHelp, nil)
+		return
+	}
+
+	user, err := tg.user.LinkTelegram(ctx, code, chatID)
+	if err != nil {
+		tg.reply(chatID, botErrorText(err), nil)
+		return
+	}
+	zlog.Logger.Info().Msgf("Чат %d привязан к пользователю %d", chatID, user.ID)
+	tg.reply(chatID, fmt.Sprintf("Чат привязан к аккаунту %s.\n\n%s", user.Email, botHelp), nil)
+}
+
+func (tg *TelegramBot) handleUnlink(ctx context.Context, chatID int64) {
+	user, err := tg.user.GetByTgChatID(ctx, chatID)
+	if err != nil {
+		tg.reply(chatID, botErrorText(err), nil)
+		return
+	}
+
+	err = tg.user.UnlinkTelegram(ctx, user.ID)
+	if err != nil {
+		tg.reply(chatID, botErrorText(err), nil)
+		return
+	}
+	tg.reply(chatID, "Чат отвязан от аккаунта. Уведомления в Telegram больше не придут.", nil)
+}
+
+func (tg *TelegramBot) handleBookCommand(ctx context.Context, chatID int64, args string) {
+	const usage = "Использование: /book <id события> [количество] [id типа билета]"
+
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 3 {
+		tg.reply(chatID, usage, nil)
+		return
+	}
+
+	eventID, err := strconv.Atoi(fields[0])
+	if err != nil {
+		tg.reply(chatID, usage, nil)
+		return
+	}
+
+	quantity := 1
+	if len(fields) >= 2 {
+		quantity, err = strconv.Atoi(fields[1])
+		if err != nil {
+			tg.reply(chatID, usage, nil)
+			return
+		}
+	}
+
+	ticketTypeID := 0
+	if len(fields) == 3 {
+		ticketTypeID, err = strconv.Atoi(fields[2])
+		if err != nil {
+			tg.reply(chatID, usage, nil)
+			return
+		}
+	}
+
+	tg.reply(chatID, tg.book(ctx, chatID, eventID, ticketTypeID, quantity), nil)
+}
+
+func (tg *TelegramBot) book(ctx context.Context, chatID int64, eventID, ticketTypeID, quantity int) string {
+	u, err := tg.user.GetByTgChatID(ctx, chatID)
+	if err != nil {
+		return botErrorText(err)
+	}
+
+	err = tg.booking.Book(ctx, model.BookingInCreate{
+		UserID:       u.ID,
+		EventID:      eventID,
+		Quantity:     quantity,
+		TicketTypeID: ticketTypeID,
+	})
+	if err != nil {
+		return botErrorText(err)
+	}
+	return fmt.Sprintf("Бронь на событие #%d создана (мест: %d). Список броней: /mybookings", eventID, quantity)
+}
+
+func (tg *TelegramBot) handleMyBookings(ctx context.Context, chatID int64) {
+	u, err := tg.user.GetByTgChatID(ctx, chatID)
+	if err != nil {
+		tg.reply(chatID, botErrorText(err), nil)
+		return
+	}
+
+	bookings, err := tg.booking.GetByUserID(ctx, model.BookingGetRequest{
+		UserID:        u.ID,
+		Mode:          "prev",
+		LastCreatedAt: time.Now(),
+		LastID:        math.MaxInt32,
+		PageSize:      botBookingsSize,
+	})
+	if err != nil {
+		tg.reply(chatID, botErrorText(err), nil)
+		return
+	}
+
+	if len(bookings) == 0 {
+		tg.reply(chatID, "У вас нет броней. Список событий: /events", nil)
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Последние брони:\n\n")
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, b := range bookings {
+		builder.WriteString(fmt.Sprintf("#%d «%s» — %s\n", b.ID, b.EventTitle, b.EventDate.Format(botDateLayout)))
+		builder.WriteString(fmt.Sprintf("Мест: %d, статус: %s", b.Quantity, b.Status))
+		if b.Status == model.StatusBookingPending {
+			builder.WriteString(fmt.Sprintf(", подтвердить до %s", b.ExpiresAt.Format(botDateLayout)))
+		}
+		builder.WriteString("\n\n")
+
+		var row []tgbotapi.InlineKeyboardButton
+		if b.Status == model.StatusBookingPending {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("Подтвердить #%d", b.ID),
+				fmt.Sprintf("%s:%d:%d", callbackConfirm, b.ID, b.EventID)))
+		}
+		if b.Status == model.StatusBookingPending || b.Status == model.StatusBookingConfirmed {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("Отменить #%d", b.ID),
+				fmt.Sprintf("%s:%d:%d", callbackCancel, b.ID, b.EventID)))
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	var markup *tgbotapi.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		m := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		markup = &m
+	}
+	tg.reply(chatID, builder.String(), markup)
+}
+
+// renderEvents builds one page of the event list. The cursor is the
+// created_at and id of the last (mode "next") or first (mode "prev") event
+// of the current page, as in the HTTP API. An empty text means the page has
+// no events.
+func (tg *TelegramBot) renderEvents(ctx context.Context, mode string, lastCreatedAt time.Time,
+	lastID int) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	events, err := tg.event.GetListEvents(ctx, model.EventGetRequest{
+		Mode:          mode,
+		LastCreatedAt: lastCreatedAt,
+		LastID:        lastID,
+		PageSize:      botPageSize,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(events) == 0 {
+		return "", nil, nil
+	}
+
+	if mode == "prev" {
+		slices.Reverse(events)
+	}
+
+	var builder strings.Builder
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, e := range events {
+		builder.WriteString(fmt.Sprintf("#%d %s\n", e.ID, e.Title))
+		builder.WriteString(fmt.Sprintf("%s, свободно %d из %d\n", e.EventDate.Format(botDateLayout),
+			e.TotalPlace-e.OccupiedPlace, e.TotalPlace))
+
+		onSale := e.EventStatus == model.EventStatusPublished && e.EventDate.After(time.Now())
+		if len(e.TicketTypes) <= 1 {
+			builder.WriteString("\n")
+			if onSale && e.OccupiedPlace < e.TotalPlace {
+				rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+					fmt.Sprintf("Забронировать #%d", e.ID), fmt.Sprintf("%s:%d", callbackBook, e.ID))))
+			}
+			continue
+		}
+
+		// Events with several tiers get a line and a button per tier.
+		for _, t := range e.TicketTypes {
+			builder.WriteString(fmt.Sprintf("  %s: %s, свободно %d из %d\n", t.Name, formatPrice(t.Price, t.Currency),
+				t.Capacity-t.OccupiedPlace, t.Capacity))
+			if onSale && t.OccupiedPlace < t.Capacity {
+				rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+					fmt.Sprintf("#%d %s", e.ID, t.Name), fmt.Sprintf("%s:%d:%d", callbackBook, e.ID, t.ID))))
+			}
+		}
+		builder.WriteString("\n")
+	}
+
+	first, last := events[0], events[len(events)-1]
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("« Назад",
+			fmt.Sprintf("%s:%d:%d", callbackEventsPrev, first.CreatedAt.UnixMicro(), first.ID)),
+		tgbotapi.NewInlineKeyboardButtonData("Далее »",
+			fmt.Sprintf("%s:%d:%d", callbackEventsNext, last.CreatedAt.UnixMicro(), last.ID)),
+	))
+
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return builder.String(), &markup, nil
+}
+
+func (tg *TelegramBot) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if cb.Message == nil {
+		tg.answer(cb.ID, "")
+		return
+	}
+	chatID := cb.Message.Chat.ID
+	parts := strings.Split(cb.Data, ":")
+
+	switch {
+	case len(parts) == 4 && (parts[0]+":"+parts[1] == callbackEventsNext || parts[0]+":"+parts[1] == callbackEventsPrev):
+		micro, err1 := strconv.ParseInt(parts[2], 10, 64)
+		lastID, err2 := strconv.Atoi(parts[3])
+		if err1 != nil || err2 != nil {
+			tg.answer(cb.ID, "Некорректная кнопка")
+			return
+		}
+
+		mode := "next"
+		if parts[1] == "p" {
+			mode = "prev"
+		}
+		tg.pageEvents(ctx, cb, mode, time.UnixMicro(micro), lastID)
+
+	case (len(parts) == 2 || len(parts) == 3) && parts[0] == callbackBook:
+		eventID, err := strconv.Atoi(parts[1])
+		if err != nil {
+			tg.answer(cb.ID, "Некорректная кнопка")
+			return
+		}
+		ticketTypeID := 0
+		if len(parts) == 3 {
+			ticketTypeID, err = strconv.Atoi(parts[2])
+			if err != nil {
+				tg.answer(cb.ID, "Некорректная кнопка")
+				return
+			}
+		}
+		tg.answer(cb.ID, "")
+		tg.reply(chatID, tg.book(ctx, chatID, eventID, ticketTypeID, 1), nil)
+
+	case len(parts) == 3 && (parts[0] == callbackConfirm || parts[0] == callbackCancel):
+		bookID, err1 := strconv.Atoi(parts[1])
+		eventID, err2 := strconv.Atoi(parts[2])
+		if err1 != nil || err2 != nil {
+			tg.answer(cb.ID, "Некорректная кнопка")
+			return
+		}
+		tg.answer(cb.ID, "")
+		tg.reply(chatID, tg.changeBooking(ctx, chatID, parts[0], bookID, eventID), nil)
+
+	default:
+		tg.answer(cb.ID, "Неизвестная кнопка")
+	}
+}
+
+func (tg *TelegramBot) pageEvents(ctx context.Context, cb *tgbotapi.CallbackQuery, mode string,
+	lastCreatedAt time.Time, lastID int) {
+	text, markup, err := tg.renderEvents(ctx, mode, lastCreatedAt, lastID)
+	if err != nil {
+		tg.answer(cb.ID, botErrorText(err))
+		return
+	}
+	if text == "" {
+		if mode == "prev" {
+			tg.answer(cb.ID, "Это первая страница")
+		} else {
+			tg.answer(cb.ID, "Это последняя страница")
+		}
+		return
+	}
+	tg.answer(cb.ID, "")
+
+	edit := tgbotapi.NewEditMessageText(cb.Message.Chat.ID, cb.Message.MessageID, text)
+	edit.ReplyMarkup = markup
+	tg.m.Lock()
+	defer tg.m.Unlock()
+	if _, err := tg.bot.Send(edit); err != nil {
+		zlog.Logger.Error().Msgf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+func (tg *TelegramBot) changeBooking(ctx context.Context, chatID int64, action string, bookID, eventID int) string {
+	u, err := tg.user.GetByTgChatID(ctx, chatID)
+	if err != nil {
+		return botErrorText(err)
+	}
+
+	if action == callbackConfirm {
+		err = tg.booking.Confirm(ctx, bookID, eventID, u.ID)
+		if err != nil {
+			return botErrorText(err)
+		}
+		return fmt.Sprintf("Бронь #%d подтверждена.", bookID)
+	}
+
+	err = tg.booking.CancelBook(ctx, bookID, eventID, u.ID)
+	if err != nil {
+		return botErrorText(err)
+	}
+	return fmt.Sprintf("Бронь #%d отменена.", bookID)
+}
+
+func (tg *TelegramBot) reply(chatID int64, text string, markup *tgbotapi.InlineKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	if markup != nil {
+		msg.ReplyMarkup = markup
+	}
+	tg.m.Lock()
+	defer tg.m.Unlock()
+	if _, err := tg.bot.Send(msg); err != nil {
+		zlog.Logger.Error().Msgf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+func (tg *TelegramBot) answer(callbackID, text string) {
+	tg.m.Lock()
+	defer tg.m.Unlock()
+	if _, err := tg.bot.Request(tgbotapi.NewCallback(callbackID, text)); err != nil {
+		zlog.Logger.Error().Msgf("Ошибка ответа на callback: %v", err)
+	}
+}
+
+func botErrorText(err error) string {
+	if errors.Is(err, ErrUserNotFound) {
+		return "Этот чат не привязан к аккаунту EventBooker.\n\n" + botLinkHelp
+	}
+	if errors.Is(err, ErrInvalidLinkCode) {
+		return "Код привязки недействителен или истёк. Получите новый код в личном кабинете."
+	}
+	if errors.Is(err, ErrPaymentRequired) {
+		return "Бронь платная: оплатите её в личном кабинете, она подтвердится после оплаты."
+	}
+	if errors.Is(err, ErrCancellationClosed) {
+		return "До события осталось слишком мало времени, по условиям организатора бронь уже нельзя отменить."
+	}
+	if errors.Is(err, ErrSeatsRequired) {
+		return "На это событие места выбираются по схеме зала. Забронируйте их на сайте."
+	}
+	if errors.Is(err, ErrTicketTypeRequired) {
+		return "У события несколько типов билетов. Выберите тип кнопкой в /events или укажите его id: /book <id события> <количество> <id типа билета>"
+	}
+	return "Ошибка: " + err.Error()
+}
+
+// formatPrice renders a price in minor units, e.g. 150000 RUB as "1500.00 RUB".
+func formatPrice(price int64, currency string) string {
+	if price == 0 {
+		return "бесплатно"
+	}
+	return fmt.Sprintf("%d.%02d %s", price/100, price%100, currency)
+}
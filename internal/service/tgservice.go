@@ -2,29 +2,22 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"sync"
-	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/wb-go/wbf/zlog"
 )
 
 type TelegramBot struct {
-	bot *tgbotapi.BotAPI
-	m   sync.Mutex
+	bot     *tgbotapi.BotAPI
+	m       sync.Mutex
+	event   EventService
+	booking BookingService
+	user    UserService
 }
 
-const maxRetry = 5
-
-type RetryMessage struct {
-	ChatID   int64
-	Attempts int
-	Text     string
-}
-
-func NewTelegramBot(token string) *TelegramBot {
+func NewTelegramBot(token string, s *Services) *TelegramBot {
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 
@@ -32,7 +25,29 @@ func NewTelegramBot(token string) *TelegramBot {
 		return nil
 	}
 
-	return &TelegramBot{bot: bot, m: sync.Mutex{}}
+	return newTelegramBot(bot, s)
+}
+
+// NewTelegramBotWithEndpoint talks to a Bot API server other than
+// api.telegram.org. Endpoint is a format string like
+// "http://localhost:8081/bot%s/%s".
+func NewTelegramBotWithEndpoint(token, endpoint string, s *Services) (*TelegramBot, error) {
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(token, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTelegramBot(bot, s), nil
+}
+
+func newTelegramBot(bot *tgbotapi.BotAPI, s *Services) *TelegramBot {
+	return &TelegramBot{
+		bot:     bot,
+		m:       sync.Mutex{},
+		event:   s.Event,
+		booking: s.Booking,
+		user:    s.User,
+	}
 }
 
 func (tg *TelegramBot) ListenUpdated(ctx context.Context) {
@@ -44,6 +59,7 @@ func (tg *TelegramBot) ListenUpdated(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
+			tg.bot.StopReceivingUpdates()
 			return
 
 		case update, ok := <-updates:
@@ -52,32 +68,7 @@ func (tg *TelegramBot) ListenUpdated(ctx context.Context) {
 				return
 			}
 
-			if update.Message == nil {
-				continue
-			}
-
-			if update.Message.IsCommand() {
-				command := update.Message.Command()
-				chatID := update.Message.Chat.ID
-
-				switch command {
-				case "start":
-					username := update.Message.Chat.UserName
-
-					zlog.Logger.Info().Msgf("Получено сообщение от %s [%d]: %s", username, chatID, update.Message.Text)
-
-					msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ваш ChatId: %d", chatID))
-					if _, err := tg.bot.Send(msg); err != nil {
-						zlog.Logger.Error().Msgf("Ошибка отправки сообщения: %v", err)
-					}
-				default:
-					msg := tgbotapi.NewMessage(chatID, "Неизвестная команда")
-					if _, err := tg.bot.Send(msg); err != nil {
-						zlog.Logger.Error().Msgf("Ошибка отправки сообщения: %v", err)
-					}
-				}
-			}
-
+			tg.HandleUpdate(ctx, update)
 		}
 	}
 }
@@ -94,33 +85,3 @@ func (tg *TelegramBot) Send(chatId int64, text string) error {
 	}
 	return nil
 }
-
-func (tg *TelegramBot) RetryWorker(ctx context.Context, retryQueue chan RetryMessage) {
-	for rm := range retryQueue {
-		if tg == nil {
-			log.Println(rm.Text)
-			continue
-		}
-		err := tg.Send(rm.ChatID, rm.Text)
-		if err != nil {
-			zlog.Logger.Error().Msgf("service.TelegramBot.RetryWorker error: %v", err)
-			rm.Attempts++
-
-			if rm.Attempts < maxRetry {
-				go func(r RetryMessage) {
-					select {
-					case <-time.After(time.Second * 3 * time.Duration(r.Attempts)):
-						select {
-						case <-ctx.Done():
-							return
-						case retryQueue <- r:
-						}
-					case <-ctx.Done():
-						return
-					}
-				}(rm)
-			}
-
-		}
-	}
-}
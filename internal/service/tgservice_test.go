@@ -0,0 +1,354 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/service"
+	"EventBooker/internal/service/mocks"
+)
+
+type botCall struct {
+	method string
+	params url.Values
+}
+
+// fakeBotAPI imitates the Telegram Bot API: it answers getMe and records
+// every other method call.
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls []botCall
+}
+
+func (f *fakeBotAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	_ = r.ParseForm()
+
+	var result any
+	switch method {
+	case "getMe":
+		result = map[string]any{"id": 1, "is_bot": true, "first_name": "EventBooker", "username": "event_booker_bot"}
+	case "answerCallbackQuery":
+		f.record(method, r.PostForm)
+		result = true
+	default:
+		f.record(method, r.PostForm)
+		result = map[string]any{"message_id": 1, "date": 0, "chat": map[string]any{"id": 1}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+func (f *fakeBotAPI) record(method string, params url.Values) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, botCall{method: method, params: params})
+}
+
+func (f *fakeBotAPI) callsOf(method string) []botCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var res []botCall
+	for _, c := range f.calls {
+		if c.method == method {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+const testChatID = 100
+
+func commandUpdate(text string) tgbotapi.Update {
+	command := strings.Fields(text)[0]
+	return tgbotapi.Update{Message: &tgbotapi.Message{
+		MessageID: 10,
+		Chat:      &tgbotapi.Chat{ID: testChatID},
+		Text:      text,
+		Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+	}}
+}
+
+func callbackUpdate(data string) tgbotapi.Update {
+	return tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb1",
+		Data:    data,
+		Message: &tgbotapi.Message{MessageID: 20, Chat: &tgbotapi.Chat{ID: testChatID}},
+	}}
+}
+
+func TestTelegramBot_HandleUpdate(t *testing.T) {
+	zlog.Init()
+	eventDate := time.Now().Add(48 * time.Hour)
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	linkedUser := model.UserInResponse{ID: 42, Email: "bot@test.local"}
+
+	type serviceMocks struct {
+		event   *mocks.MockEventService
+		booking *mocks.MockBookingService
+		user    *mocks.MockUserService
+	}
+
+	tests := []struct {
+		name         string
+		update       tgbotapi.Update
+		setupMocks   func(m serviceMocks)
+		method       string
+		expectedText string
+		expectedData string
+	}{
+		{
+			name:   "events first page",
+			update: commandUpdate("/events"),
+			setupMocks: func(m serviceMocks) {
+				m.event.On("GetListEvents", mock.Anything, mock.MatchedBy(func(req model.EventGetRequest) bool {
+					return req.Mode == "next" && req.LastID == 0 && req.PageSize == 5
+				})).Return([]model.EventInResponse{
+					{ID: 7, Title: "Concert", EventDate: eventDate, TotalPlace: 10, OccupiedPlace: 4,
+						EventStatus: model.EventStatusPublished, CreatedAt: createdAt},
+				}, nil)
+			},
+			method:       "sendMessage",
+			expectedText: "свободно 6 из 10",
+			expectedData: fmt.Sprintf(`"callback_data":"ev:n:%d:7"`, createdAt.UnixMicro()),
+		},
+		{
+			name:   "events next page",
+			update: callbackUpdate(fmt.Sprintf("ev:n:%d:7", createdAt.UnixMicro())),
+			setupMocks: func(m serviceMocks) {
+				m.event.On("GetListEvents", mock.Anything, mock.MatchedBy(func(req model.EventGetRequest) bool {
+					return req.Mode == "next" && req.LastID == 7 && req.LastCreatedAt.Equal(createdAt)
+				})).Return([]model.EventInResponse{
+					{ID: 9, Title: "Lecture", EventDate: eventDate, TotalPlace: 5,
+						EventStatus: model.EventStatusPublished, CreatedAt: createdAt},
+				}, nil)
+			},
+			method:       "editMessageText",
+			expectedText: "#9 Lecture",
+			expectedData: `"callback_data":"bk:9"`,
+		},
+		{
+			name:   "events with ticket types",
+			update: commandUpdate("/events"),
+			setupMocks: func(m serviceMocks) {
+				m.event.On("GetListEvents", mock.Anything, mock.Anything).Return([]model.EventInResponse{
+					{ID: 9, Title: "Festival", EventDate: eventDate, TotalPlace: 15, OccupiedPlace: 5,
+						EventStatus: model.EventStatusPublished, CreatedAt: createdAt,
+						TicketTypes: []model.TicketTypeInResponse{
+							{ID: 3, Name: "standard", Capacity: 10, OccupiedPlace: 5, Price: 150000, Currency: "RUB"},
+							{ID: 4, Name: "vip", Capacity: 5, Price: 500050, Currency: "RUB"},
+						}},
+				}, nil)
+			},
+			method:       "sendMessage",
+			expectedText: "vip: 5000.50 RUB, свободно 5 из 5",
+			expectedData: `"callback_data":"bk:9:4"`,
+		},
+		{
+			name:   "events last page",
+			update: callbackUpdate(fmt.Sprintf("ev:n:%d:9", createdAt.UnixMicro())),
+			setupMocks: func(m serviceMocks) {
+				m.event.On("GetListEvents", mock.Anything, mock.Anything).Return([]model.EventInResponse{}, nil)
+			},
+			method:       "answerCallbackQuery",
+			expectedText: "Это последняя страница",
+		},
+		{
+			name:   "book with quantity",
+			update: commandUpdate("/book 7 2"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Book", mock.Anything, model.BookingInCreate{UserID: 42, EventID: 7, Quantity: 2}).Return(nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Бронь на событие #7 создана (мест: 2)",
+		},
+		{
+			name:         "book bad arguments",
+			update:       commandUpdate("/book seven"),
+			setupMocks:   func(m serviceMocks) {},
+			method:       "sendMessage",
+			expectedText: "Использование: /book",
+		},
+		{
+			name:   "book from unlinked chat",
+			update: commandUpdate("/book 7"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(model.UserInResponse{}, service.ErrUserNotFound)
+			},
+			method:       "sendMessage",
+			expectedText: "не привязан",
+		},
+		{
+			name:   "book button",
+			update: callbackUpdate("bk:9"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Book", mock.Anything, model.BookingInCreate{UserID: 42, EventID: 9, Quantity: 1}).
+					Return(service.ErrNoSeatsAvailable)
+			},
+			method:       "sendMessage",
+			expectedText: service.ErrNoSeatsAvailable.Error(),
+		},
+		{
+			name:   "book ticket type",
+			update: commandUpdate("/book 7 2 5"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Book", mock.Anything, model.BookingInCreate{UserID: 42, EventID: 7, Quantity: 2, TicketTypeID: 5}).
+					Return(nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Бронь на событие #7 создана (мест: 2)",
+		},
+		{
+			name:   "book ticket type button",
+			update: callbackUpdate("bk:9:4"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Book", mock.Anything, model.BookingInCreate{UserID: 42, EventID: 9, Quantity: 1, TicketTypeID: 4}).
+					Return(nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Бронь на событие #9 создана (мест: 1)",
+		},
+		{
+			name:   "book without ticket type",
+			update: commandUpdate("/book 9"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Book", mock.Anything, model.BookingInCreate{UserID: 42, EventID: 9, Quantity: 1}).
+					Return(service.ErrTicketTypeRequired)
+			},
+			method:       "sendMessage",
+			expectedText: "несколько типов билетов",
+		},
+		{
+			name:   "my bookings",
+			update: commandUpdate("/mybookings"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("GetByUserID", mock.Anything, mock.MatchedBy(func(req model.BookingGetRequest) bool {
+					return req.UserID == 42 && req.Mode == "prev"
+				})).Return([]model.BookingInResponse{
+					{ID: 3, EventID: 7, Quantity: 1, Status: model.StatusBookingPending, EventTitle: "Concert",
+						EventDate: eventDate, ExpiresAt: time.Now().Add(time.Hour)},
+				}, nil)
+			},
+			method:       "sendMessage",
+			expectedText: "#3 «Concert»",
+			expectedData: `"callback_data":"cf:3:7"`,
+		},
+		{
+			name:   "confirm button",
+			update: callbackUpdate("cf:3:7"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("Confirm", mock.Anything, 3, 7, 42).Return(nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Бронь #3 подтверждена.",
+		},
+		{
+			name:   "cancel button error",
+			update: callbackUpdate("cn:3:7"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.booking.On("CancelBook", mock.Anything, 3, 7, 42).Return(errors.New("db failed"))
+			},
+			method:       "sendMessage",
+			expectedText: "Ошибка: db failed",
+		},
+		{
+			name:         "start without code",
+			update:       commandUpdate("/start"),
+			setupMocks:   func(m serviceMocks) {},
+			method:       "sendMessage",
+			expectedText: "/start <код>",
+		},
+		{
+			name:   "start with link code",
+			update: commandUpdate("/start abc123"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("LinkTelegram", mock.Anything, "abc123", int64(testChatID)).Return(linkedUser, nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Чат привязан к аккаунту bot@test.local",
+		},
+		{
+			name:   "start with invalid code",
+			update: commandUpdate("/start expired"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("LinkTelegram", mock.Anything, "expired", int64(testChatID)).
+					Return(model.UserInResponse{}, service.ErrInvalidLinkCode)
+			},
+			method:       "sendMessage",
+			expectedText: "Код привязки недействителен",
+		},
+		{
+			name:   "unlink",
+			update: commandUpdate("/unlink"),
+			setupMocks: func(m serviceMocks) {
+				m.user.On("GetByTgChatID", mock.Anything, int64(testChatID)).Return(linkedUser, nil)
+				m.user.On("UnlinkTelegram", mock.Anything, 42).Return(nil)
+			},
+			method:       "sendMessage",
+			expectedText: "Чат отвязан от аккаунта",
+		},
+		{
+			name:         "unknown command",
+			update:       commandUpdate("/dance"),
+			setupMocks:   func(m serviceMocks) {},
+			method:       "sendMessage",
+			expectedText: "Неизвестная команда",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBotAPI{}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			m := serviceMocks{
+				event:   mocks.NewMockEventService(t),
+				booking: mocks.NewMockBookingService(t),
+				user:    mocks.NewMockUserService(t),
+			}
+			tt.setupMocks(m)
+
+			bot, err := service.NewTelegramBotWithEndpoint("test-token", server.URL+"/bot%s/%s", &service.Services{
+				Event:   m.event,
+				Booking: m.booking,
+				User:    m.user,
+			})
+			require.NoError(t, err)
+
+			bot.HandleUpdate(t.Context(), tt.update)
+
+			calls := fake.callsOf(tt.method)
+			require.NotEmpty(t, calls, "expected a %s call", tt.method)
+			last := calls[len(calls)-1]
+			assert.Contains(t, last.params.Get("text"), tt.expectedText)
+			if tt.expectedData != "" {
+				assert.Contains(t, last.params.Get("reply_markup"), tt.expectedData)
+			}
+		})
+	}
+}
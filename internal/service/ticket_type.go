@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+const maxTicketTypeNameLength = 50
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// normalizeTicketTypes validates the tiers in place and fills in the default
+// currency. Names must be unique within the event.
+func normalizeTicketTypes(tiers []model.TicketTypeInput) error {
+	if len(tiers) == 0 {
+		return ErrNoTicketTypes
+	}
+
+	names := make(map[string]bool, len(tiers))
+	for i := range tiers {
+		t := &tiers[i]
+		t.Name = strings.TrimSpace(t.Name)
+		if t.Name == "" || utf8.RuneCountInString(t.Name) > maxTicketTypeNameLength {
+			return ErrInvalidTicketType
+		}
+		if names[t.Name] {
+			return ErrDuplicateTicketType
+		}
+		names[t.Name] = true
+
+		if t.Capacity <= 0 {
+			return ErrInvalidTotalPlace
+		}
+		if t.Price < 0 {
+			return ErrInvalidPrice
+		}
+
+		t.Currency = strings.ToUpper(strings.TrimSpace(t.Currency))
+		if t.Currency == "" {
+			t.Currency = model.DefaultCurrency
+		}
+		if !currencyPattern.MatchString(t.Currency) {
+			return ErrInvalidCurrency
+		}
+	}
+	return nil
+}
+
+func sumCapacity(tiers []model.TicketTypeInput) int {
+	total := 0
+	for _, t := range tiers {
+		total += t.Capacity
+	}
+	return total
+}
+
+// resolveTicketType returns the tier to book. The id can be omitted only for
+// events with a single tier.
+func resolveTicketType(ctx context.Context, s *repository.Storage, eventID, ticketTypeID int) (model.TicketTypeInRepo, error) {
+	if ticketTypeID == 0 {
+		tiers, err := s.TicketType.GetByEvent(ctx, eventID)
+		if err != nil {
+			return model.TicketTypeInRepo{}, err
+		}
+		if len(tiers) == 0 {
+			return model.TicketTypeInRepo{}, ErrTicketTypeNotFound
+		}
+		if len(tiers) > 1 {
+			return model.TicketTypeInRepo{}, ErrTicketTypeRequired
+		}
+		return tiers[0], nil
+	}
+
+	tier, err := s.TicketType.GetByID(ctx, ticketTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.TicketTypeInRepo{}, ErrTicketTypeNotFound
+	}
+	if err != nil {
+		return model.TicketTypeInRepo{}, err
+	}
+	if tier.EventID != eventID {
+		return model.TicketTypeInRepo{}, ErrTicketTypeNotFound
+	}
+	return tier, nil
+}
+
+// syncTicketTypes makes the event tiers match the list: listed ids are
+// updated, new entries are created and the rest are removed. Tiers that were
+// ever booked cannot be removed, and capacity cannot drop below the seats
+// already held. Returns the new total capacity.
+func syncTicketTypes(ctx context.Context, s *repository.Storage, eventID int, tiers []model.TicketTypeInput) (int, error) {
+	current, err := s.TicketType.GetByEvent(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	existing := make(map[int]model.TicketTypeInRepo, len(current))
+	for _, t := range current {
+		existing[t.ID] = t
+	}
+
+	kept := make(map[int]bool, len(tiers))
+	for _, t := range tiers {
+		if t.ID == 0 {
+			continue
+		}
+		if _, ok := existing[t.ID]; !ok || kept[t.ID] {
+			return 0, ErrTicketTypeNotFound
+		}
+		kept[t.ID] = true
+	}
+
+	// Removals go first, so a new tier can take over the name of a removed one.
+	for _, t := range current {
+		if kept[t.ID] {
+			continue
+		}
+		booked, err := s.TicketType.HasBookings(ctx, t.ID)
+		if err != nil {
+			return 0, err
+		}
+		if booked {
+			return 0, ErrTicketTypeHasBookings
+		}
+		err = s.TicketType.Delete(ctx, t.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, t := range tiers {
+		if t.ID == 0 {
+			continue
+		}
+		tier := existing[t.ID]
+		if t.Capacity < tier.Occupied {
+			return 0, ErrTotalPlaceBelowOccupied
+		}
+		tier.Name, tier.Capacity, tier.Price, tier.Currency = t.Name, t.Capacity, t.Price, t.Currency
+		err = s.TicketType.Update(ctx, tier)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, t := range tiers {
+		if t.ID != 0 {
+			continue
+		}
+		_, err = s.TicketType.Create(ctx, eventID, t)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return sumCapacity(tiers), nil
+}
+
+// resizeSingleTicketType applies a plain total_place change, which is only
+// unambiguous when the event has one tier.
+func resizeSingleTicketType(ctx context.Context, s *repository.Storage, eventID, totalPlace int) error {
+	tiers, err := s.TicketType.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if len(tiers) != 1 {
+		return ErrTotalPlaceWithTiers
+	}
+
+	tier := tiers[0]
+	if totalPlace < tier.Occupied {
+		return ErrTotalPlaceBelowOccupied
+	}
+	tier.Capacity = totalPlace
+	return s.TicketType.Update(ctx, tier)
+}
+
+func hasSections(tiers []model.TicketTypeInput) bool {
+	for _, t := range tiers {
+		if len(t.Sections) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// keepSeatedCapacity checks a tier update of an event with assigned seating:
+// the tiers stay the same and keep the capacity of their seats.
+func keepSeatedCapacity(ctx context.Context, s *repository.Storage, eventID int, tiers []model.TicketTypeInput) error {
+	current, err := s.TicketType.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if len(tiers) != len(current) {
+		return ErrSeatedCapacity
+	}
+
+	capacity := make(map[int]int, len(current))
+	for _, t := range current {
+		capacity[t.ID] = t.Capacity
+	}
+	for i := range tiers {
+		c, ok := capacity[tiers[i].ID]
+		if !ok || len(tiers[i].Sections) > 0 {
+			return ErrSeatedCapacity
+		}
+		tiers[i].Capacity = c
+		delete(capacity, tiers[i].ID)
+	}
+	return nil
+}
+
+func toTicketTypeResponses(tiers []model.TicketTypeInRepo) []model.TicketTypeInResponse {
+	res := make([]model.TicketTypeInResponse, 0, len(tiers))
+	for _, t := range tiers {
+		res = append(res, model.TicketTypeInResponse{
+			ID:            t.ID,
+			Name:          t.Name,
+			Capacity:      t.Capacity,
+			OccupiedPlace: t.Occupied,
+			Price:         t.Price,
+			Currency:      t.Currency,
+		})
+	}
+	return res
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestNormalizeTicketTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiers []model.TicketTypeInput
+		err   error
+	}{
+		{"valid", []model.TicketTypeInput{{Name: "vip", Capacity: 5, Price: 500000, Currency: "usd"}}, nil},
+		{"empty list", []model.TicketTypeInput{}, ErrNoTicketTypes},
+		{"empty name", []model.TicketTypeInput{{Name: " ", Capacity: 5}}, ErrInvalidTicketType},
+		{"long name", []model.TicketTypeInput{{Name: strings.Repeat("a", 51), Capacity: 5}}, ErrInvalidTicketType},
+		{"duplicate name", []model.TicketTypeInput{{Name: "vip", Capacity: 5}, {Name: " vip", Capacity: 1}},
+			ErrDuplicateTicketType},
+		{"zero capacity", []model.TicketTypeInput{{Name: "vip"}}, ErrInvalidTotalPlace},
+		{"negative price", []model.TicketTypeInput{{Name: "vip", Capacity: 5, Price: -1}}, ErrInvalidPrice},
+		{"bad currency", []model.TicketTypeInput{{Name: "vip", Capacity: 5, Currency: "rubles"}}, ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, normalizeTicketTypes(tt.tiers), tt.err)
+		})
+	}
+
+	tiers := []model.TicketTypeInput{{Name: " student ", Capacity: 3}, {Name: "vip", Capacity: 2, Currency: "eur"}}
+	require.NoError(t, normalizeTicketTypes(tiers))
+	assert.Equal(t, "student", tiers[0].Name)
+	assert.Equal(t, model.DefaultCurrency, tiers[0].Currency)
+	assert.Equal(t, "EUR", tiers[1].Currency)
+	assert.Equal(t, 5, sumCapacity(tiers))
+}
+
+func TestBook_PerTicketTypeCapacityAndPrice(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	var userID int
+	err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+		VALUES ('tiers@test.local', 'x', 'user', now(), now()) RETURNING user_id`).Scan(&userID)
+	require.NoError(t, err)
+
+	manager := model.Actor{UserID: userID, Permissions: []string{model.PermissionEventsWrite, model.PermissionEventsManageAll}}
+	es := NewEventService(storage, nil)
+	err = es.CreateEvent(ctx, manager, model.EventInCreate{
+		Title:             "Tiers",
+		EventDate:         time.Now().Add(48 * time.Hour),
+		ReservationPeriod: "10m",
+		TicketTypes: []model.TicketTypeInput{
+			{Name: "standard", Capacity: 3, Price: 150000},
+			{Name: "vip", Capacity: 1, Price: 500000},
+		},
+	})
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`SELECT event_id FROM events WHERE title='Tiers'`).Scan(&eventID)
+	require.NoError(t, err)
+
+	event, err := es.GetByID(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, event.TotalPlace, "total_place is the sum of the tiers")
+	require.Len(t, event.TicketTypes, 2)
+	standard, vip := event.TicketTypes[0], event.TicketTypes[1]
+	assert.Equal(t, model.DefaultCurrency, vip.Currency)
+
+	_, err = es.GetByID(ctx, eventID+1000)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+
+	bs := NewBookingService(storage, nil)
+	assert.ErrorIs(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID}), ErrTicketTypeRequired)
+	assert.ErrorIs(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, TicketTypeID: vip.ID + 100}),
+		ErrTicketTypeNotFound)
+
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, TicketTypeID: vip.ID}))
+	assert.ErrorIs(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, TicketTypeID: vip.ID}),
+		ErrNoSeatsAvailable, "a sold out tier does not borrow seats from another one")
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userID, EventID: eventID, TicketTypeID: standard.ID,
+		Quantity: 2}))
+
+	tiers := []model.TicketTypeInput{
+		{ID: standard.ID, Name: "standard", Capacity: 3, Price: 200000},
+		{ID: vip.ID, Name: "vip", Capacity: 1, Price: 500000},
+	}
+	require.NoError(t, es.UpdateEvent(ctx, manager, eventID, model.EventInUpdate{TicketTypes: &tiers}))
+
+	bookings, err := bs.GetByUserID(ctx, model.BookingGetRequest{
+		UserID:        userID,
+		LastCreatedAt: time.Now().Add(time.Hour),
+		LastID:        1 << 30,
+		Mode:          "prev",
+		PageSize:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, bookings, 2)
+	for _, b := range bookings {
+		if b.TicketTypeID == standard.ID {
+			assert.Equal(t, int64(150000), b.UnitPrice, "the price is fixed at booking time")
+			assert.Equal(t, int64(300000), b.TotalPrice)
+			assert.Equal(t, "standard", b.TicketTypeName)
+		}
+	}
+
+	events, err := es.GetListEvents(ctx, model.EventGetRequest{
+		LastCreatedAt: time.Now().Add(time.Hour),
+		LastID:        1 << 30,
+		Mode:          "prev",
+		PageSize:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Len(t, events[0].TicketTypes, 2)
+	assert.Equal(t, 2, events[0].TicketTypes[0].OccupiedPlace)
+	assert.Equal(t, 1, events[0].TicketTypes[1].OccupiedPlace)
+
+	onlyVIP := []model.TicketTypeInput{{ID: vip.ID, Name: "vip", Capacity: 1, Price: 500000}}
+	err = es.UpdateEvent(ctx, manager, eventID, model.EventInUpdate{TicketTypes: &onlyVIP})
+	assert.ErrorIs(t, err, ErrTicketTypeHasBookings)
+
+	totalPlace := 10
+	err = es.UpdateEvent(ctx, manager, eventID, model.EventInUpdate{TotalPlace: &totalPlace})
+	assert.ErrorIs(t, err, ErrTotalPlaceWithTiers)
+}
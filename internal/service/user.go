@@ -2,9 +2,17 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,84 +26,874 @@ import (
 
 var jwtSecret []byte
 
+// authPolicy is loaded from the configuration by NewUserService, like
+// jwtSecret.
+var authPolicy = config.AuthConfig{UnverifiedLogin: true}
+
+// Claims carry the permissions of the role resolved when the token was
+// issued, so a change of the mapping applies from the next refresh.
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID      int      `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"perms,omitempty"`
+	SessionID   int      `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 type UserService interface {
-	CreateUser(ctx context.Context, u model.UserInCreate) (string, error)
-	Login(ctx context.Context, req model.UserLoginRequest) (string, error)
+	CreateUser(ctx context.Context, u model.UserInCreate) (model.AuthTokens, error)
+	Login(ctx context.Context, req model.UserLoginRequest) (model.AuthTokens, error)
+	Refresh(ctx context.Context, refreshToken string) (model.AuthTokens, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Authenticate(ctx context.Context, token string) (*Claims, error)
+	RevokeSessions(ctx context.Context, userID int) (int64, error)
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req model.PasswordResetRequest) error
+	ChangePassword(ctx context.Context, userID int, req model.PasswordChangeRequest) (model.AuthTokens, error)
+	UnlockUser(ctx context.Context, userID int) error
+	GetProfile(ctx context.Context, userID int) (model.UserInResponse, error)
+	UpdateProfile(ctx context.Context, userID int, req model.ProfileUpdate) (model.UserInResponse, error)
+	DeleteAccount(ctx context.Context, userID int, password string) error
+	ChangeRole(ctx context.Context, actorID, userID int, role string) error
+	GetRolePermissions(ctx context.Context) ([]model.RolePermissions, error)
+	SetRolePermissions(ctx context.Context, role string, permissions []string) error
+	EnsureAdmin(ctx context.Context, email, password string) (bool, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID int) error
 	GetListUsers(ctx context.Context, req model.UserGetRequest) ([]model.UserInResponse, error)
 	GetCountUsers(ctx context.Context) (int, error)
+	GetByTgChatID(ctx context.Context, chatID int64) (model.UserInResponse, error)
+	CreateTgLinkCode(ctx context.Context, userID int) (model.TgLinkCodeInResponse, error)
+	LinkTelegram(ctx context.Context, code string, chatID int64) (model.UserInResponse, error)
+	UnlinkTelegram(ctx context.Context, userID int) error
 }
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+	tgLinkCodeTTL   = 10 * time.Minute
+	resetTokenTTL   = 30 * time.Minute
+	verifyTokenTTL  = 24 * time.Hour
+
+	minPasswordLength = 6
+)
+
 type userService struct {
-	storage *repository.Storage
+	storage     *repository.Storage
+	providers   map[string]PaymentProvider
+	limiter     LoginLimiter
+	botUsername string
+	publicURL   string
 }
 
-func NewUserService(s *repository.Storage) *userService {
+func NewUserService(s *repository.Storage, providers map[string]PaymentProvider) *userService {
 	c, _ := config.NewConfig()
 	jwtSecret = []byte(c.Server.JwtKey)
-	return &userService{storage: s}
+	authPolicy = c.Auth
+	return &userService{
+		storage:     s,
+		providers:   providers,
+		limiter:     NewLoginLimiter(c.Auth.LoginLimiter, s),
+		botUsername: c.TgBot.Username,
+		publicURL:   strings.TrimRight(c.Server.PublicURL, "/"),
+	}
 }
 
-func (us *userService) CreateUser(ctx context.Context, u model.UserInCreate) (string, error) {
+// CreateUser registers an account with an unverified email and sends the
+// verification token. No session is started when the configuration does not
+// let unverified accounts log in.
+func (us *userService) CreateUser(ctx context.Context, u model.UserInCreate) (model.AuthTokens, error) {
+	u.Email = normalizeEmail(u.Email)
+	if !validEmail(u.Email) {
+		return model.AuthTokens{}, ErrInvalidEmail
+	}
 
 	hashedPassword, err := hashPassword(u.Password)
 	if err != nil {
 		zlog.Logger.Error().Msgf("service.UserService.CreateUser error: %v", err)
-		return "", err
+		return model.AuthTokens{}, err
 	}
 	u.Password = hashedPassword
 
-	err = us.storage.User.Create(ctx, u)
+	var user model.UserInRepo
+	err = us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := s.User.Create(ctx, u, model.RoleUser)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.User.GetByEmail(ctx, u.Email)
+		if err != nil {
+			return err
+		}
+
+		return us.sendVerification(ctx, s, user)
+	})
 	if err != nil {
 		zlog.Logger.Error().Msgf("service.UserService.CreateUser error: %v", err)
-		return "", err
+		return model.AuthTokens{}, err
 	}
 
-	user, err := us.storage.User.GetByEmail(ctx, u.Email)
-	if err != nil {
-		zlog.Logger.Error().Msgf("service.UserService.CreateUser error: %v", err)
-		return "", err
+	if !authPolicy.UnverifiedLogin {
+		return model.AuthTokens{}, nil
 	}
 
-	token, err := GenerateToken(user.ID, user.Email, user.Role)
+	tokens, err := us.startSession(ctx, user)
 	if err != nil {
 		zlog.Logger.Error().Msgf("service.UserService.CreateUser error: %v", err)
-		return "", err
+		return model.AuthTokens{}, err
 	}
 
-	return token, nil
+	return tokens, nil
 
 }
 
-func (us *userService) Login(ctx context.Context, req model.UserLoginRequest) (string, error) {
-	user, err := us.storage.User.GetByEmail(ctx, req.Email)
+// Login counts the attempt against the account and the client address before
+// comparing the password, so a locked key costs no bcrypt work and parallel
+// attempts cannot get past the limit. Unknown and
+// deleted accounts are compared against a dummy hash and get the same answer
+// as a wrong password, so neither the response nor its timing reveals which
+// emails are registered.
+func (us *userService) Login(ctx context.Context, req model.UserLoginRequest) (model.AuthTokens, error) {
+	keys := []loginKey{
+		{accountLoginKey(req.Email), accountMaxFailures},
+		{ipLoginKey(req.IP), ipMaxFailures},
+	}
+	started, err := us.acquireLogin(ctx, keys)
 	if err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	user, err := us.storage.User.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", ErrUserNotFound
-		}
-		return "", err
+		return model.AuthTokens{}, err
+	}
+
+	if user.ID == 0 || user.DeletedAt != nil {
+		checkPassword(req.Password, dummyPasswordHash())
+		return model.AuthTokens{}, loginFailed(keys[0].key, started, ErrUnauthorized)
 	}
 
 	if !checkPassword(req.Password, user.Password) {
-		return "", ErrUnauthorized
+		return model.AuthTokens{}, loginFailed(keys[0].key, started, ErrUnauthorized)
 	}
-	token, err := GenerateToken(user.ID, user.Email, user.Role)
+
+	err = us.limiter.Reset(ctx, keys[0].key)
 	if err != nil {
 		zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
-		return "", err
+		return model.AuthTokens{}, err
+	}
+	err = us.limiter.Forgive(ctx, keys[1].key, started[1])
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
+		return model.AuthTokens{}, err
 	}
 
-	return token, nil
+	if user.EmailVerifiedAt == nil && !authPolicy.UnverifiedLogin {
+		return model.AuthTokens{}, ErrEmailNotVerified
+	}
+	tokens, err := us.startSession(ctx, user)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	return tokens, nil
 
 }
 
+type loginKey struct {
+	key         string
+	maxFailures int
+}
+
+// acquireLogin counts the attempt for every key and returns the lockouts it
+// started, one per key. If a key is locked out, the keys counted before it
+// are forgiven and a LoginLockedError is returned.
+func (us *userService) acquireLogin(ctx context.Context, keys []loginKey) ([]time.Time, error) {
+	started := make([]time.Time, 0, len(keys))
+	for _, k := range keys {
+		until, allowed, err := us.limiter.Acquire(ctx, k.key, k.maxFailures)
+		if err == nil && !allowed {
+			err = &LoginLockedError{Until: until}
+		}
+		if err != nil {
+			for i, lockedUntil := range started {
+				if err := us.limiter.Forgive(ctx, keys[i].key, lockedUntil); err != nil {
+					zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
+				}
+			}
+			if !errors.Is(err, ErrLoginLocked) {
+				zlog.Logger.Error().Msgf("service.UserService.Login error: %v", err)
+			}
+			return nil, err
+		}
+		started = append(started, until)
+	}
+	return started, nil
+}
+
+// loginFailed returns a lockout error if the failed attempt started one,
+// otherwise cause. The failure itself was counted by acquireLogin.
+func loginFailed(accountKey string, started []time.Time, cause error) error {
+	var lockedUntil time.Time
+	for _, until := range started {
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	if !lockedUntil.IsZero() {
+		zlog.Logger.Warn().Msgf("service.UserService.Login: %s locked out until %s", accountKey, lockedUntil)
+		return &LoginLockedError{Until: lockedUntil}
+	}
+	return cause
+}
+
+// UnlockUser lifts the lockout of the account. Lockouts of client addresses
+// expire on their own.
+func (us *userService) UnlockUser(ctx context.Context, userID int) error {
+	user, err := us.storage.User.GetByID(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.UnlockUser error: %v", err)
+		return err
+	}
+	if user.ID == 0 {
+		return ErrUserNotFound
+	}
+
+	err = us.limiter.Reset(ctx, accountLoginKey(user.Email))
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.UnlockUser error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ChangeRole gives the user another role. Admins cannot change their own role,
+// so there is always at least one admin left.
+func (us *userService) ChangeRole(ctx context.Context, actorID, userID int, role string) error {
+	if !slices.Contains(model.Roles, role) {
+		return ErrInvalidRole
+	}
+	if actorID == userID {
+		return ErrOwnRole
+	}
+
+	return us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		user, err := s.User.GetByID(ctx, userID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.UserService.ChangeRole error: %v", err)
+			return err
+		}
+		if user.ID == 0 {
+			return ErrUserNotFound
+		}
+		if user.Role == role {
+			return nil
+		}
+
+		err = setRole(ctx, s, userID, role)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.UserService.ChangeRole error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (us *userService) GetRolePermissions(ctx context.Context) ([]model.RolePermissions, error) {
+	permissions, err := us.storage.Permission.GetAll(ctx)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.GetRolePermissions error: %v", err)
+		return nil, err
+	}
+
+	res := make([]model.RolePermissions, 0, len(model.Roles))
+	for _, role := range model.Roles {
+		p := permissions[role]
+		if p == nil {
+			p = []string{}
+		}
+		res = append(res, model.RolePermissions{Role: role, Permissions: p})
+	}
+	return res, nil
+}
+
+// SetRolePermissions replaces the permissions of the role. Tokens already
+// issued keep the old permissions until they are refreshed. The admin role
+// always keeps users:manage, so the mapping can still be repaired through the
+// API.
+func (us *userService) SetRolePermissions(ctx context.Context, role string, permissions []string) error {
+	if !slices.Contains(model.Roles, role) {
+		return ErrInvalidRole
+	}
+
+	unique := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if !slices.Contains(model.Permissions, p) {
+			return fmt.Errorf("%w: %s", ErrInvalidPermission, p)
+		}
+		if !slices.Contains(unique, p) {
+			unique = append(unique, p)
+		}
+	}
+
+	if role == model.RoleAdmin && !slices.Contains(unique, model.PermissionUsersManage) {
+		return ErrAdminPermissionRequired
+	}
+
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		return s.Permission.SetForRole(ctx, role, unique)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.SetRolePermissions error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// EnsureAdmin promotes the account with the email to admin, or creates a
+// verified admin account when there is none. It reports whether the account
+// was created.
+func (us *userService) EnsureAdmin(ctx context.Context, email, password string) (bool, error) {
+	email = normalizeEmail(email)
+	if !validEmail(email) {
+		return false, ErrInvalidEmail
+	}
+
+	var created bool
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		user, err := s.User.GetByEmail(ctx, email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if user.ID != 0 {
+			if user.Role == model.RoleAdmin {
+				return nil
+			}
+			return setRole(ctx, s, user.ID, model.RoleAdmin)
+		}
+
+		if len(password) < minPasswordLength {
+			return ErrWeakPassword
+		}
+		hashedPassword, err := hashPassword(password)
+		if err != nil {
+			return err
+		}
+
+		err = s.User.Create(ctx, model.UserInCreate{Email: email, Password: hashedPassword}, model.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.User.GetByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		created = true
+		return s.User.MarkEmailVerified(ctx, user.ID)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.EnsureAdmin error: %v", err)
+		return false, err
+	}
+	return created, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// works once: presenting an already rotated token revokes the whole session,
+// since either the client or an attacker holds a stolen copy.
+func (us *userService) Refresh(ctx context.Context, refreshToken string) (model.AuthTokens, error) {
+	newRefresh, err := generateSecret()
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+		return model.AuthTokens{}, err
+	}
+	refreshExpiresAt := time.Now().Add(refreshTokenTTL)
+
+	session, err := us.storage.Session.Rotate(ctx, hashSecret(refreshToken), hashSecret(newRefresh), refreshExpiresAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+			return model.AuthTokens{}, err
+		}
+
+		reused, err := us.storage.Session.RevokeByPrevious(ctx, hashSecret(refreshToken))
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+			return model.AuthTokens{}, err
+		}
+		if reused {
+			zlog.Logger.Warn().Msgf("service.UserService.Refresh: rotated refresh token reused, session revoked")
+		}
+		return model.AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	user, err := us.storage.User.GetByID(ctx, session.UserID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	permissions, err := us.storage.Permission.GetByRole(ctx, user.Role)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	expiresAt := time.Now().Add(accessTokenTTL)
+	token, err := GenerateToken(user.ID, session.ID, user.Email, user.Role, permissions, expiresAt)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Refresh error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	return model.AuthTokens{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     newRefresh,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (us *userService) Logout(ctx context.Context, refreshToken string) error {
+	_, err := us.storage.Session.RevokeByRefresh(ctx, hashSecret(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidRefreshToken
+		}
+		zlog.Logger.Error().Msgf("service.UserService.Logout error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Authenticate validates an access token and checks that its session has not
+// been revoked or expired.
+func (us *userService) Authenticate(ctx context.Context, token string) (*Claims, error) {
+	claims, err := ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := us.storage.Session.IsActive(ctx, claims.SessionID, claims.UserID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.Authenticate error: %v", err)
+		return nil, err
+	}
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+func (us *userService) RevokeSessions(ctx context.Context, userID int) (int64, error) {
+	count, err := us.storage.Session.RevokeAllByUser(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.RevokeSessions error: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// RequestPasswordReset sends a single-use reset token through the outbox.
+// Unknown emails are ignored silently so the endpoint does not reveal which
+// addresses are registered.
+func (us *userService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := us.storage.User.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		// Unknown accounts get the same answer as existing ones.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		zlog.Logger.Error().Msgf("service.UserService.RequestPasswordReset error: %v", err)
+		return err
+	}
+	if user.DeletedAt != nil {
+		return nil
+	}
+
+	token, err := generateSecret()
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.RequestPasswordReset error: %v", err)
+		return err
+	}
+
+	err = us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := s.PasswordReset.DeleteByUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.PasswordReset.Create(ctx, user.ID, hashSecret(token), time.Now().Add(resetTokenTTL))
+		if err != nil {
+			return err
+		}
+
+		return enqueueNotifications(ctx, s, []model.NotificationInCreate{buildPasswordResetMessage(user.ID, token)})
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.RequestPasswordReset error: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (us *userService) ResetPassword(ctx context.Context, req model.PasswordResetRequest) error {
+	if len(req.NewPassword) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ResetPassword error: %v", err)
+		return err
+	}
+
+	err = us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		userID, err := s.PasswordReset.Consume(ctx, hashSecret(req.Token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		return setPassword(ctx, s, userID, hashedPassword)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ResetPassword error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ChangePassword signs the user out everywhere and returns a fresh token pair
+// so that the client making the change stays signed in.
+func (us *userService) ChangePassword(ctx context.Context, userID int, req model.PasswordChangeRequest) (model.AuthTokens, error) {
+	if len(req.NewPassword) < minPasswordLength {
+		return model.AuthTokens{}, ErrWeakPassword
+	}
+
+	user, err := us.storage.User.GetByID(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ChangePassword error: %v", err)
+		return model.AuthTokens{}, err
+	}
+	if user.ID == 0 {
+		return model.AuthTokens{}, ErrUserNotFound
+	}
+
+	if !checkPassword(req.OldPassword, user.Password) {
+		return model.AuthTokens{}, ErrWrongPassword
+	}
+
+	hashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ChangePassword error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	err = us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		return setPassword(ctx, s, userID, hashedPassword)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ChangePassword error: %v", err)
+		return model.AuthTokens{}, err
+	}
+
+	tokens, err := us.startSession(ctx, user)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ChangePassword error: %v", err)
+		return model.AuthTokens{}, err
+	}
+	return tokens, nil
+}
+
+func (us *userService) VerifyEmail(ctx context.Context, token string) error {
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		userID, err := s.EmailVerification.Consume(ctx, hashSecret(token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidVerifyToken
+			}
+			return err
+		}
+
+		err = s.User.MarkEmailVerified(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return s.EmailVerification.DeleteByUser(ctx, userID)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.VerifyEmail error: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (us *userService) ResendVerification(ctx context.Context, userID int) error {
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		user, err := s.User.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.ID == 0 {
+			return ErrUserNotFound
+		}
+		if user.EmailVerifiedAt != nil {
+			return ErrEmailAlreadyVerified
+		}
+		return us.sendVerification(ctx, s, user)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.ResendVerification error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// sendVerification replaces pending verification tokens of the user with a
+// new one and queues it for the email channel only, since delivery is what
+// proves ownership of the address.
+func (us *userService) sendVerification(ctx context.Context, s *repository.Storage, user model.UserInRepo) error {
+	token, err := generateSecret()
+	if err != nil {
+		return err
+	}
+
+	err = s.EmailVerification.DeleteByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.EmailVerification.Create(ctx, user.ID, hashSecret(token), time.Now().Add(verifyTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	return enqueueNotifications(ctx, s, []model.NotificationInCreate{us.buildVerificationMessage(user.ID, token)})
+}
+
+func (us *userService) buildVerificationMessage(userID int, token string) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString("Подтвердите email для аккаунта EventBooker.\n")
+	if us.publicURL != "" {
+		builder.WriteString(fmt.Sprintf("Перейдите по ссылке: %s/?verify_token=%s\n", us.publicURL, token))
+	}
+	builder.WriteString(fmt.Sprintf("Код подтверждения: %s\n", token))
+	builder.WriteString(fmt.Sprintf("Код действует %s.\n", verifyTokenTTL))
+	return model.NotificationInCreate{
+		UserID:    userID,
+		EventType: model.NotificationTypeEmailVerification,
+		Subject:   "Подтверждение email EventBooker",
+		Text:      builder.String(),
+		Channel:   model.NotificationChannelEmail,
+	}
+}
+
+// checkCanBook rejects users with an unverified email unless the
+// configuration lets them book.
+func checkCanBook(ctx context.Context, s *repository.Storage, userID int) error {
+	if authPolicy.UnverifiedBooking {
+		return nil
+	}
+
+	user, err := s.User.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+// setPassword stores the new hash and ends every session and pending reset of
+// the user, so an attacker holding old credentials is locked out.
+func setPassword(ctx context.Context, s *repository.Storage, userID int, passwordHash string) error {
+	err := s.User.UpdatePassword(ctx, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	err = s.PasswordReset.DeleteByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Session.RevokeAllByUser(ctx, userID)
+	return err
+}
+
+// setRole changes the role and revokes the sessions of the user, since access
+// tokens carry the role they were issued with.
+func setRole(ctx context.Context, s *repository.Storage, userID int, role string) error {
+	err := s.User.UpdateRole(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Session.RevokeAllByUser(ctx, userID)
+	return err
+}
+
+func buildPasswordResetMessage(userID int, token string) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString("Вы запросили сброс пароля EventBooker.\n")
+	builder.WriteString(fmt.Sprintf("Код для сброса: %s\n", token))
+	builder.WriteString(fmt.Sprintf("Код действует %s и может быть использован один раз.\n", resetTokenTTL))
+	builder.WriteString("Если вы не запрашивали сброс, просто проигнорируйте это сообщение.\n")
+	return model.NotificationInCreate{
+		UserID:    userID,
+		EventType: model.NotificationTypePasswordReset,
+		Subject:   "Сброс пароля EventBooker",
+		Text:      builder.String(),
+	}
+}
+
+func (us *userService) startSession(ctx context.Context, user model.UserInRepo) (model.AuthTokens, error) {
+	refreshToken, err := generateSecret()
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	refreshExpiresAt := time.Now().Add(refreshTokenTTL)
+
+	permissions, err := us.storage.Permission.GetByRole(ctx, user.Role)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	sessionID, err := us.storage.Session.Create(ctx, user.ID, hashSecret(refreshToken), refreshExpiresAt)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	expiresAt := time.Now().Add(accessTokenTTL)
+	token, err := GenerateToken(user.ID, sessionID, user.Email, user.Role, permissions, expiresAt)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	return model.AuthTokens{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (us *userService) GetByTgChatID(ctx context.Context, chatID int64) (model.UserInResponse, error) {
+	u, err := us.storage.User.GetByTgChatID(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserInResponse{}, ErrUserNotFound
+		}
+		zlog.Logger.Error().Msgf("service.UserService.GetByTgChatID error: %v", err)
+		return model.UserInResponse{}, err
+	}
+
+	return toUserResponse(u), nil
+}
+
+// CreateTgLinkCode issues a one-time code the user sends to the bot as
+// "/start <code>". Only a hash is stored, and issuing a new code revokes the
+// previous ones.
+func (us *userService) CreateTgLinkCode(ctx context.Context, userID int) (model.TgLinkCodeInResponse, error) {
+	code, err := generateSecret()
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.CreateTgLinkCode error: %v", err)
+		return model.TgLinkCodeInResponse{}, err
+	}
+	expiresAt := time.Now().Add(tgLinkCodeTTL)
+
+	err = us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := s.TgLinkCode.DeleteByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return s.TgLinkCode.Create(ctx, userID, hashSecret(code), expiresAt)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.CreateTgLinkCode error: %v", err)
+		return model.TgLinkCodeInResponse{}, err
+	}
+
+	res := model.TgLinkCodeInResponse{Code: code, ExpiresAt: expiresAt}
+	if us.botUsername != "" {
+		res.Link = fmt.Sprintf("https://t.me/%s?start=%s", us.botUsername, code)
+	}
+	return res, nil
+}
+
+func (us *userService) LinkTelegram(ctx context.Context, code string, chatID int64) (model.UserInResponse, error) {
+	var user model.UserInRepo
+	err := us.storage.WithTx(ctx, func(s *repository.Storage) error {
+		userID, err := s.TgLinkCode.Consume(ctx, hashSecret(code))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidLinkCode
+			}
+			return err
+		}
+
+		err = s.User.LinkTgChat(ctx, userID, chatID)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.User.GetByID(ctx, userID)
+		return err
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.LinkTelegram error: %v", err)
+		return model.UserInResponse{}, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+func (us *userService) UnlinkTelegram(ctx context.Context, userID int) error {
+	err := us.storage.User.UnlinkTgChat(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.UserService.UnlinkTelegram error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// generateSecret returns a random URL-safe token, short enough for a
+// Telegram deep-link start parameter.
+func generateSecret() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
 func hashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -104,18 +902,30 @@ func hashPassword(password string) (string, error) {
 	return string(bytes), nil
 }
 
+// dummyPasswordHash is compared against when there is no account to check,
+// so a login for an unknown email costs the same bcrypt work.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := hashPassword("dummy password for unknown accounts")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
 func checkPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return errors.Is(err, nil)
 }
 
-func GenerateToken(userID int, email, role string) (string, error) {
+func GenerateToken(userID, sessionID int, email, role string, permissions []string, expiresAt time.Time) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "event-booker",
@@ -162,12 +972,7 @@ func (us *userService) GetListUsers(ctx context.Context, req model.UserGetReques
 
 	usersInResponse := make([]model.UserInResponse, 0, len(usersInRepo))
 	for _, u := range usersInRepo {
-		usersInResponse = append(usersInResponse, model.UserInResponse{
-			ID:        u.ID,
-			Email:     u.Email,
-			TgChatID:  u.TgChatID,
-			CreatedAt: u.CreatedAt,
-		})
+		usersInResponse = append(usersInResponse, toUserResponse(u))
 	}
 
 	return usersInResponse, nil
@@ -177,3 +982,19 @@ func (us *userService) GetListUsers(ctx context.Context, req model.UserGetReques
 func (us *userService) GetCountUsers(ctx context.Context) (int, error) {
 	return us.storage.User.GetCountUsers(ctx)
 }
+
+func toUserResponse(u model.UserInRepo) model.UserInResponse {
+	return model.UserInResponse{
+		ID:              u.ID,
+		Email:           u.Email,
+		Role:            u.Role,
+		TgChatID:        u.TgChatID,
+		CreatedAt:       u.CreatedAt,
+		EmailVerified:   u.EmailVerifiedAt != nil,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		Name:            u.Name,
+		Phone:           u.Phone,
+		Locale:          u.Locale,
+		DeletedAt:       u.DeletedAt,
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+const (
+	maxVenueNameLength   = 100
+	maxSectionNameLength = 50
+	maxRowLabelLength    = 10
+	maxSeatsInRow        = 500
+)
+
+type VenueService interface {
+	Create(ctx context.Context, v model.VenueInput) (model.VenueInResponse, error)
+	GetByID(ctx context.Context, id int) (model.VenueInResponse, error)
+	GetList(ctx context.Context) ([]model.VenueInResponse, error)
+	Update(ctx context.Context, id int, v model.VenueInput) (model.VenueInResponse, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type venueService struct {
+	storage *repository.Storage
+}
+
+func NewVenueService(s *repository.Storage) VenueService {
+	return &venueService{storage: s}
+}
+
+func (vs *venueService) Create(ctx context.Context, v model.VenueInput) (model.VenueInResponse, error) {
+	venue, err := normalizeVenue(v)
+	if err != nil {
+		return model.VenueInResponse{}, err
+	}
+	if venue.Seats == nil {
+		return model.VenueInResponse{}, ErrInvalidVenue
+	}
+
+	var id int
+	err = vs.storage.WithTx(ctx, func(s *repository.Storage) error {
+		id, err = s.Venue.Create(ctx, venue)
+		return err
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.VenueService.Create error: %v", err)
+		return model.VenueInResponse{}, err
+	}
+	return vs.GetByID(ctx, id)
+}
+
+// GetByID returns the venue with its seat map.
+func (vs *venueService) GetByID(ctx context.Context, id int) (model.VenueInResponse, error) {
+	venue, err := vs.storage.Venue.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.VenueInResponse{}, ErrVenueNotFound
+		}
+		zlog.Logger.Error().Msgf("service.VenueService.GetByID error: %v", err)
+		return model.VenueInResponse{}, err
+	}
+
+	seats, err := vs.storage.Venue.GetSeats(ctx, id)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.VenueService.GetByID error: %v", err)
+		return model.VenueInResponse{}, err
+	}
+
+	res := toVenueResponse(venue)
+	res.Seats = toSeatResponses(seats)
+	return res, nil
+}
+
+func (vs *venueService) GetList(ctx context.Context) ([]model.VenueInResponse, error) {
+	venues, err := vs.storage.Venue.GetList(ctx)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.VenueService.GetList error: %v", err)
+		return nil, err
+	}
+
+	res := make([]model.VenueInResponse, 0, len(venues))
+	for _, v := range venues {
+		res = append(res, toVenueResponse(v))
+	}
+	return res, nil
+}
+
+// Update renames the venue and, when sections are given, replaces its seat
+// map. The seat map of a venue used by events stays as it is, otherwise
+// sold seats would change under their bookings.
+func (vs *venueService) Update(ctx context.Context, id int, v model.VenueInput) (model.VenueInResponse, error) {
+	venue, err := normalizeVenue(v)
+	if err != nil {
+		return model.VenueInResponse{}, err
+	}
+
+	err = vs.storage.WithTx(ctx, func(s *repository.Storage) error {
+		_, err := s.Venue.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrVenueNotFound
+			}
+			return err
+		}
+
+		if venue.Seats != nil {
+			used, err := s.Venue.IsUsed(ctx, id)
+			if err != nil {
+				return err
+			}
+			if used {
+				return ErrVenueInUse
+			}
+		}
+
+		return s.Venue.Update(ctx, id, venue)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrVenueNotFound) && !errors.Is(err, ErrVenueInUse) {
+			zlog.Logger.Error().Msgf("service.VenueService.Update error: %v", err)
+		}
+		return model.VenueInResponse{}, err
+	}
+	return vs.GetByID(ctx, id)
+}
+
+// Delete removes a venue no event has ever used.
+func (vs *venueService) Delete(ctx context.Context, id int) error {
+	return vs.storage.WithTx(ctx, func(s *repository.Storage) error {
+		_, err := s.Venue.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrVenueNotFound
+			}
+			zlog.Logger.Error().Msgf("service.VenueService.Delete error: %v", err)
+			return err
+		}
+
+		used, err := s.Venue.IsUsed(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.VenueService.Delete error: %v", err)
+			return err
+		}
+		if used {
+			return ErrVenueInUse
+		}
+
+		err = s.Venue.Delete(ctx, id)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.VenueService.Delete error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// normalizeVenue validates the venue and expands its sections into seats.
+// Seats stay nil when no sections are given.
+func normalizeVenue(v model.VenueInput) (model.VenueInCreate, error) {
+	res := model.VenueInCreate{
+		Name:    strings.TrimSpace(v.Name),
+		Address: strings.TrimSpace(v.Address),
+	}
+	if res.Name == "" || utf8.RuneCountInString(res.Name) > maxVenueNameLength {
+		return model.VenueInCreate{}, ErrInvalidVenue
+	}
+
+	if v.Sections == nil {
+		return res, nil
+	}
+
+	seats, err := expandSeatMap(v.Sections)
+	if err != nil {
+		return model.VenueInCreate{}, err
+	}
+	res.Seats = seats
+	return res, nil
+}
+
+// expandSeatMap turns the section and row description into single seats,
+// numbered from 1 in every row.
+func expandSeatMap(sections []model.SectionInput) ([]model.SeatInCreate, error) {
+	var seats []model.SeatInCreate
+	names := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		name := strings.TrimSpace(section.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxSectionNameLength || len(section.Rows) == 0 {
+			return nil, ErrInvalidVenue
+		}
+		if names[name] {
+			return nil, ErrDuplicateSeat
+		}
+		names[name] = true
+
+		rows := make(map[string]bool, len(section.Rows))
+		for _, r := range section.Rows {
+			row := strings.TrimSpace(r.Row)
+			if row == "" || utf8.RuneCountInString(row) > maxRowLabelLength {
+				return nil, ErrInvalidVenue
+			}
+			if rows[row] {
+				return nil, ErrDuplicateSeat
+			}
+			rows[row] = true
+
+			if r.Seats <= 0 || r.Seats > maxSeatsInRow {
+				return nil, ErrInvalidVenue
+			}
+			for _, n := range r.Accessible {
+				if n < 1 || n > r.Seats {
+					return nil, ErrInvalidVenue
+				}
+			}
+
+			for n := 1; n <= r.Seats; n++ {
+				seats = append(seats, model.SeatInCreate{
+					Section:    name,
+					Row:        row,
+					Number:     n,
+					Accessible: slices.Contains(r.Accessible, n),
+				})
+			}
+		}
+	}
+
+	if len(seats) == 0 {
+		return nil, ErrInvalidVenue
+	}
+	return seats, nil
+}
+
+// assignSeats splits the venue seats between the tiers of a new event and
+// sets the tier capacities to match. A single tier without sections gets the
+// whole venue, otherwise every section goes to exactly one tier. Returns the
+// seat ids of every tier in the order of tiers.
+func assignSeats(tiers []model.TicketTypeInput, seats []model.SeatInRepo) ([][]int, error) {
+	owner := make(map[string]int)
+	for _, s := range seats {
+		owner[s.Section] = -1
+	}
+
+	wholeVenue := len(tiers) == 1 && len(tiers[0].Sections) == 0
+	for i := range tiers {
+		if wholeVenue {
+			for section := range owner {
+				owner[section] = i
+			}
+			break
+		}
+		if len(tiers[i].Sections) == 0 {
+			return nil, ErrInvalidSeating
+		}
+		for j, section := range tiers[i].Sections {
+			section = strings.TrimSpace(section)
+			tiers[i].Sections[j] = section
+			current, ok := owner[section]
+			if !ok || current != -1 {
+				return nil, ErrInvalidSeating
+			}
+			owner[section] = i
+		}
+	}
+
+	res := make([][]int, len(tiers))
+	for _, s := range seats {
+		i := owner[s.Section]
+		if i == -1 {
+			return nil, ErrInvalidSeating
+		}
+		res[i] = append(res[i], s.ID)
+	}
+
+	for i := range tiers {
+		tiers[i].Capacity = len(res[i])
+	}
+	return res, nil
+}
+
+// checkSeats makes sure the requested seats are sold at the event, free at
+// now and belong to one tier. Returns the id of that tier.
+func checkSeats(ctx context.Context, s *repository.Storage, eventID int, seatIDs []int, now time.Time) (int, error) {
+	seats, err := s.Seat.GetEventSeatsByID(ctx, eventID, seatIDs, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(seats) != len(seatIDs) {
+		return 0, ErrSeatNotFound
+	}
+
+	ticketTypeID := seats[0].TicketTypeID
+	for _, seat := range seats {
+		if seat.TicketTypeID != ticketTypeID {
+			return 0, ErrSeatsMixedTiers
+		}
+		if seat.State != model.SeatStateFree {
+			return 0, ErrSeatUnavailable
+		}
+	}
+	return ticketTypeID, nil
+}
+
+func isSeatError(err error) bool {
+	return errors.Is(err, ErrSeatsRequired) || errors.Is(err, ErrSeatsNotAssigned) ||
+		errors.Is(err, ErrSeatNotFound) || errors.Is(err, ErrSeatUnavailable) || errors.Is(err, ErrSeatsMixedTiers)
+}
+
+func toVenueResponse(v model.VenueInRepo) model.VenueInResponse {
+	return model.VenueInResponse{
+		ID:        v.ID,
+		Name:      v.Name,
+		Address:   v.Address,
+		SeatCount: v.SeatCount,
+		CreatedAt: v.CreatedAt,
+	}
+}
+
+func toSeatResponse(s model.SeatInRepo) model.SeatInResponse {
+	return model.SeatInResponse{
+		ID:         s.ID,
+		Section:    s.Section,
+		Row:        s.Row,
+		Number:     s.Number,
+		Accessible: s.Accessible,
+	}
+}
+
+func toSeatResponses(seats []model.SeatInRepo) []model.SeatInResponse {
+	res := make([]model.SeatInResponse, 0, len(seats))
+	for _, s := range seats {
+		res = append(res, toSeatResponse(s))
+	}
+	return res
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestExpandSeatMap(t *testing.T) {
+	row := func(label string, seats int, accessible ...int) model.RowInput {
+		return model.RowInput{Row: label, Seats: seats, Accessible: accessible}
+	}
+	section := func(name string, rows ...model.RowInput) model.SectionInput {
+		return model.SectionInput{Name: name, Rows: rows}
+	}
+
+	tests := []struct {
+		name     string
+		sections []model.SectionInput
+		err      error
+	}{
+		{"valid", []model.SectionInput{section("Партер", row("1", 10, 1, 2)), section("Балкон", row("A", 5))}, nil},
+		{"no sections", []model.SectionInput{}, ErrInvalidVenue},
+		{"section without rows", []model.SectionInput{section("Партер")}, ErrInvalidVenue},
+		{"empty section name", []model.SectionInput{section(" ", row("1", 10))}, ErrInvalidVenue},
+		{"duplicate section", []model.SectionInput{section("A", row("1", 1)), section("A", row("2", 1))},
+			ErrDuplicateSeat},
+		{"duplicate row", []model.SectionInput{section("A", row("1", 1), row(" 1", 2))}, ErrDuplicateSeat},
+		{"empty row", []model.SectionInput{section("A", row("1", 0))}, ErrInvalidVenue},
+		{"accessible outside row", []model.SectionInput{section("A", row("1", 5, 6))}, ErrInvalidVenue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expandSeatMap(tt.sections)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	seats, err := expandSeatMap([]model.SectionInput{section(" Партер ", row("1", 3, 2), row("2", 2))})
+	require.NoError(t, err)
+	require.Len(t, seats, 5)
+	assert.Equal(t, model.SeatInCreate{Section: "Партер", Row: "1", Number: 2, Accessible: true}, seats[1])
+	assert.Equal(t, model.SeatInCreate{Section: "Партер", Row: "2", Number: 2}, seats[4])
+}
+
+func TestAssignSeats(t *testing.T) {
+	seats := []model.SeatInRepo{
+		{ID: 1, Section: "Партер"}, {ID: 2, Section: "Партер"}, {ID: 3, Section: "Балкон"}, {ID: 4, Section: "Ложа"},
+	}
+	tier := func(sections ...string) model.TicketTypeInput {
+		return model.TicketTypeInput{Name: "t", Sections: sections}
+	}
+
+	tests := []struct {
+		name  string
+		tiers []model.TicketTypeInput
+		ids   [][]int
+		err   error
+	}{
+		{"single tier takes the venue", []model.TicketTypeInput{tier()}, [][]int{{1, 2, 3, 4}}, nil},
+		{"split by section", []model.TicketTypeInput{tier("Партер", "Ложа"), tier("Балкон")},
+			[][]int{{1, 2, 4}, {3}}, nil},
+		{"section left over", []model.TicketTypeInput{tier("Партер"), tier("Балкон")}, nil, ErrInvalidSeating},
+		{"section twice", []model.TicketTypeInput{tier("Партер", "Ложа"), tier("Балкон", "Партер")}, nil,
+			ErrInvalidSeating},
+		{"unknown section", []model.TicketTypeInput{tier("Партер", "Балкон", "Ложа", "Сцена")}, nil,
+			ErrInvalidSeating},
+		{"tier without sections", []model.TicketTypeInput{tier("Партер", "Балкон", "Ложа"), tier()}, nil,
+			ErrInvalidSeating},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := assignSeats(tt.tiers, seats)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.ids, ids)
+			for i := range ids {
+				assert.Equal(t, len(ids[i]), tt.tiers[i].Capacity, "capacity follows the seats")
+			}
+		})
+	}
+}
+
+func TestBook_AssignedSeating(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+
+	userIDs := make([]int, 2)
+	for i, email := range []string{"seat1@test.local", "seat2@test.local"} {
+		err := db.QueryRow(`INSERT INTO users (email, password, role, created_at, email_verified_at)
+			VALUES ($1, 'x', 'user', now(), now()) RETURNING user_id`, email).Scan(&userIDs[i])
+		require.NoError(t, err)
+	}
+
+	venue, err := NewVenueService(storage).Create(ctx, model.VenueInput{
+		Name: "Зал",
+		Sections: []model.SectionInput{
+			{Name: "Партер", Rows: []model.RowInput{{Row: "1", Seats: 3, Accessible: []int{1}}}},
+			{Name: "Балкон", Rows: []model.RowInput{{Row: "1", Seats: 2}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, venue.Seats, 5)
+
+	es := NewEventService(storage, nil)
+	err = es.CreateEvent(ctx, model.Actor{UserID: userIDs[0]}, model.EventInCreate{
+		Title:              "Концерт",
+		EventDate:          time.Now().Add(48 * time.Hour),
+		ReservationPeriod:  "1h",
+		BookingConfimation: true,
+		VenueID:            &venue.ID,
+		TicketTypes: []model.TicketTypeInput{
+			{Name: "Партер", Sections: []string{"Партер"}},
+			{Name: "Балкон", Sections: []string{"Балкон"}},
+		},
+	})
+	require.NoError(t, err)
+
+	var eventID int
+	err = db.QueryRow(`SELECT event_id FROM events WHERE venue_id=$1`, venue.ID).Scan(&eventID)
+	require.NoError(t, err)
+
+	event, err := es.GetByID(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, event.TotalPlace)
+
+	stalls, balcony := venue.Seats[0].ID, venue.Seats[3].ID
+
+	bs := NewBookingService(storage, nil)
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userIDs[0], EventID: eventID, Quantity: 2})
+	assert.ErrorIs(t, err, ErrSeatsRequired)
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userIDs[0], EventID: eventID, SeatIDs: []int{stalls, balcony}})
+	assert.ErrorIs(t, err, ErrSeatsMixedTiers)
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userIDs[0], EventID: eventID, SeatIDs: []int{-1}})
+	assert.ErrorIs(t, err, ErrSeatNotFound)
+
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userIDs[0], EventID: eventID, SeatIDs: []int{stalls}}))
+	err = bs.Book(ctx, model.BookingInCreate{UserID: userIDs[1], EventID: eventID, SeatIDs: []int{stalls}})
+	assert.ErrorIs(t, err, ErrSeatUnavailable)
+
+	seatMap, err := es.GetSeatMap(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, seatMap.Held)
+	assert.Equal(t, 4, seatMap.Free)
+	assert.Equal(t, model.SeatStateHeld, seatMap.Seats[0].State)
+	assert.True(t, seatMap.Seats[0].Accessible)
+
+	// The hold ends with expires_at, before the expiry job runs.
+	_, err = db.Exec(`UPDATE booking SET expires_at = now() - interval '1 minute' WHERE user_id=$1`, userIDs[0])
+	require.NoError(t, err)
+
+	seatMap, err = es.GetSeatMap(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, model.SeatStateFree, seatMap.Seats[0].State)
+
+	require.NoError(t, bs.Book(ctx, model.BookingInCreate{UserID: userIDs[1], EventID: eventID, SeatIDs: []int{stalls}}))
+
+	bookings, err := bs.GetByUserID(ctx, model.BookingGetRequest{UserID: userIDs[1], Mode: "next", PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, bookings, 1)
+	require.Len(t, bookings[0].Seats, 1)
+	assert.Equal(t, stalls, bookings[0].Seats[0].ID)
+
+	_, err = NewVenueService(storage).Update(ctx, venue.ID, model.VenueInput{Name: "Зал",
+		Sections: []model.SectionInput{{Name: "A", Rows: []model.RowInput{{Row: "1", Seats: 1}}}}})
+	assert.ErrorIs(t, err, ErrVenueInUse)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EventBooker/internal/model"
+)
+
+func TestValidEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		valid bool
+	}{
+		{"user@mail.com", true},
+		{"first.last+tag@sub.example.org", true},
+		{"", false},
+		{"not-an-email", false},
+		{"User <user@mail.com>", false},
+		{"user@", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.valid, validEmail(tt.email))
+		})
+	}
+}
+
+func TestVerifyEmail_UnlocksBooking(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+	authPolicy.UnverifiedLogin, authPolicy.UnverifiedBooking = true, false
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	_, err := us.CreateUser(ctx, model.UserInCreate{Email: "bad-email", Password: "secret"})
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "verify@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, tokens.Token)
+	require.NoError(t, err, "unverified accounts may log in by default")
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Verify test', '', $1, 'published', 10, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 0)
+
+	bs := NewBookingService(storage, nil)
+	err = bs.Book(ctx, model.BookingInCreate{UserID: claims.UserID, EventID: eventID})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+
+	var channel, text string
+	err = db.QueryRow(`SELECT channel, message FROM notification_outbox WHERE event_type=$1`,
+		model.NotificationTypeEmailVerification).Scan(&channel, &text)
+	require.NoError(t, err)
+	assert.Equal(t, model.NotificationChannelEmail, channel)
+	match := regexp.MustCompile(`Код подтверждения: (\S+)`).FindStringSubmatch(text)
+	require.Len(t, match, 2)
+
+	require.NoError(t, us.VerifyEmail(ctx, match[1]))
+	assert.ErrorIs(t, us.VerifyEmail(ctx, match[1]), ErrInvalidVerifyToken)
+	assert.ErrorIs(t, us.ResendVerification(ctx, claims.UserID), ErrEmailAlreadyVerified)
+
+	err = bs.Book(ctx, model.BookingInCreate{UserID: claims.UserID, EventID: eventID})
+	assert.NoError(t, err)
+}
+
+func TestLogin_UnverifiedRejectedByPolicy(t *testing.T) {
+	storage, _ := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+	authPolicy.UnverifiedLogin = false
+	t.Cleanup(func() { authPolicy.UnverifiedLogin = true })
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "strict@test.local", Password: "secret"})
+	require.NoError(t, err)
+	assert.Empty(t, tokens.Token, "no session before verification")
+
+	_, err = us.Login(ctx, model.UserLoginRequest{Email: "strict@test.local", Password: "secret"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+}
+
+func TestResendVerification_ReplacesTokenAndBookingCommits(t *testing.T) {
+	storage, db := setupTestStorage(t)
+	ctx := context.Background()
+	jwtSecret = []byte("test-secret")
+	authPolicy.UnverifiedLogin, authPolicy.UnverifiedBooking = true, false
+
+	us := &userService{storage: storage, limiter: NewMemoryLoginLimiter()}
+	tokens, err := us.CreateUser(ctx, model.UserInCreate{Email: "resend@test.local", Password: "secret"})
+	require.NoError(t, err)
+	claims, err := us.Authenticate(ctx, tokens.Token)
+	require.NoError(t, err)
+
+	require.NoError(t, us.ResendVerification(ctx, claims.UserID))
+
+	rows, err := db.Query(`SELECT message FROM notification_outbox WHERE event_type=$1 ORDER BY notification_id`,
+		model.NotificationTypeEmailVerification)
+	require.NoError(t, err)
+	var codes []string
+	for rows.Next() {
+		var text string
+		require.NoError(t, rows.Scan(&text))
+		match := regexp.MustCompile(`Код подтверждения: (\S+)`).FindStringSubmatch(text)
+		require.Len(t, match, 2)
+		codes = append(codes, match[1])
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	require.Len(t, codes, 2)
+
+	assert.ErrorIs(t, us.VerifyEmail(ctx, codes[0]), ErrInvalidVerifyToken, "a resent code replaces the old one")
+	require.NoError(t, us.VerifyEmail(ctx, codes[1]))
+
+	var eventID int
+	err = db.QueryRow(`INSERT INTO events (title, event_description, event_date, event_status,
+		total_place, reservation_period, booking_confirmation, created_at)
+		VALUES ('Resend test', '', $1, 'published', 10, $2, true, now()) RETURNING event_id`,
+		time.Now().Add(24*time.Hour), int64(time.Hour)).Scan(&eventID)
+	require.NoError(t, err)
+	insertTicketType(t, db, eventID, model.DefaultTicketTypeName, 10, 0)
+
+	err = NewBookingService(storage, nil).Book(ctx, model.BookingInCreate{UserID: claims.UserID, EventID: eventID, Quantity: 3})
+	require.NoError(t, err)
+
+	occupied, err := storage.Booking.GetOccupiedPlace(ctx, eventID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, occupied, "the booking must be committed after the verification check")
+}
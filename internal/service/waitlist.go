@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wb-go/wbf/zlog"
+
+	"EventBooker/internal/model"
+	"EventBooker/internal/repository"
+)
+
+type WaitlistService interface {
+	Join(ctx context.Context, w model.WaitlistInCreate) error
+	Leave(ctx context.Context, eventID, userID int) error
+	GetByUserID(ctx context.Context, userID int) ([]model.WaitlistInResponse, error)
+	Promote(ctx context.Context, eventID int) error
+}
+
+type waitlistService struct {
+	storage *repository.Storage
+}
+
+func NewWaitlistService(s *repository.Storage) WaitlistService {
+	return &waitlistService{storage: s}
+}
+
+func (ws *waitlistService) Join(ctx context.Context, w model.WaitlistInCreate) error {
+	if w.Quantity == 0 {
+		w.Quantity = 1
+	}
+	if w.Quantity < 0 {
+		return ErrInvalidQuantity
+	}
+
+	return ws.storage.WithTx(ctx, func(s *repository.Storage) error {
+		err := checkCanBook(ctx, s, w.UserID)
+		if err != nil {
+			return err
+		}
+
+		event, err := s.Event.GetByIDForUpdate(ctx, w.EventID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.WaitlistService.Join error: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if event.Status == model.EventSratusCanceled {
+			return ErrEventCanceled
+		}
+
+		if event.Status != model.EventStatusPublished {
+			return ErrEventNotOnSale
+		}
+
+		if event.EventDate.Before(time.Now()) {
+			return ErrEventAlreadyPassed
+		}
+
+		// Released seats go back on the seat map, there is no queue for them.
+		if event.VenueID != nil {
+			return ErrWaitlistSeated
+		}
+
+		tier, err := resolveTicketType(ctx, s, w.EventID, w.TicketTypeID)
+		if err != nil {
+			if !errors.Is(err, ErrTicketTypeNotFound) && !errors.Is(err, ErrTicketTypeRequired) {
+				zlog.Logger.Error().Msgf("service.WaitlistService.Join error: %v", err)
+			}
+			return err
+		}
+		w.TicketTypeID = tier.ID
+
+		if w.Quantity > tier.Capacity {
+			return ErrQuantityExceedsCapacity
+		}
+
+		if tier.Occupied+w.Quantity <= tier.Capacity {
+			return ErrSeatsAvailable
+		}
+
+		waiting, err := s.Waitlist.IsWaiting(ctx, w.EventID, w.UserID)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.WaitlistService.Join error: %v", err)
+			return err
+		}
+		if waiting {
+			return ErrAlreadyInWaitlist
+		}
+
+		err = s.Waitlist.Create(ctx, w)
+		if err != nil {
+			zlog.Logger.Error().Msgf("service.WaitlistService.Join error: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (ws *waitlistService) Leave(ctx context.Context, eventID, userID int) error {
+	return ws.storage.Waitlist.Cancel(ctx, eventID, userID)
+}
+
+func (ws *waitlistService) GetByUserID(ctx context.Context, userID int) ([]model.WaitlistInResponse, error) {
+	waitlistInRepo, err := ws.storage.Waitlist.GetListByUser(ctx, userID)
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.WaitlistService.GetByUserID error: %v", err)
+		return nil, err
+	}
+
+	waitlistInResponse := make([]model.WaitlistInResponse, 0, len(waitlistInRepo))
+	for _, w := range waitlistInRepo {
+		waitlistInResponse = append(waitlistInResponse, model.WaitlistInResponse{
+			ID:           w.ID,
+			EventID:      w.EventID,
+			Quantity:     w.Quantity,
+			TicketTypeID: w.TicketTypeID,
+			Status:       w.Status,
+			Position:     w.Position,
+			CreatedAt:    w.CreatedAt,
+			EventTitle:   w.EventTitle,
+			EventDate:    w.EventDate,
+		})
+	}
+	return waitlistInResponse, nil
+}
+
+func (ws *waitlistService) Promote(ctx context.Context, eventID int) error {
+	err := ws.storage.WithTx(ctx, func(s *repository.Storage) error {
+		return promoteWaitlist(ctx, s, eventID)
+	})
+	if err != nil {
+		zlog.Logger.Error().Msgf("service.WaitlistService.Promote error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// promoteWaitlist hands freed seats to the ticket type queues in FIFO order.
+// Every promoted entry gets a pending booking at the current tier price with
+// a fresh reservation period. Each queue is strict: if its head does not fit,
+// nobody behind it is promoted. Notifications go to the outbox, so must be
+// called inside a transaction.
+func promoteWaitlist(ctx context.Context, s *repository.Storage, eventID int) error {
+	event, err := s.Event.GetByIDForUpdate(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if event.Status != model.EventStatusPublished || event.EventDate.Before(time.Now()) {
+		return nil
+	}
+
+	tiers, err := s.TicketType.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	for _, tier := range tiers {
+		err = promoteTicketType(ctx, s, event, tier)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func promoteTicketType(ctx context.Context, s *repository.Storage, event model.EventInRepo, tier model.TicketTypeInRepo) error {
+	for {
+		next, err := s.Waitlist.GetNext(ctx, tier.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if tier.Occupied+next.Quantity > tier.Capacity {
+			return nil
+		}
+
+		bookID, err := s.Booking.Create(ctx, model.BookingInCreate{
+			UserID:       next.UserID,
+			EventID:      event.ID,
+			Quantity:     next.Quantity,
+			TicketTypeID: tier.ID,
+			ExpiresAt:    time.Now().Add(event.ReservationPeriod),
+			UnitPrice:    tier.Price,
+			Currency:     tier.Currency,
+		}, model.StatusBookingPending)
+		if err != nil {
+			return err
+		}
+		tier.Occupied += next.Quantity
+
+		err = s.Waitlist.UpdateStatus(ctx, model.StatusWaitlistPromoted, next.ID)
+		if err != nil {
+			return err
+		}
+
+		b, err := s.Booking.GetForTG(ctx, bookID)
+		if err != nil {
+			return err
+		}
+
+		err = enqueueNotifications(ctx, s, []model.NotificationInCreate{buildPromotionMessage(b, event.ReservationPeriod)})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func buildPromotionMessage(b model.BookingGetForTG, reservationPeriod time.Duration) model.NotificationInCreate {
+	var builder strings.Builder
+	builder.WriteString("Освободились места из листа ожидания!\n")
+	builder.WriteString(fmt.Sprintf("Бронирование %d создано.\n", b.ID))
+	builder.WriteString(fmt.Sprintf("Количество мест: %d\n", b.Quantity))
+	builder.WriteString(fmt.Sprintf("Событие: %s\n", b.TitleEvent))
+	builder.WriteString(fmt.Sprintf("Время события: %s\n", b.EventDate))
+	builder.WriteString(fmt.Sprintf("Подтвердите бронь в течение %s\n", reservationPeriod))
+	return model.NotificationInCreate{
+		UserID:    b.UserID,
+		EventType: model.NotificationTypeBookingCreated,
+		Subject:   fmt.Sprintf("Освободились места на «%s»", b.TitleEvent),
+		Text:      builder.String(),
+	}
+}
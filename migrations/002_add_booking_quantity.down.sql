@@ -0,0 +1 @@
+ALTER TABLE booking DROP COLUMN IF EXISTS quantity;
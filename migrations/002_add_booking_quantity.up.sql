@@ -0,0 +1,2 @@
+ALTER TABLE booking
+    ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS waitlist (
+    waitlist_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    event_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'promoted', 'cancelled')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (event_id) REFERENCES events (event_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_waitlist_user_event_waiting ON waitlist(user_id, event_id) WHERE status = 'waiting';
+CREATE INDEX idx_waitlist_event_queue ON waitlist(event_id, created_at, waitlist_id) WHERE status = 'waiting';
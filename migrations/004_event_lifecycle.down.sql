@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_events_status_date;
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_event_status_check;
+
+UPDATE events SET event_status = 'pending' WHERE event_status IN ('draft', 'published', 'sales_closed');
+UPDATE events SET event_status = 'expired' WHERE event_status = 'finished';
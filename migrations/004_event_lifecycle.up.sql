@@ -0,0 +1,8 @@
+UPDATE events SET event_status = 'published' WHERE event_status = 'pending';
+UPDATE events SET event_status = 'finished' WHERE event_status = 'expired';
+
+ALTER TABLE events
+    ADD CONSTRAINT events_event_status_check
+    CHECK (event_status IN ('draft', 'published', 'sales_closed', 'finished', 'canceled'));
+
+CREATE INDEX idx_events_status_date ON events(event_status, event_date);
@@ -0,0 +1,3 @@
+ALTER TABLE booking
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,3 @@
+ALTER TABLE booking
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS status_reason TEXT;
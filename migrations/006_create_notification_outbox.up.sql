@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    notification_id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    message TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_notification_outbox_due ON notification_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notification_outbox_status ON notification_outbox(status, created_at, notification_id);
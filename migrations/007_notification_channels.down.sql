@@ -0,0 +1,13 @@
+DELETE FROM notification_outbox WHERE channel <> 'telegram';
+
+ALTER TABLE notification_outbox ADD COLUMN chat_id BIGINT;
+
+UPDATE notification_outbox SET chat_id = recipient::BIGINT;
+
+ALTER TABLE notification_outbox
+    ALTER COLUMN chat_id SET NOT NULL,
+    DROP COLUMN user_id,
+    DROP COLUMN event_type,
+    DROP COLUMN channel,
+    DROP COLUMN recipient,
+    DROP COLUMN subject;
@@ -0,0 +1,13 @@
+ALTER TABLE notification_outbox
+    ADD COLUMN user_id INTEGER REFERENCES users(user_id) ON DELETE CASCADE,
+    ADD COLUMN event_type VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN channel VARCHAR(20) NOT NULL DEFAULT 'telegram' CHECK (channel IN ('telegram', 'email')),
+    ADD COLUMN recipient TEXT,
+    ADD COLUMN subject TEXT NOT NULL DEFAULT '';
+
+UPDATE notification_outbox SET recipient = chat_id::TEXT;
+
+ALTER TABLE notification_outbox
+    ALTER COLUMN recipient SET NOT NULL,
+    ALTER COLUMN channel DROP DEFAULT,
+    DROP COLUMN chat_id;
@@ -0,0 +1,2 @@
+DROP TABLE reminder_deliveries;
+DROP TABLE event_reminders;
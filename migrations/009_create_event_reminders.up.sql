@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS event_reminders (
+    event_id INTEGER NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    remind_before BIGINT NOT NULL CHECK (remind_before > 0),
+    PRIMARY KEY (event_id, remind_before)
+);
+
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    booking_id INTEGER NOT NULL REFERENCES booking(booking_id) ON DELETE CASCADE,
+    remind_before BIGINT NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (booking_id, remind_before)
+);
+
+-- Events created before reminders existed get the default schedule.
+INSERT INTO event_reminders (event_id, remind_before)
+SELECT event_id, r.remind_before
+FROM events
+CROSS JOIN (VALUES (86400000000000), (3600000000000)) AS r(remind_before)
+WHERE event_status IN ('draft', 'published', 'sales_closed');
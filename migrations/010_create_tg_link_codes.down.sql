@@ -0,0 +1 @@
+DROP TABLE tg_link_codes;
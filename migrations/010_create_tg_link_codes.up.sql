@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS tg_link_codes (
+    code_hash VARCHAR(64) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_tg_link_codes_user ON tg_link_codes(user_id);
+
+-- Chat ids used to be typed in at registration and were never verified, so
+-- any existing link may point at someone else's chat. Every account has to
+-- link its chat again with a one-time code.
+UPDATE users SET tg_chatid = NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attempt_key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
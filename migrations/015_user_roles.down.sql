@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_events_organizer_id;
+ALTER TABLE events DROP COLUMN organizer_id;
+
+UPDATE users SET role = 'user' WHERE role = 'organizer';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check
+    CHECK (role IN ('user', 'admin'));
@@ -0,0 +1,10 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check
+    CHECK (role IN ('user', 'organizer', 'admin'));
+
+-- Events created before organizers existed have no owner and stay manageable
+-- by admins only.
+ALTER TABLE events ADD COLUMN organizer_id INTEGER REFERENCES users(user_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_events_organizer_id ON events(organizer_id);
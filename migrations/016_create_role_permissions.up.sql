@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(30) NOT NULL CHECK (role IN ('user', 'organizer', 'admin')),
+    permission VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('organizer', 'events:write'),
+    ('admin', 'events:write'),
+    ('admin', 'events:manage_all'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage'),
+    ('admin', 'notifications:manage');
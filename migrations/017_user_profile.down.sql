@@ -0,0 +1,10 @@
+ALTER TABLE booking DROP CONSTRAINT IF EXISTS booking_user_id_fkey;
+ALTER TABLE booking
+    ADD CONSTRAINT booking_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE;
+
+ALTER TABLE users
+    DROP COLUMN deleted_at,
+    DROP COLUMN locale,
+    DROP COLUMN phone,
+    DROP COLUMN display_name;
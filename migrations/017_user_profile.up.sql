@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100),
+    ADD COLUMN phone VARCHAR(30),
+    ADD COLUMN locale VARCHAR(10),
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Deleted accounts are anonymized rather than removed, and removing a user
+-- row must never take its booking history along.
+ALTER TABLE booking DROP CONSTRAINT IF EXISTS booking_user_id_fkey;
+ALTER TABLE booking
+    ADD CONSTRAINT booking_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE RESTRICT;
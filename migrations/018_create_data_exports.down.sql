@@ -0,0 +1 @@
+DROP TABLE data_exports;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    export_id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    requested_by INTEGER REFERENCES users(user_id) ON DELETE SET NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('json', 'zip')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    payload BYTEA,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_data_exports_pending ON data_exports(created_at, export_id) WHERE status = 'pending';
+CREATE INDEX idx_data_exports_user ON data_exports(user_id);
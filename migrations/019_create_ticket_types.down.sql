@@ -0,0 +1,12 @@
+DROP INDEX idx_waitlist_ticket_type_queue;
+CREATE INDEX idx_waitlist_event_queue ON waitlist(event_id, created_at, waitlist_id) WHERE status = 'waiting';
+
+ALTER TABLE waitlist
+    DROP COLUMN ticket_type_id;
+
+ALTER TABLE booking
+    DROP COLUMN currency,
+    DROP COLUMN unit_price,
+    DROP COLUMN ticket_type_id;
+
+DROP TABLE ticket_types;
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS ticket_types (
+    ticket_type_id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    price BIGINT NOT NULL CHECK (price >= 0),
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (event_id, name)
+);
+
+-- Existing events get one free tier holding all of their seats.
+INSERT INTO ticket_types (event_id, name, capacity, price, currency, created_at)
+SELECT event_id, 'standard', total_place, 0, 'RUB', now()
+FROM events;
+
+-- Prices are stored in minor units as they were at the moment of booking.
+ALTER TABLE booking
+    ADD COLUMN ticket_type_id INTEGER REFERENCES ticket_types(ticket_type_id),
+    ADD COLUMN unit_price BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+UPDATE booking b
+SET ticket_type_id = t.ticket_type_id
+FROM ticket_types t
+WHERE t.event_id = b.event_id;
+
+ALTER TABLE booking
+    ALTER COLUMN ticket_type_id SET NOT NULL,
+    ALTER COLUMN unit_price DROP DEFAULT,
+    ALTER COLUMN currency DROP DEFAULT;
+
+CREATE INDEX idx_booking_ticket_type ON booking(ticket_type_id);
+
+ALTER TABLE waitlist
+    ADD COLUMN ticket_type_id INTEGER REFERENCES ticket_types(ticket_type_id) ON DELETE CASCADE;
+
+UPDATE waitlist w
+SET ticket_type_id = t.ticket_type_id
+FROM ticket_types t
+WHERE t.event_id = w.event_id;
+
+ALTER TABLE waitlist
+    ALTER COLUMN ticket_type_id SET NOT NULL;
+
+DROP INDEX idx_waitlist_event_queue;
+CREATE INDEX idx_waitlist_ticket_type_queue ON waitlist(ticket_type_id, created_at, waitlist_id) WHERE status = 'waiting';
@@ -0,0 +1,17 @@
+-- Payments are money history: deleting a booking, or the event it belongs
+-- to, must not erase them.
+CREATE TABLE IF NOT EXISTS payments (
+    payment_id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES booking(booking_id) ON DELETE RESTRICT,
+    provider VARCHAR(32) NOT NULL,
+    provider_payment_id VARCHAR(128) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed', 'refunded')),
+    confirmation_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (provider, provider_payment_id)
+);
+
+CREATE INDEX idx_payments_booking ON payments(booking_id);
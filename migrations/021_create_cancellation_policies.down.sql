@@ -0,0 +1,8 @@
+UPDATE payments SET status = 'succeeded' WHERE status = 'partially_refunded';
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'succeeded', 'failed', 'refunded'));
+
+DROP TABLE refunds;
+DROP TABLE cancellation_refund_rules;
+DROP TABLE cancellation_policies;
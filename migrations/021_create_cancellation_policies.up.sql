@@ -0,0 +1,30 @@
+-- Events without a row keep the old behaviour: free cancellation at any time.
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    event_id INTEGER PRIMARY KEY REFERENCES events(event_id) ON DELETE CASCADE,
+    free_cancel_before BIGINT NOT NULL CHECK (free_cancel_before >= 0),
+    no_cancel_within BIGINT NOT NULL CHECK (no_cancel_within >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS cancellation_refund_rules (
+    event_id INTEGER NOT NULL REFERENCES cancellation_policies(event_id) ON DELETE CASCADE,
+    cancel_before BIGINT NOT NULL CHECK (cancel_before > 0),
+    refund_percent INTEGER NOT NULL CHECK (refund_percent BETWEEN 0 AND 100),
+    PRIMARY KEY (event_id, cancel_before)
+);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    refund_id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payments(payment_id) ON DELETE RESTRICT,
+    booking_id INTEGER NOT NULL REFERENCES booking(booking_id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    refund_percent INTEGER NOT NULL CHECK (refund_percent BETWEEN 1 AND 100),
+    reason VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_refunds_booking ON refunds(booking_id);
+
+ALTER TABLE payments DROP CONSTRAINT payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'succeeded', 'failed', 'refunded', 'partially_refunded'));
@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission = 'promo_codes:manage';
+
+ALTER TABLE booking
+    DROP COLUMN discount;
+
+DROP TABLE promo_redemptions;
+DROP TABLE promo_code_ticket_types;
+DROP TABLE promo_code_events;
+DROP TABLE promo_codes;
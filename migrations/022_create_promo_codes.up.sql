@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS promo_codes (
+    promo_code_id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value BIGINT NOT NULL CHECK (discount_value > 0),
+    currency VARCHAR(3),
+    max_uses INTEGER CHECK (max_uses > 0),
+    max_uses_per_user INTEGER CHECK (max_uses_per_user > 0),
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (max_uses IS NULL OR used_count <= max_uses),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- A code without rows in a restriction table applies to every event or tier.
+CREATE TABLE IF NOT EXISTS promo_code_events (
+    promo_code_id INTEGER NOT NULL REFERENCES promo_codes(promo_code_id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    PRIMARY KEY (promo_code_id, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS promo_code_ticket_types (
+    promo_code_id INTEGER NOT NULL REFERENCES promo_codes(promo_code_id) ON DELETE CASCADE,
+    ticket_type_id INTEGER NOT NULL REFERENCES ticket_types(ticket_type_id) ON DELETE CASCADE,
+    PRIMARY KEY (promo_code_id, ticket_type_id)
+);
+
+-- Redemptions keep their code: a code in use cannot be deleted.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    redemption_id SERIAL PRIMARY KEY,
+    promo_code_id INTEGER NOT NULL REFERENCES promo_codes(promo_code_id),
+    booking_id INTEGER NOT NULL UNIQUE REFERENCES booking(booking_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    discount BIGINT NOT NULL CHECK (discount > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_promo_redemptions_user ON promo_redemptions(promo_code_id, user_id);
+
+ALTER TABLE booking
+    ADD COLUMN discount BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'promo_codes:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,10 @@
+DELETE FROM role_permissions WHERE permission = 'venues:manage';
+
+DROP TABLE booking_seats;
+DROP TABLE event_seats;
+
+ALTER TABLE events
+    DROP COLUMN venue_id;
+
+DROP TABLE venue_seats;
+DROP TABLE venues;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS venues (
+    venue_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS venue_seats (
+    seat_id SERIAL PRIMARY KEY,
+    venue_id INTEGER NOT NULL REFERENCES venues(venue_id) ON DELETE CASCADE,
+    section VARCHAR(50) NOT NULL,
+    seat_row VARCHAR(10) NOT NULL,
+    seat_number INTEGER NOT NULL CHECK (seat_number > 0),
+    accessible BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE (venue_id, section, seat_row, seat_number)
+);
+
+-- Events with a venue use assigned seating: every seat of the venue is sold
+-- through exactly one ticket type.
+ALTER TABLE events
+    ADD COLUMN venue_id INTEGER REFERENCES venues(venue_id);
+
+CREATE TABLE IF NOT EXISTS event_seats (
+    event_id INTEGER NOT NULL REFERENCES events(event_id) ON DELETE CASCADE,
+    seat_id INTEGER NOT NULL REFERENCES venue_seats(seat_id),
+    ticket_type_id INTEGER NOT NULL REFERENCES ticket_types(ticket_type_id),
+    PRIMARY KEY (event_id, seat_id)
+);
+
+-- A seat is held by a pending booking until its expires_at and sold to a
+-- confirmed one. Cancelled and expired bookings release it.
+CREATE TABLE IF NOT EXISTS booking_seats (
+    booking_id INTEGER NOT NULL REFERENCES booking(booking_id) ON DELETE CASCADE,
+    event_id INTEGER NOT NULL,
+    seat_id INTEGER NOT NULL,
+    PRIMARY KEY (booking_id, seat_id),
+    FOREIGN KEY (event_id, seat_id) REFERENCES event_seats(event_id, seat_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_booking_seats_event ON booking_seats(event_id, seat_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'venues:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+-- Emails are looked up lower-cased, so the lookup needs an index on the
+-- expression. It is not unique: accounts registered before emails were
+-- normalized may differ only in case.
+CREATE INDEX idx_users_email_lower ON users(lower(email));